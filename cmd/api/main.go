@@ -13,8 +13,21 @@ import (
 	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
 	"github.com/brnocorreia/api-meu-buzufba/internal/infra/mail"
 	"github.com/brnocorreia/api-meu-buzufba/internal/infra/server"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/alert"
 	"github.com/brnocorreia/api-meu-buzufba/internal/modules/auth"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/eta"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/favorite"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/fleet"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/gtfs"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/lostfound"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/occupancy"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/planner"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/reminder"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
 	"github.com/brnocorreia/api-meu-buzufba/internal/modules/session"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/timetable"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/tracking"
 	"github.com/brnocorreia/api-meu-buzufba/internal/modules/user"
 	"github.com/brnocorreia/api-meu-buzufba/pkg/cache"
 	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
@@ -60,6 +73,17 @@ func main() {
 	// Repositories
 	userRepo := user.NewRepo(pgConn.DB())
 	sessionRepo := session.NewRepo(pgConn.DB())
+	authRepo := auth.NewRepo(pgConn.DB())
+	routeRepo := route.NewRepo(pgConn.DB())
+	calendarRepo := calendar.NewRepo(pgConn.DB())
+	alertRepo := alert.NewRepo(pgConn.DB())
+	favoriteRepo := favorite.NewRepo(pgConn.DB())
+	reminderRepo := reminder.NewRepo(pgConn.DB())
+	lostItemRepo := lostfound.NewRepo(pgConn.DB())
+	positionRepo := tracking.NewRepo(pgConn.DB())
+	segmentRepo := eta.NewRepo(pgConn.DB())
+	fleetRepo := fleet.NewRepo(pgConn.DB())
+	occupancyRepo := occupancy.NewRepo(pgConn.DB())
 
 	// Services
 	mailService := mail.New(ctx, mail.Config{
@@ -74,19 +98,134 @@ func main() {
 		UserRepo:    userRepo,
 		Cache:       cache,
 		SecretKey:   cfg.JWTSecretKey,
+		MaxSessions: cfg.MaxSessions,
 	})
 	authService := auth.NewService(auth.ServiceConfig{
+		AuthRepo:       authRepo,
 		UserRepo:       userRepo,
 		SessionService: sessionService,
 		SessionRepo:    sessionRepo,
 		Mailer:         mailService,
 		Cache:          cache,
 		SecretKey:      cfg.JWTSecretKey,
+		FrontEndURL:    cfg.FrontEndURL,
+	})
+	calendarService := calendar.NewService(calendar.ServiceConfig{
+		CalendarRepo: calendarRepo,
+	})
+	alertService := alert.NewService(alert.ServiceConfig{
+		AlertRepo: alertRepo,
+		Cache:     cache,
+	})
+	occupancyService := occupancy.NewService(occupancy.ServiceConfig{
+		OccupancyRepo: occupancyRepo,
+		Cache:         cache,
+	})
+	routeService := route.NewService(route.ServiceConfig{
+		RouteRepo:         routeRepo,
+		CalendarService:   calendarService,
+		AlertProvider:     alertService,
+		OccupancyProvider: occupancyService,
+	})
+	plannerService := planner.NewService(planner.ServiceConfig{
+		RouteService:    routeService,
+		CalendarService: calendarService,
+	})
+	favoriteService := favorite.NewService(favorite.ServiceConfig{
+		FavoriteRepo: favoriteRepo,
+		RouteService: routeService,
+		Cache:        cache,
+	})
+	reminderService := reminder.NewService(reminder.ServiceConfig{
+		ReminderRepo: reminderRepo,
+		RouteService: routeService,
+	})
+	lostFoundService := lostfound.NewService(lostfound.ServiceConfig{
+		LostItemRepo: lostItemRepo,
+	})
+	trackingService := tracking.NewService(tracking.ServiceConfig{
+		PositionRepo: positionRepo,
+		RouteService: routeService,
+		Cache:        cache,
+	})
+	etaService := eta.NewService(eta.ServiceConfig{
+		SegmentRepo:     segmentRepo,
+		RouteService:    routeService,
+		TrackingService: trackingService,
+		Cache:           cache,
+	})
+	fleetService := fleet.NewService(fleet.ServiceConfig{
+		FleetRepo:    fleetRepo,
+		RouteService: routeService,
+	})
+	gtfsService := gtfs.NewService(gtfs.ServiceConfig{
+		RouteService:    routeService,
+		CalendarService: calendarService,
+		AlertService:    alertService,
+		TrackingService: trackingService,
+		EtaService:      etaService,
+		Importer:        timetable.NewImporter(pgConn.DB()),
+		Cache:           cache,
+	})
+
+	liveHub := tracking.NewHub(tracking.HubConfig{
+		Cache: cache,
 	})
 
 	// Handlers
 	session.NewHandler(sessionService, cfg.JWTSecretKey).Register(r)
-	auth.NewHandler(authService, cfg.JWTSecretKey).Register(r)
+	auth.NewHandler(authService, cfg.JWTSecretKey, sessionService).Register(r)
+	route.NewHandler(routeService, cfg.JWTSecretKey, sessionService).Register(r)
+	calendar.NewHandler(calendarService, cfg.JWTSecretKey, sessionService).Register(r)
+	alert.NewHandler(alertService, cfg.JWTSecretKey, sessionService).Register(r)
+	gtfs.NewHandler(gtfsService, cfg.JWTSecretKey, sessionService).Register(r)
+	planner.NewHandler(plannerService).Register(r)
+	favorite.NewHandler(favoriteService, cfg.JWTSecretKey, sessionService).Register(r)
+	reminder.NewHandler(reminderService, cfg.JWTSecretKey, sessionService).Register(r)
+	lostfound.NewHandler(lostFoundService, cfg.JWTSecretKey, sessionService).Register(r)
+	tracking.NewHandler(trackingService, liveHub, cfg.JWTSecretKey, sessionService).Register(r)
+	eta.NewHandler(etaService).Register(r)
+	fleet.NewHandler(fleetService, cfg.JWTSecretKey, sessionService).Register(r)
+	occupancy.NewHandler(occupancyService, cfg.JWTSecretKey, sessionService).Register(r)
+
+	// Background jobs
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+
+	go reminder.NewScheduler(reminder.SchedulerConfig{
+		ReminderRepo: reminderRepo,
+		UserRepo:     userRepo,
+		RouteService: routeService,
+		Cache:        cache,
+		Notifiers: map[string]reminder.Notifier{
+			reminder.ChannelEmail: reminder.NewEmailNotifier(mailService),
+		},
+	}).Run(jobsCtx)
+	go liveHub.Run(jobsCtx)
+	go tracking.NewPruner(tracking.PrunerConfig{
+		PositionRepo: positionRepo,
+	}).Run(jobsCtx)
+
+	if cfg.TrackingDeviceKey != "" {
+		listener := tracking.NewListener(tracking.ListenerConfig{
+			TrackingService: trackingService,
+			DeviceKey:       cfg.TrackingDeviceKey,
+		})
+		if cfg.TrackingUDPAddr != "" {
+			go func() {
+				if err := listener.ListenUDP(jobsCtx, cfg.TrackingUDPAddr); err != nil {
+					logging.Error("udp position listener failed", err, zap.String("journey", "main"))
+				}
+			}()
+		}
+		if cfg.TrackingTCPAddr != "" {
+			go func() {
+				if err := listener.ListenTCP(jobsCtx, cfg.TrackingTCPAddr); err != nil {
+					logging.Error("tcp position listener failed", err, zap.String("journey", "main"))
+				}
+			}()
+		}
+	}
 
 	srv := server.New(server.Config{
 		Port:         cfg.Port,
@@ -96,6 +235,8 @@ func main() {
 		Router:       r,
 	})
 
+	// Ends the background jobs and the live streams, which would hold the shutdown
+	srv.RegisterOnShutdown(stopJobs)
 	shutdoewnErr := srv.GracefulShutdown(ctx, time.Second*30)
 
 	err = srv.Start()
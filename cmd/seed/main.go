@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/config"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/pg"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/gtfs"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/timetable"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+func main() {
+	file := flag.String("file", "data/timetable.yaml", "path to the timetable file (.yaml, .yml or .json)")
+	gtfsFile := flag.String("gtfs", "", "path to a GTFS zip to import instead of the timetable file")
+	dryRun := flag.Bool("dry-run", false, "report the changes to the routes without writing them")
+	replace := flag.Bool("replace", false, "remove the routes missing from the imported network")
+	flag.Parse()
+
+	ctx := context.Background()
+	cfg := config.GetConfig()
+
+	tt, source, err := load(*file, *gtfsFile)
+	if err != nil {
+		logging.Error("failed to load timetable", err, zap.String("journey", "seed"))
+		os.Exit(1)
+	}
+
+	pgConn, err := pg.NewConnection(ctx, cfg.PostgresDSN)
+	if err != nil {
+		logging.Error("failed to connect to database", err, zap.String("journey", "seed"))
+		os.Exit(1)
+	}
+	defer pgConn.Close()
+
+	err = pgConn.Migrate()
+	if err != nil {
+		logging.Error("failed to migrate database", err, zap.String("journey", "seed"))
+		os.Exit(1)
+	}
+
+	res, err := timetable.NewImporter(pgConn.DB()).Import(ctx, tt, timetable.Options{
+		DryRun:  *dryRun,
+		Replace: *replace,
+	})
+	if err != nil {
+		// The error is already being logged by the importer
+		os.Exit(1)
+	}
+
+	if *dryRun {
+		logging.Info("dry run finished, nothing was written",
+			zap.String("journey", "seed"),
+			zap.String("file", source),
+			zap.Strings("added", res.Diff.Added),
+			zap.Any("changed", res.Diff.Changed),
+			zap.Strings("removed", res.Diff.Removed),
+			zap.Int("unchanged", res.Diff.Unchanged))
+		return
+	}
+
+	logging.Info("database seeded successfully",
+		zap.String("journey", "seed"),
+		zap.String("file", source))
+}
+
+// load reads the GTFS zip when one is given, the timetable file otherwise
+func load(file, gtfsFile string) (*timetable.Timetable, string, error) {
+	if gtfsFile == "" {
+		tt, err := timetable.Load(file)
+		return tt, file, err
+	}
+
+	archive, err := os.ReadFile(gtfsFile)
+	if err != nil {
+		return nil, gtfsFile, err
+	}
+
+	report, err := gtfs.Validate(archive)
+	if err != nil {
+		return nil, gtfsFile, err
+	}
+	for _, w := range report.Warnings {
+		logging.Info("gtfs feed warning", zap.String("journey", "seed"), zap.String("warning", w))
+	}
+	if !report.Valid() {
+		for _, e := range report.Errors {
+			logging.Info("gtfs feed error", zap.String("journey", "seed"), zap.String("error", e))
+		}
+		return nil, gtfsFile, errors.New("invalid gtfs feed")
+	}
+
+	tt, skipped, err := gtfs.ToTimetable(archive)
+	if skipped > 0 {
+		logging.Info("gtfs trips left out of the import",
+			zap.String("journey", "seed"),
+			zap.Int("skipped", skipped))
+	}
+	return tt, gtfsFile, err
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type CreateAlert struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Kind        string     `json:"kind"`
+	Severity    string     `json:"severity"`
+	RouteIDs    []string   `json:"route_ids"`
+	StopIDs     []string   `json:"stop_ids"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+}
+
+type AlertsQuery struct {
+	RouteID  string
+	StopID   string
+	Kind     string
+	Severity string
+	// Status is one of active, upcoming, ended or all
+	Status string
+}
+
+type AlertResponse struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Kind        string     `json:"kind"`
+	Severity    string     `json:"severity"`
+	RouteIDs    []string   `json:"route_ids"`
+	StopIDs     []string   `json:"stop_ids"`
+	StartsAt    time.Time  `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+type CalendarResponse struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Days      []string `json:"days"`
+	TermKind  string   `json:"term_kind"`
+	StartDate *string  `json:"start_date"`
+	EndDate   *string  `json:"end_date"`
+}
+
+type CreateCalendarException struct {
+	CalendarID  *string `json:"calendar_id"`
+	Date        string  `json:"date"`
+	Type        string  `json:"type"`
+	Description string  `json:"description"`
+}
+
+type CalendarExceptionResponse struct {
+	ID          string  `json:"id"`
+	CalendarID  *string `json:"calendar_id"`
+	Date        string  `json:"date"`
+	Type        string  `json:"type"`
+	Description string  `json:"description"`
+}
+
+type CreateAcademicTerm struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type AcademicTermResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
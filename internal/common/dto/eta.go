@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+type StopETAResponse struct {
+	LocationID string    `json:"location_id"`
+	Name       string    `json:"name"`
+	StopOrder  int       `json:"stop_order"`
+	ArrivesAt  time.Time `json:"arrives_at"`
+	// Minutes is how long until the bus arrives, rounded to the nearest minute
+	Minutes int `json:"minutes"`
+}
+
+type TripETAResponse struct {
+	// Source is "live" when predicted from a vehicle position, "schedule" when taken from the timetable
+	Source string `json:"source"`
+	// VehicleID is set for live trips
+	VehicleID *string `json:"vehicle_id"`
+	// DepartureID is the timetable departure the trip runs, when known
+	DepartureID *string           `json:"departure_id"`
+	Stops       []StopETAResponse `json:"stops"`
+}
+
+type RouteETAResponse struct {
+	RouteID   string            `json:"route_id"`
+	RouteName string            `json:"route_name"`
+	Trips     []TripETAResponse `json:"trips"`
+}
+
+type StopArrivalETAResponse struct {
+	RouteID     string    `json:"route_id"`
+	RouteName   string    `json:"route_name"`
+	Source      string    `json:"source"`
+	VehicleID   *string   `json:"vehicle_id"`
+	DepartureID *string   `json:"departure_id"`
+	StopOrder   int       `json:"stop_order"`
+	ArrivesAt   time.Time `json:"arrives_at"`
+	Minutes     int       `json:"minutes"`
+}
+
+type StopETAsResponse struct {
+	Stop     LocationResponse         `json:"stop"`
+	Arrivals []StopArrivalETAResponse `json:"arrivals"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type CreateFavorite struct {
+	RouteID *string `json:"route_id"`
+	StopID  *string `json:"stop_id"`
+}
+
+type ReorderFavorites struct {
+	IDs []string `json:"ids"`
+}
+
+type FavoriteResponse struct {
+	ID        string                       `json:"id"`
+	Kind      string                       `json:"kind"`
+	Position  int                          `json:"position"`
+	Route     *RouteNextDeparturesResponse `json:"route"`
+	Stop      *StopNextDeparturesResponse  `json:"stop"`
+	CreatedAt time.Time                    `json:"created_at"`
+}
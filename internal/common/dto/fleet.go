@@ -0,0 +1,79 @@
+package dto
+
+import "time"
+
+type CreateVehicle struct {
+	Plate                string `json:"plate"`
+	Capacity             int    `json:"capacity"`
+	WheelchairAccessible bool   `json:"wheelchair_accessible"`
+	LowFloor             bool   `json:"low_floor"`
+	// Status is one of active, maintenance or retired, defaulting to active
+	Status string `json:"status"`
+}
+
+type VehicleResponse struct {
+	ID                   string    `json:"id"`
+	Plate                string    `json:"plate"`
+	Capacity             int       `json:"capacity"`
+	WheelchairAccessible bool      `json:"wheelchair_accessible"`
+	LowFloor             bool      `json:"low_floor"`
+	Status               string    `json:"status"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+type CreateDriver struct {
+	Name          string  `json:"name"`
+	LicenseNumber string  `json:"license_number"`
+	Phone         *string `json:"phone"`
+	// UserID links the driver to their account in the app
+	UserID *string `json:"user_id"`
+	// Active defaults to true
+	Active *bool `json:"active"`
+}
+
+type DriverResponse struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	LicenseNumber string    `json:"license_number"`
+	Phone         *string   `json:"phone"`
+	UserID        *string   `json:"user_id"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type CreateAssignment struct {
+	// Date is the service date, formatted as YYYY-MM-DD
+	Date        string `json:"date"`
+	RouteID     string `json:"route_id"`
+	DepartureID string `json:"departure_id"`
+	VehicleID   string `json:"vehicle_id"`
+	DriverID    string `json:"driver_id"`
+}
+
+type UpdateAssignment struct {
+	VehicleID string `json:"vehicle_id"`
+	DriverID  string `json:"driver_id"`
+}
+
+type AssignmentsQuery struct {
+	// Date defaults to today
+	Date      *time.Time
+	RouteID   string
+	VehicleID string
+	DriverID  string
+}
+
+type AssignmentResponse struct {
+	ID          string    `json:"id"`
+	Date        string    `json:"date"`
+	RouteID     string    `json:"route_id"`
+	DepartureID string    `json:"departure_id"`
+	VehicleID   string    `json:"vehicle_id"`
+	DriverID    string    `json:"driver_id"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
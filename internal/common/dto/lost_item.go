@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+type CreateLostItem struct {
+	// Kind is either "lost" or "found"
+	Kind        string     `json:"kind"`
+	Description string     `json:"description"`
+	RouteID     string     `json:"route_id"`
+	OccurredAt  *time.Time `json:"occurred_at"`
+	PhotoURL    *string    `json:"photo_url"`
+}
+
+type HoldLostItem struct {
+	Location string `json:"location"`
+}
+
+type LostItemsQuery struct {
+	RouteID string
+	Kind    string
+	Status  string
+	// Date restricts the search to the items lost or found on that day
+	Date  *time.Time
+	Page  int
+	Limit int
+}
+
+type LostItemResponse struct {
+	ID           string     `json:"id"`
+	Kind         string     `json:"kind"`
+	Description  string     `json:"description"`
+	RouteID      *string    `json:"route_id"`
+	OccurredAt   time.Time  `json:"occurred_at"`
+	PhotoURL     *string    `json:"photo_url"`
+	Status       string     `json:"status"`
+	HeldLocation *string    `json:"held_location"`
+	ClaimedAt    *time.Time `json:"claimed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type LostItemsPageResponse struct {
+	Items []LostItemResponse `json:"items"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+	Total int                `json:"total"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+type ReportOccupancy struct {
+	RouteID string `json:"route_id"`
+	StopID  string `json:"stop_id"`
+	// Level is one of empty, seated, standing or full
+	Level string `json:"level"`
+}
+
+type OccupancyReportResponse struct {
+	ID         string    `json:"id"`
+	RouteID    string    `json:"route_id"`
+	StopID     string    `json:"stop_id"`
+	Level      string    `json:"level"`
+	ReportedAt time.Time `json:"reported_at"`
+}
+
+type OccupancyResponse struct {
+	Level string `json:"level"`
+	// Score is the average of the reports, from 0 (empty) to 3 (full)
+	Score   float64 `json:"score"`
+	Reports int     `json:"reports"`
+	// Source is "recent" when estimated from the last minutes, "history" when from past weeks at the same hour
+	Source string `json:"source"`
+}
+
+// RouteOccupancyEstimate is what is known of how full a route's buses are,
+// right now and at each hour of the day
+type RouteOccupancyEstimate struct {
+	Recent *OccupancyResponse        `json:"recent"`
+	Hours  map[int]OccupancyResponse `json:"hours"`
+}
+
+type OccupancyHeatmapCell struct {
+	// Weekday goes from 0 (Sunday) to 6 (Saturday)
+	Weekday int     `json:"weekday"`
+	Hour    int     `json:"hour"`
+	Level   string  `json:"level"`
+	Score   float64 `json:"score"`
+	Reports int     `json:"reports"`
+}
+
+type OccupancyHeatmapResponse struct {
+	RouteID string                 `json:"route_id"`
+	From    time.Time              `json:"from"`
+	To      time.Time              `json:"to"`
+	Cells   []OccupancyHeatmapCell `json:"cells"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+type PlanQuery struct {
+	From     string
+	To       string
+	DepartAt time.Time
+}
+
+type PlanLegResponse struct {
+	// Mode is either "bus" or "walk"
+	Mode           string           `json:"mode"`
+	RouteID        string           `json:"route_id,omitempty"`
+	RouteName      string           `json:"route_name,omitempty"`
+	DepartureID    string           `json:"departure_id,omitempty"`
+	From           LocationResponse `json:"from"`
+	To             LocationResponse `json:"to"`
+	DepartsAt      time.Time        `json:"departs_at"`
+	ArrivesAt      time.Time        `json:"arrives_at"`
+	WaitMinutes    int              `json:"wait_minutes"`
+	Stops          int              `json:"stops,omitempty"`
+	DistanceMeters int              `json:"distance_meters,omitempty"`
+}
+
+type ItineraryResponse struct {
+	DepartsAt       time.Time         `json:"departs_at"`
+	ArrivesAt       time.Time         `json:"arrives_at"`
+	DurationMinutes int               `json:"duration_minutes"`
+	WaitMinutes     int               `json:"wait_minutes"`
+	Transfers       int               `json:"transfers"`
+	Legs            []PlanLegResponse `json:"legs"`
+}
+
+type PlanResponse struct {
+	From        LocationResponse    `json:"from"`
+	To          LocationResponse    `json:"to"`
+	DepartAt    time.Time           `json:"depart_at"`
+	Itineraries []ItineraryResponse `json:"itineraries"`
+}
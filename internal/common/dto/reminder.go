@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/pkg/timeofday"
+)
+
+type CreateReminder struct {
+	RouteID     string               `json:"route_id"`
+	StopID      string               `json:"stop_id"`
+	Days        []string             `json:"days"`
+	WindowStart *timeofday.TimeOfDay `json:"window_start"`
+	WindowEnd   *timeofday.TimeOfDay `json:"window_end"`
+	LeadMinutes *int                 `json:"lead_minutes"`
+	Channel     string               `json:"channel"`
+}
+
+type ReminderResponse struct {
+	ID          string              `json:"id"`
+	RouteID     string              `json:"route_id"`
+	StopID      string              `json:"stop_id"`
+	Days        []string            `json:"days"`
+	WindowStart timeofday.TimeOfDay `json:"window_start"`
+	WindowEnd   timeofday.TimeOfDay `json:"window_end"`
+	LeadMinutes int                 `json:"lead_minutes"`
+	Channel     string              `json:"channel"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
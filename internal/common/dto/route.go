@@ -0,0 +1,112 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/pkg/timeofday"
+)
+
+type LocationResponse struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// RouteStopResponse and RouteResponse keep the camelCase names of the Java API
+// they replace, so its clients keep working
+type RouteStopResponse struct {
+	ID          string           `json:"id"`
+	Location    LocationResponse `json:"location"`
+	StopOrder   int              `json:"stopOrder"`
+	IsDeparture bool             `json:"departure"`
+	IsArrival   bool             `json:"arrival"`
+}
+
+type DepartureTimeResponse struct {
+	ID         string              `json:"id"`
+	CalendarID string              `json:"calendar_id"`
+	Time       timeofday.TimeOfDay `json:"time"`
+}
+
+type RouteResponse struct {
+	ID                string                  `json:"id"`
+	Name              string                  `json:"name"`
+	TripLength        float64                 `json:"tripLength"`
+	DepartureLocation *LocationResponse       `json:"departureLocation"`
+	ArrivalLocation   *LocationResponse       `json:"arrivalLocation"`
+	Notes             []string                `json:"notes"`
+	Shape             *string                 `json:"shape"`
+	Stops             []RouteStopResponse     `json:"stops"`
+	Departures        []DepartureTimeResponse `json:"departures"`
+	Alerts            []AlertResponse         `json:"alerts"`
+}
+
+type RouteTimetableResponse struct {
+	RouteID    string                  `json:"route_id"`
+	RouteName  string                  `json:"route_name"`
+	Date       string                  `json:"date"`
+	Departures []DepartureTimeResponse `json:"departures"`
+}
+
+type RouteNameResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type StopArrivalResponse struct {
+	LocationID       string    `json:"location_id"`
+	Name             string    `json:"name"`
+	StopOrder        int       `json:"stop_order"`
+	EstimatedArrival time.Time `json:"estimated_arrival"`
+}
+
+type RouteNextDepartureResponse struct {
+	DepartureID string                `json:"departure_id"`
+	DepartsAt   time.Time             `json:"departs_at"`
+	Stops       []StopArrivalResponse `json:"stops"`
+	Occupancy   *OccupancyResponse    `json:"occupancy"`
+}
+
+type RouteNextDeparturesResponse struct {
+	RouteID    string                       `json:"route_id"`
+	RouteName  string                       `json:"route_name"`
+	Departures []RouteNextDepartureResponse `json:"departures"`
+	Alerts     []AlertResponse              `json:"alerts"`
+}
+
+type StopNextDepartureResponse struct {
+	RouteID          string             `json:"route_id"`
+	RouteName        string             `json:"route_name"`
+	DepartureID      string             `json:"departure_id"`
+	StopOrder        int                `json:"stop_order"`
+	DepartsAt        time.Time          `json:"departs_at"`
+	EstimatedArrival time.Time          `json:"estimated_arrival"`
+	Occupancy        *OccupancyResponse `json:"occupancy"`
+}
+
+type StopNextDeparturesResponse struct {
+	Stop       LocationResponse            `json:"stop"`
+	Departures []StopNextDepartureResponse `json:"departures"`
+	Alerts     []AlertResponse             `json:"alerts"`
+}
+
+type SetStopCoordinates struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+type NearbyStopsQuery struct {
+	Latitude  float64
+	Longitude float64
+	// Radius is the search radius in meters
+	Radius float64
+	Limit  int
+}
+
+type NearbyStopResponse struct {
+	Stop                  LocationResponse `json:"stop"`
+	DistanceMeters        int              `json:"distance_meters"`
+	WalkingDistanceMeters int              `json:"walking_distance_meters"`
+	WalkingMinutes        int              `json:"walking_minutes"`
+}
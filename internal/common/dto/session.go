@@ -9,6 +9,7 @@ type LoginResponse struct {
 }
 
 type CreateSession struct {
+	ID           string `json:"id"`
 	UserID       string `json:"user_id"`
 	IP           string `json:"ip"`
 	Agent        string `json:"agent"`
@@ -25,6 +26,7 @@ type SessionResponse struct {
 	Agent     string    `json:"agent"`
 	IP        string    `json:"ip_address"`
 	Active    bool      `json:"active"`
+	Current   bool      `json:"current"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -15,8 +15,40 @@ type UserResponse struct {
 	Username    string     `json:"username"`
 	Email       string     `json:"email"`
 	IsUfba      bool       `json:"is_ufba"`
+	Role        string     `json:"role"`
 	Activated   bool       `json:"activated"`
 	ActivatedAt *time.Time `json:"activated_at"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 }
+
+type ActivateUser struct {
+	Token string `json:"token"`
+}
+
+type ResendVerification struct {
+	Email string `json:"email"`
+}
+
+type ForgotPassword struct {
+	Email string `json:"email"`
+}
+
+type ResetPassword struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type ChangeEmail struct {
+	Email           string `json:"email"`
+	CurrentPassword string `json:"current_password"`
+}
+
+type ConfirmEmail struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type ReportPosition struct {
+	VehicleID string   `json:"vehicle_id"`
+	RouteID   string   `json:"route_id"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	// Heading is the direction of travel in degrees clockwise from north
+	Heading   *float64   `json:"heading"`
+	Timestamp *time.Time `json:"timestamp"`
+}
+
+// VehicleSnapResponse places a vehicle between two consecutive stops of its route
+type VehicleSnapResponse struct {
+	FromStopID    string `json:"from_stop_id"`
+	FromStopOrder int    `json:"from_stop_order"`
+	ToStopID      string `json:"to_stop_id"`
+	ToStopOrder   int    `json:"to_stop_order"`
+	// Progress goes from 0 at the first stop to 1 at the second
+	Progress float64 `json:"progress"`
+	// OffsetMeters is how far the reported position is from the straight line between the stops
+	OffsetMeters int `json:"offset_meters"`
+}
+
+type VehiclePositionResponse struct {
+	VehicleID  string               `json:"vehicle_id"`
+	RouteID    string               `json:"route_id"`
+	Latitude   float64              `json:"latitude"`
+	Longitude  float64              `json:"longitude"`
+	Heading    *float64             `json:"heading"`
+	Snap       *VehicleSnapResponse `json:"snap"`
+	RecordedAt time.Time            `json:"recorded_at"`
+}
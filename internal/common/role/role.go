@@ -0,0 +1,8 @@
+package role
+
+// Roles a user may have, stored in users.role and carried in the access token claims
+const (
+	User   = "user"
+	Admin  = "admin"
+	Driver = "driver"
+)
@@ -22,15 +22,24 @@ type Config struct {
 
 	PostgresDSN string `mapstructure:"DB_POSTGRES_DSN"`
 	ResendKey   string `mapstructure:"RESEND_API_KEY"`
+	FrontEndURL string `mapstructure:"FRONT_END_URL"`
 
 	JWTSecretKey            string `mapstructure:"JWT_SECRET"`
 	JWTAccessTokenDuration  string `mapstructure:"JWT_ACCESS_TOKEN_DURATION"`
 	JWTRefreshTokenDuration string `mapstructure:"JWT_REFRESH_TOKEN_DURATION"`
 
+	// MaxSessions is how many devices a user may be logged in at once, 5 when unset
+	MaxSessions int `mapstructure:"MAX_SESSIONS_PER_USER"`
+
 	RedisHost     string `mapstructure:"REDIS_HOST"`
 	RedisPort     string `mapstructure:"REDIS_PORT"`
 	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
 	RedisDB       string `mapstructure:"REDIS_DB"`
+
+	// TrackingUDPAddr and TrackingTCPAddr enable the position listeners when set, as ":5050"
+	TrackingUDPAddr   string `mapstructure:"TRACKING_UDP_ADDR"`
+	TrackingTCPAddr   string `mapstructure:"TRACKING_TCP_ADDR"`
+	TrackingDeviceKey string `mapstructure:"TRACKING_DEVICE_KEY"`
 }
 
 func GetConfig() *Config {
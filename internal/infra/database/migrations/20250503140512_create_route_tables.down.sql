@@ -0,0 +1,22 @@
+-- Drop indexes
+DROP INDEX IF EXISTS "idx_departure_times_route_id_departs_at";
+DROP INDEX IF EXISTS "idx_route_stops_location_id";
+DROP INDEX IF EXISTS "idx_route_stops_route_id_stop_order";
+
+-- Drop foreign key constraints
+ALTER TABLE "departure_times"
+	DROP CONSTRAINT IF EXISTS "fk_departure_times_route_id";
+ALTER TABLE "route_stops"
+	DROP CONSTRAINT IF EXISTS "fk_route_stops_location_id";
+ALTER TABLE "route_stops"
+	DROP CONSTRAINT IF EXISTS "fk_route_stops_route_id";
+ALTER TABLE "routes"
+	DROP CONSTRAINT IF EXISTS "fk_routes_arrival_location_id";
+ALTER TABLE "routes"
+	DROP CONSTRAINT IF EXISTS "fk_routes_departure_location_id";
+
+-- Drop tables
+DROP TABLE IF EXISTS "departure_times";
+DROP TABLE IF EXISTS "route_stops";
+DROP TABLE IF EXISTS "routes";
+DROP TABLE IF EXISTS "locations";
@@ -0,0 +1,60 @@
+-- Create locations table
+CREATE TABLE IF NOT EXISTS "locations" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"name" VARCHAR(255) NOT NULL UNIQUE,
+	"latitude" DOUBLE PRECISION NULL,
+	"longitude" DOUBLE PRECISION NULL,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Create routes table
+-- trip_length is the duration of a full trip in minutes
+CREATE TABLE IF NOT EXISTS "routes" (
+	"id" VARCHAR(50) PRIMARY KEY,
+	"name" VARCHAR(255) NOT NULL,
+	"trip_length" DOUBLE PRECISION NOT NULL DEFAULT 0,
+	"departure_location_id" VARCHAR(255) NULL,
+	"arrival_location_id" VARCHAR(255) NULL,
+	"notes" TEXT[] NOT NULL DEFAULT '{}',
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Create route_stops table
+CREATE TABLE IF NOT EXISTS "route_stops" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"route_id" VARCHAR(50) NOT NULL,
+	"location_id" VARCHAR(255) NOT NULL,
+	"stop_order" INTEGER NOT NULL,
+	"is_departure" BOOLEAN NOT NULL DEFAULT false,
+	"is_arrival" BOOLEAN NOT NULL DEFAULT false,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Create departure_times table
+CREATE TABLE IF NOT EXISTS "departure_times" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"route_id" VARCHAR(50) NOT NULL,
+	"departs_at" TIME NOT NULL,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Add foreign key constraints
+ALTER TABLE "routes"
+	ADD CONSTRAINT "fk_routes_departure_location_id" FOREIGN KEY ("departure_location_id") REFERENCES "locations" ("id");
+ALTER TABLE "routes"
+	ADD CONSTRAINT "fk_routes_arrival_location_id" FOREIGN KEY ("arrival_location_id") REFERENCES "locations" ("id");
+ALTER TABLE "route_stops"
+	ADD CONSTRAINT "fk_route_stops_route_id" FOREIGN KEY ("route_id") REFERENCES "routes" ("id") ON DELETE CASCADE;
+ALTER TABLE "route_stops"
+	ADD CONSTRAINT "fk_route_stops_location_id" FOREIGN KEY ("location_id") REFERENCES "locations" ("id");
+ALTER TABLE "departure_times"
+	ADD CONSTRAINT "fk_departure_times_route_id" FOREIGN KEY ("route_id") REFERENCES "routes" ("id") ON DELETE CASCADE;
+
+-- Create indexes for better query performance
+CREATE UNIQUE INDEX "idx_route_stops_route_id_stop_order" ON "route_stops" ("route_id", "stop_order");
+CREATE INDEX "idx_route_stops_location_id" ON "route_stops" ("location_id");
+CREATE UNIQUE INDEX "idx_departure_times_route_id_departs_at" ON "departure_times" ("route_id", "departs_at");
@@ -0,0 +1,3 @@
+-- Drop role column from users table
+ALTER TABLE "users"
+	DROP COLUMN IF EXISTS "role";
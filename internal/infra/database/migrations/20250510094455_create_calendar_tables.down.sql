@@ -0,0 +1,23 @@
+-- Drop indexes
+DROP INDEX IF EXISTS "idx_academic_terms_dates";
+DROP INDEX IF EXISTS "idx_calendar_exceptions_date";
+DROP INDEX IF EXISTS "idx_departure_times_route_id_calendar_id_departs_at";
+
+-- Restore the previous departure uniqueness, keeping weekday departures only
+DELETE FROM "departure_times" WHERE "calendar_id" <> 'weekdays';
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_departure_times_route_id_departs_at" ON "departure_times" ("route_id", "departs_at");
+
+-- Drop foreign key constraints
+ALTER TABLE "departure_times"
+	DROP CONSTRAINT IF EXISTS "fk_departure_times_calendar_id";
+ALTER TABLE "calendar_exceptions"
+	DROP CONSTRAINT IF EXISTS "fk_calendar_exceptions_calendar_id";
+
+-- Drop calendar column from departure_times
+ALTER TABLE "departure_times"
+	DROP COLUMN IF EXISTS "calendar_id";
+
+-- Drop tables
+DROP TABLE IF EXISTS "academic_terms";
+DROP TABLE IF EXISTS "calendar_exceptions";
+DROP TABLE IF EXISTS "service_calendars";
@@ -0,0 +1,65 @@
+-- Create service_calendars table
+-- term_kind tells which academic periods the calendar runs in:
+-- "regular" outside exam weeks and "exam" only during exam weeks
+CREATE TABLE IF NOT EXISTS "service_calendars" (
+	"id" VARCHAR(50) PRIMARY KEY,
+	"name" VARCHAR(255) NOT NULL,
+	"monday" BOOLEAN NOT NULL DEFAULT false,
+	"tuesday" BOOLEAN NOT NULL DEFAULT false,
+	"wednesday" BOOLEAN NOT NULL DEFAULT false,
+	"thursday" BOOLEAN NOT NULL DEFAULT false,
+	"friday" BOOLEAN NOT NULL DEFAULT false,
+	"saturday" BOOLEAN NOT NULL DEFAULT false,
+	"sunday" BOOLEAN NOT NULL DEFAULT false,
+	"term_kind" VARCHAR(50) NOT NULL DEFAULT 'regular',
+	"start_date" DATE NULL,
+	"end_date" DATE NULL,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Create calendar_exceptions table
+-- A null calendar_id means the exception applies to every calendar (e.g. holidays)
+CREATE TABLE IF NOT EXISTS "calendar_exceptions" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"calendar_id" VARCHAR(50) NULL,
+	"date" DATE NOT NULL,
+	"exception_type" VARCHAR(50) NOT NULL,
+	"description" VARCHAR(255) NOT NULL DEFAULT '',
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Create academic_terms table
+CREATE TABLE IF NOT EXISTS "academic_terms" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"name" VARCHAR(255) NOT NULL,
+	"kind" VARCHAR(50) NOT NULL,
+	"start_date" DATE NOT NULL,
+	"end_date" DATE NOT NULL,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- The official timetable only runs on weekdays
+INSERT INTO "service_calendars" ("id", "name", "monday", "tuesday", "wednesday", "thursday", "friday")
+VALUES ('weekdays', 'Dias úteis', true, true, true, true, true)
+ON CONFLICT ("id") DO NOTHING;
+
+-- Bind departures to a calendar
+ALTER TABLE "departure_times"
+	ADD COLUMN IF NOT EXISTS "calendar_id" VARCHAR(50) NOT NULL DEFAULT 'weekdays';
+
+-- Add foreign key constraints
+ALTER TABLE "calendar_exceptions"
+	ADD CONSTRAINT "fk_calendar_exceptions_calendar_id" FOREIGN KEY ("calendar_id") REFERENCES "service_calendars" ("id") ON DELETE CASCADE;
+ALTER TABLE "departure_times"
+	ADD CONSTRAINT "fk_departure_times_calendar_id" FOREIGN KEY ("calendar_id") REFERENCES "service_calendars" ("id");
+
+-- Departures are now unique per calendar
+DROP INDEX IF EXISTS "idx_departure_times_route_id_departs_at";
+CREATE UNIQUE INDEX "idx_departure_times_route_id_calendar_id_departs_at" ON "departure_times" ("route_id", "calendar_id", "departs_at");
+
+-- Create indexes for better query performance
+CREATE INDEX "idx_calendar_exceptions_date" ON "calendar_exceptions" ("date");
+CREATE INDEX "idx_academic_terms_dates" ON "academic_terms" ("start_date", "end_date");
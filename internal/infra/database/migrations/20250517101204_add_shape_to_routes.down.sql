@@ -0,0 +1,3 @@
+-- Drop shape column from routes table
+ALTER TABLE "routes"
+	DROP COLUMN IF EXISTS "shape";
@@ -0,0 +1,4 @@
+-- Add shape column to routes table
+-- shape is the path driven by the route as an encoded polyline (precision 5)
+ALTER TABLE "routes"
+	ADD COLUMN IF NOT EXISTS "shape" TEXT NULL;
@@ -0,0 +1,2 @@
+-- Drop favorites table
+DROP TABLE IF EXISTS "favorites";
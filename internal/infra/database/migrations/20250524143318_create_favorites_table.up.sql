@@ -0,0 +1,29 @@
+-- Create favorites table
+-- A favorite points to either a route or a stop, position orders the list of the user
+CREATE TABLE IF NOT EXISTS "favorites" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"user_id" VARCHAR(255) NOT NULL,
+	"kind" VARCHAR(50) NOT NULL,
+	"route_id" VARCHAR(50) NULL,
+	"location_id" VARCHAR(255) NULL,
+	"position" INTEGER NOT NULL DEFAULT 0,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now(),
+	CONSTRAINT "chk_favorites_target" CHECK (
+		("kind" = 'route' AND "route_id" IS NOT NULL AND "location_id" IS NULL) OR
+		("kind" = 'stop' AND "location_id" IS NOT NULL AND "route_id" IS NULL)
+	)
+);
+
+-- Add foreign key constraints
+ALTER TABLE "favorites"
+	ADD CONSTRAINT "fk_favorites_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "favorites"
+	ADD CONSTRAINT "fk_favorites_route_id" FOREIGN KEY ("route_id") REFERENCES "routes" ("id") ON DELETE CASCADE;
+ALTER TABLE "favorites"
+	ADD CONSTRAINT "fk_favorites_location_id" FOREIGN KEY ("location_id") REFERENCES "locations" ("id") ON DELETE CASCADE;
+
+-- Create indexes for better query performance
+CREATE INDEX "idx_favorites_user_id_position" ON "favorites" ("user_id", "position");
+CREATE UNIQUE INDEX "idx_favorites_user_id_route_id" ON "favorites" ("user_id", "route_id") WHERE "route_id" IS NOT NULL;
+CREATE UNIQUE INDEX "idx_favorites_user_id_location_id" ON "favorites" ("user_id", "location_id") WHERE "location_id" IS NOT NULL;
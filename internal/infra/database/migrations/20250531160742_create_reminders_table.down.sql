@@ -0,0 +1,2 @@
+-- Drop reminders table
+DROP TABLE IF EXISTS "reminders";
@@ -0,0 +1,30 @@
+-- Create reminders table
+-- A reminder fires lead_minutes before every departure of the route reaching
+-- the stop between window_start and window_end on the given weekdays.
+-- An empty weekdays array means every day.
+CREATE TABLE IF NOT EXISTS "reminders" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"user_id" VARCHAR(255) NOT NULL,
+	"route_id" VARCHAR(50) NOT NULL,
+	"location_id" VARCHAR(255) NOT NULL,
+	"weekdays" TEXT[] NOT NULL DEFAULT '{}',
+	"window_start" TIME NOT NULL,
+	"window_end" TIME NOT NULL,
+	"lead_minutes" INTEGER NOT NULL DEFAULT 10,
+	"channel" VARCHAR(50) NOT NULL DEFAULT 'email',
+	"active" BOOLEAN NOT NULL DEFAULT true,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Add foreign key constraints
+ALTER TABLE "reminders"
+	ADD CONSTRAINT "fk_reminders_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "reminders"
+	ADD CONSTRAINT "fk_reminders_route_id" FOREIGN KEY ("route_id") REFERENCES "routes" ("id") ON DELETE CASCADE;
+ALTER TABLE "reminders"
+	ADD CONSTRAINT "fk_reminders_location_id" FOREIGN KEY ("location_id") REFERENCES "locations" ("id") ON DELETE CASCADE;
+
+-- Create indexes for better query performance
+CREATE INDEX "idx_reminders_user_id" ON "reminders" ("user_id");
+CREATE INDEX "idx_reminders_active" ON "reminders" ("active");
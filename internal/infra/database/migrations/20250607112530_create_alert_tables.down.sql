@@ -0,0 +1,4 @@
+-- Drop alert tables
+DROP TABLE IF EXISTS "alert_stops";
+DROP TABLE IF EXISTS "alert_routes";
+DROP TABLE IF EXISTS "alerts";
@@ -0,0 +1,46 @@
+-- Create alerts table
+-- An alert without routes nor stops affects the whole network.
+-- A null ends_at keeps the alert active until it is resolved.
+CREATE TABLE IF NOT EXISTS "alerts" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"title" VARCHAR(255) NOT NULL,
+	"description" TEXT NOT NULL DEFAULT '',
+	"kind" VARCHAR(50) NOT NULL,
+	"severity" VARCHAR(50) NOT NULL,
+	"starts_at" TIMESTAMPTZ NOT NULL,
+	"ends_at" TIMESTAMPTZ NULL,
+	"created_by" VARCHAR(255) NULL,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Create alert_routes table
+CREATE TABLE IF NOT EXISTS "alert_routes" (
+	"alert_id" VARCHAR(255) NOT NULL,
+	"route_id" VARCHAR(50) NOT NULL,
+	PRIMARY KEY ("alert_id", "route_id")
+);
+
+-- Create alert_stops table
+CREATE TABLE IF NOT EXISTS "alert_stops" (
+	"alert_id" VARCHAR(255) NOT NULL,
+	"location_id" VARCHAR(255) NOT NULL,
+	PRIMARY KEY ("alert_id", "location_id")
+);
+
+-- Add foreign key constraints
+ALTER TABLE "alerts"
+	ADD CONSTRAINT "fk_alerts_created_by" FOREIGN KEY ("created_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+ALTER TABLE "alert_routes"
+	ADD CONSTRAINT "fk_alert_routes_alert_id" FOREIGN KEY ("alert_id") REFERENCES "alerts" ("id") ON DELETE CASCADE;
+ALTER TABLE "alert_routes"
+	ADD CONSTRAINT "fk_alert_routes_route_id" FOREIGN KEY ("route_id") REFERENCES "routes" ("id") ON DELETE CASCADE;
+ALTER TABLE "alert_stops"
+	ADD CONSTRAINT "fk_alert_stops_alert_id" FOREIGN KEY ("alert_id") REFERENCES "alerts" ("id") ON DELETE CASCADE;
+ALTER TABLE "alert_stops"
+	ADD CONSTRAINT "fk_alert_stops_location_id" FOREIGN KEY ("location_id") REFERENCES "locations" ("id") ON DELETE CASCADE;
+
+-- Create indexes for better query performance
+CREATE INDEX "idx_alerts_ends_at" ON "alerts" ("ends_at");
+CREATE INDEX "idx_alert_routes_route_id" ON "alert_routes" ("route_id");
+CREATE INDEX "idx_alert_stops_location_id" ON "alert_stops" ("location_id");
@@ -0,0 +1,2 @@
+-- Drop lost_items table
+DROP TABLE IF EXISTS "lost_items";
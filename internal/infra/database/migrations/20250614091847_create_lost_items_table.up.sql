@@ -0,0 +1,34 @@
+-- Create lost_items table
+-- Items are reported by riders as lost or found on a route. Staff then mark
+-- them as held at a location (held_location) and finally as claimed.
+-- route_id becomes NULL when the route is removed so the report is kept.
+CREATE TABLE IF NOT EXISTS "lost_items" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"user_id" VARCHAR(255) NOT NULL,
+	"kind" VARCHAR(50) NOT NULL,
+	"description" TEXT NOT NULL,
+	"route_id" VARCHAR(50) NULL,
+	"occurred_at" TIMESTAMPTZ NOT NULL,
+	"photo_url" TEXT NULL,
+	"status" VARCHAR(50) NOT NULL DEFAULT 'open',
+	"held_location" VARCHAR(255) NULL,
+	"handled_by" VARCHAR(255) NULL,
+	"claimed_at" TIMESTAMPTZ NULL,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now(),
+	CONSTRAINT "chk_lost_items_kind" CHECK ("kind" IN ('lost', 'found')),
+	CONSTRAINT "chk_lost_items_status" CHECK ("status" IN ('open', 'held', 'claimed'))
+);
+
+-- Add foreign key constraints
+ALTER TABLE "lost_items"
+	ADD CONSTRAINT "fk_lost_items_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "lost_items"
+	ADD CONSTRAINT "fk_lost_items_route_id" FOREIGN KEY ("route_id") REFERENCES "routes" ("id") ON DELETE SET NULL;
+ALTER TABLE "lost_items"
+	ADD CONSTRAINT "fk_lost_items_handled_by" FOREIGN KEY ("handled_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+-- Create indexes for better query performance
+CREATE INDEX "idx_lost_items_route_id_occurred_at" ON "lost_items" ("route_id", "occurred_at" DESC);
+CREATE INDEX "idx_lost_items_occurred_at" ON "lost_items" ("occurred_at" DESC);
+CREATE INDEX "idx_lost_items_user_id" ON "lost_items" ("user_id");
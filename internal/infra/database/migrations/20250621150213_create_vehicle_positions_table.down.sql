@@ -0,0 +1,2 @@
+-- Drop vehicle_positions table
+DROP TABLE IF EXISTS "vehicle_positions";
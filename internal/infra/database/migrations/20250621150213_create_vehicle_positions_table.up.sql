@@ -0,0 +1,29 @@
+-- Create vehicle_positions table
+-- Every position reported by the vehicles, kept for a limited time to
+-- learn travel times between stops. The latest position of each vehicle
+-- lives in Redis.
+-- from_stop_order, to_stop_order and progress place the vehicle on the
+-- segment between two consecutive stops of the route, they are NULL when
+-- the vehicle was too far from the route to be placed.
+CREATE TABLE IF NOT EXISTS "vehicle_positions" (
+	"id" BIGSERIAL PRIMARY KEY,
+	"vehicle_id" VARCHAR(50) NOT NULL,
+	"route_id" VARCHAR(50) NOT NULL,
+	"latitude" DOUBLE PRECISION NOT NULL,
+	"longitude" DOUBLE PRECISION NOT NULL,
+	"heading" DOUBLE PRECISION NULL,
+	"from_stop_order" INTEGER NULL,
+	"to_stop_order" INTEGER NULL,
+	"progress" DOUBLE PRECISION NULL,
+	"recorded_at" TIMESTAMPTZ NOT NULL,
+	"created_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Add foreign key constraints
+ALTER TABLE "vehicle_positions"
+	ADD CONSTRAINT "fk_vehicle_positions_route_id" FOREIGN KEY ("route_id") REFERENCES "routes" ("id") ON DELETE CASCADE;
+
+-- Create indexes for better query performance
+CREATE INDEX "idx_vehicle_positions_route_id_recorded_at" ON "vehicle_positions" ("route_id", "recorded_at");
+CREATE INDEX "idx_vehicle_positions_vehicle_id_recorded_at" ON "vehicle_positions" ("vehicle_id", "recorded_at");
+CREATE INDEX "idx_vehicle_positions_recorded_at" ON "vehicle_positions" ("recorded_at");
@@ -0,0 +1,5 @@
+-- Drop fleet tables
+DROP TABLE IF EXISTS "vehicle_assignments";
+DROP TABLE IF EXISTS "drivers";
+DROP TABLE IF EXISTS "vehicles";
+DROP EXTENSION IF EXISTS btree_gist;
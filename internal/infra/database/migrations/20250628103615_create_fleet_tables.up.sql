@@ -0,0 +1,71 @@
+-- btree_gist lets the assignment exclusion constraints compare ids with =
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Create vehicles table
+-- status is 'active' for vehicles in service, 'maintenance' while they are
+-- temporarily unavailable and 'retired' once they left the fleet
+CREATE TABLE IF NOT EXISTS "vehicles" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"plate" VARCHAR(10) NOT NULL UNIQUE,
+	"capacity" INTEGER NOT NULL,
+	"wheelchair_accessible" BOOLEAN NOT NULL DEFAULT false,
+	"low_floor" BOOLEAN NOT NULL DEFAULT false,
+	"status" VARCHAR(50) NOT NULL DEFAULT 'active',
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now(),
+	CONSTRAINT "chk_vehicles_status" CHECK ("status" IN ('active', 'maintenance', 'retired'))
+);
+
+-- Create drivers table
+-- user_id links the driver to the account they use in the app, if any
+CREATE TABLE IF NOT EXISTS "drivers" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"name" VARCHAR(255) NOT NULL,
+	"license_number" VARCHAR(20) NOT NULL UNIQUE,
+	"phone" VARCHAR(20) NULL,
+	"user_id" VARCHAR(255) NULL UNIQUE,
+	"active" BOOLEAN NOT NULL DEFAULT true,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now()
+);
+
+-- Create vehicle_assignments table
+-- Each row puts a vehicle and a driver on a departure on a service date.
+-- starts_at and ends_at bound the trip so overlapping assignments can be found.
+-- Assignments go away with their departure when a timetable is replaced.
+CREATE TABLE IF NOT EXISTS "vehicle_assignments" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"service_date" DATE NOT NULL,
+	"route_id" VARCHAR(50) NOT NULL,
+	"departure_id" VARCHAR(255) NOT NULL,
+	"vehicle_id" VARCHAR(255) NOT NULL,
+	"driver_id" VARCHAR(255) NOT NULL,
+	"starts_at" TIMESTAMPTZ NOT NULL,
+	"ends_at" TIMESTAMPTZ NOT NULL,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	"updated_at" TIMESTAMPTZ DEFAULT now(),
+	CONSTRAINT "chk_vehicle_assignments_period" CHECK ("ends_at" > "starts_at"),
+	-- A vehicle or a driver cannot be on two trips at once, even when both are
+	-- assigned by concurrent requests
+	CONSTRAINT "excl_vehicle_assignments_vehicle_period"
+		EXCLUDE USING gist ("vehicle_id" WITH =, tstzrange("starts_at", "ends_at") WITH &&),
+	CONSTRAINT "excl_vehicle_assignments_driver_period"
+		EXCLUDE USING gist ("driver_id" WITH =, tstzrange("starts_at", "ends_at") WITH &&)
+);
+
+-- Add foreign key constraints
+ALTER TABLE "drivers"
+	ADD CONSTRAINT "fk_drivers_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+ALTER TABLE "vehicle_assignments"
+	ADD CONSTRAINT "fk_vehicle_assignments_route_id" FOREIGN KEY ("route_id") REFERENCES "routes" ("id") ON DELETE CASCADE;
+ALTER TABLE "vehicle_assignments"
+	ADD CONSTRAINT "fk_vehicle_assignments_departure_id" FOREIGN KEY ("departure_id") REFERENCES "departure_times" ("id") ON DELETE CASCADE;
+ALTER TABLE "vehicle_assignments"
+	ADD CONSTRAINT "fk_vehicle_assignments_vehicle_id" FOREIGN KEY ("vehicle_id") REFERENCES "vehicles" ("id");
+ALTER TABLE "vehicle_assignments"
+	ADD CONSTRAINT "fk_vehicle_assignments_driver_id" FOREIGN KEY ("driver_id") REFERENCES "drivers" ("id");
+
+-- Create indexes for better query performance
+CREATE UNIQUE INDEX "idx_vehicle_assignments_service_date_departure_id" ON "vehicle_assignments" ("service_date", "departure_id");
+CREATE INDEX "idx_vehicle_assignments_vehicle_id_starts_at" ON "vehicle_assignments" ("vehicle_id", "starts_at");
+CREATE INDEX "idx_vehicle_assignments_driver_id_starts_at" ON "vehicle_assignments" ("driver_id", "starts_at");
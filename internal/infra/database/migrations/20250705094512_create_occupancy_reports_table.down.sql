@@ -0,0 +1,2 @@
+-- Drop occupancy_reports table
+DROP TABLE IF EXISTS "occupancy_reports";
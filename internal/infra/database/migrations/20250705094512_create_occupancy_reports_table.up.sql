@@ -0,0 +1,24 @@
+-- Create occupancy_reports table
+-- Riders report how full the buses of a route are when they pass a stop.
+-- level is one of 'empty', 'seated', 'standing' or 'full'.
+CREATE TABLE IF NOT EXISTS "occupancy_reports" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"user_id" VARCHAR(255) NOT NULL,
+	"route_id" VARCHAR(50) NOT NULL,
+	"location_id" VARCHAR(255) NOT NULL,
+	"level" VARCHAR(50) NOT NULL,
+	"reported_at" TIMESTAMPTZ NOT NULL DEFAULT now(),
+	CONSTRAINT "chk_occupancy_reports_level" CHECK ("level" IN ('empty', 'seated', 'standing', 'full'))
+);
+
+-- Add foreign key constraints
+ALTER TABLE "occupancy_reports"
+	ADD CONSTRAINT "fk_occupancy_reports_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+ALTER TABLE "occupancy_reports"
+	ADD CONSTRAINT "fk_occupancy_reports_route_id" FOREIGN KEY ("route_id") REFERENCES "routes" ("id") ON DELETE CASCADE;
+ALTER TABLE "occupancy_reports"
+	ADD CONSTRAINT "fk_occupancy_reports_location_id" FOREIGN KEY ("location_id") REFERENCES "locations" ("id") ON DELETE CASCADE;
+
+-- Create indexes for better query performance
+CREATE INDEX "idx_occupancy_reports_route_id_reported_at" ON "occupancy_reports" ("route_id", "reported_at");
+CREATE INDEX "idx_occupancy_reports_user_id" ON "occupancy_reports" ("user_id");
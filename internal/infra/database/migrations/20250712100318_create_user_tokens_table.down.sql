@@ -0,0 +1,2 @@
+-- Drop user_tokens table
+DROP TABLE IF EXISTS "user_tokens";
@@ -0,0 +1,23 @@
+-- Create user_tokens table
+-- Tokens are mailed to users to confirm an action, as verifying their email.
+-- Only the SHA-256 of the token is stored (token_hash), the token itself
+-- exists only in the mailed link. A token is single use: used_at is set
+-- when it is consumed. email is the address the token was sent to.
+CREATE TABLE IF NOT EXISTS "user_tokens" (
+	"id" VARCHAR(255) PRIMARY KEY,
+	"user_id" VARCHAR(255) NOT NULL,
+	"purpose" VARCHAR(50) NOT NULL,
+	"token_hash" VARCHAR(64) NOT NULL UNIQUE,
+	"email" VARCHAR(255) NOT NULL,
+	"expires_at" TIMESTAMPTZ NOT NULL,
+	"used_at" TIMESTAMPTZ NULL,
+	"created_at" TIMESTAMPTZ DEFAULT now(),
+	CONSTRAINT "chk_user_tokens_purpose" CHECK ("purpose" IN ('verify_email'))
+);
+
+-- Add foreign key constraints
+ALTER TABLE "user_tokens"
+	ADD CONSTRAINT "fk_user_tokens_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- Create indexes for better query performance
+CREATE INDEX "idx_user_tokens_user_id_purpose" ON "user_tokens" ("user_id", "purpose");
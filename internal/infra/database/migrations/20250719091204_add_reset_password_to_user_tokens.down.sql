@@ -0,0 +1,5 @@
+-- Remove password reset tokens
+DELETE FROM "user_tokens" WHERE "purpose" = 'reset_password';
+ALTER TABLE "user_tokens" DROP CONSTRAINT "chk_user_tokens_purpose";
+ALTER TABLE "user_tokens"
+	ADD CONSTRAINT "chk_user_tokens_purpose" CHECK ("purpose" IN ('verify_email'));
@@ -0,0 +1,4 @@
+-- Allow password reset tokens
+ALTER TABLE "user_tokens" DROP CONSTRAINT "chk_user_tokens_purpose";
+ALTER TABLE "user_tokens"
+	ADD CONSTRAINT "chk_user_tokens_purpose" CHECK ("purpose" IN ('verify_email', 'reset_password'));
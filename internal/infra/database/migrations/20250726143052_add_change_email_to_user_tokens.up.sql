@@ -0,0 +1,4 @@
+-- Allow email change tokens, their email being the new address waiting for confirmation
+ALTER TABLE "user_tokens" DROP CONSTRAINT "chk_user_tokens_purpose";
+ALTER TABLE "user_tokens"
+	ADD CONSTRAINT "chk_user_tokens_purpose" CHECK ("purpose" IN ('verify_email', 'reset_password', 'change_email'));
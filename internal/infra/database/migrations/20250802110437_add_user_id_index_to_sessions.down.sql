@@ -0,0 +1,2 @@
+-- Drop sessions user index
+DROP INDEX IF EXISTS "idx_sessions_user_id_active";
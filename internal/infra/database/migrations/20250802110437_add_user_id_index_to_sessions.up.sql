@@ -0,0 +1,2 @@
+-- Sessions are looked up by user now that a user may have several active ones
+CREATE INDEX IF NOT EXISTS "idx_sessions_user_id_active" ON "sessions" ("user_id", "active");
@@ -1,6 +1,11 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/pkg/timeofday"
+	"github.com/lib/pq"
+)
 
 type Session struct {
 	ID           string    `db:"id"`
@@ -21,8 +26,247 @@ type User struct {
 	Email       string     `db:"email"`
 	Password    string     `db:"password"`
 	IsUfba      bool       `db:"is_ufba"`
+	Role        string     `db:"role"`
 	Activated   bool       `db:"activated"`
 	ActivatedAt *time.Time `db:"activated_at"`
 	CreatedAt   time.Time  `db:"created_at"`
 	UpdatedAt   time.Time  `db:"updated_at"`
 }
+
+// UserToken is a mailed token confirming an action of the user, stored by its hash
+type UserToken struct {
+	ID        string     `db:"id"`
+	UserID    string     `db:"user_id"`
+	Purpose   string     `db:"purpose"`
+	TokenHash string     `db:"token_hash"`
+	Email     string     `db:"email"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+type Location struct {
+	ID        string    `db:"id"`
+	Name      string    `db:"name"`
+	Latitude  *float64  `db:"latitude"`
+	Longitude *float64  `db:"longitude"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type Route struct {
+	ID                  string         `db:"id"`
+	Name                string         `db:"name"`
+	TripLength          float64        `db:"trip_length"`
+	DepartureLocationID *string        `db:"departure_location_id"`
+	ArrivalLocationID   *string        `db:"arrival_location_id"`
+	Notes               pq.StringArray `db:"notes"`
+	Shape               *string        `db:"shape"`
+	CreatedAt           time.Time      `db:"created_at"`
+	UpdatedAt           time.Time      `db:"updated_at"`
+}
+
+type RouteName struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+}
+
+type RouteStop struct {
+	ID          string    `db:"id"`
+	RouteID     string    `db:"route_id"`
+	LocationID  string    `db:"location_id"`
+	StopOrder   int       `db:"stop_order"`
+	IsDeparture bool      `db:"is_departure"`
+	IsArrival   bool      `db:"is_arrival"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+type DepartureTime struct {
+	ID         string              `db:"id"`
+	RouteID    string              `db:"route_id"`
+	CalendarID string              `db:"calendar_id"`
+	DepartsAt  timeofday.TimeOfDay `db:"departs_at"`
+	CreatedAt  time.Time           `db:"created_at"`
+	UpdatedAt  time.Time           `db:"updated_at"`
+}
+
+type ServiceCalendar struct {
+	ID        string     `db:"id"`
+	Name      string     `db:"name"`
+	Monday    bool       `db:"monday"`
+	Tuesday   bool       `db:"tuesday"`
+	Wednesday bool       `db:"wednesday"`
+	Thursday  bool       `db:"thursday"`
+	Friday    bool       `db:"friday"`
+	Saturday  bool       `db:"saturday"`
+	Sunday    bool       `db:"sunday"`
+	TermKind  string     `db:"term_kind"`
+	StartDate *time.Time `db:"start_date"`
+	EndDate   *time.Time `db:"end_date"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+type CalendarException struct {
+	ID            string    `db:"id"`
+	CalendarID    *string   `db:"calendar_id"`
+	Date          time.Time `db:"date"`
+	ExceptionType string    `db:"exception_type"`
+	Description   string    `db:"description"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+type AcademicTerm struct {
+	ID        string    `db:"id"`
+	Name      string    `db:"name"`
+	Kind      string    `db:"kind"`
+	StartDate time.Time `db:"start_date"`
+	EndDate   time.Time `db:"end_date"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type Favorite struct {
+	ID         string    `db:"id"`
+	UserID     string    `db:"user_id"`
+	Kind       string    `db:"kind"`
+	RouteID    *string   `db:"route_id"`
+	LocationID *string   `db:"location_id"`
+	Position   int       `db:"position"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+type Reminder struct {
+	ID          string              `db:"id"`
+	UserID      string              `db:"user_id"`
+	RouteID     string              `db:"route_id"`
+	LocationID  string              `db:"location_id"`
+	Weekdays    pq.StringArray      `db:"weekdays"`
+	WindowStart timeofday.TimeOfDay `db:"window_start"`
+	WindowEnd   timeofday.TimeOfDay `db:"window_end"`
+	LeadMinutes int                 `db:"lead_minutes"`
+	Channel     string              `db:"channel"`
+	Active      bool                `db:"active"`
+	CreatedAt   time.Time           `db:"created_at"`
+	UpdatedAt   time.Time           `db:"updated_at"`
+}
+
+type Alert struct {
+	ID          string     `db:"id"`
+	Title       string     `db:"title"`
+	Description string     `db:"description"`
+	Kind        string     `db:"kind"`
+	Severity    string     `db:"severity"`
+	StartsAt    time.Time  `db:"starts_at"`
+	EndsAt      *time.Time `db:"ends_at"`
+	CreatedBy   *string    `db:"created_by"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+type AlertRoute struct {
+	AlertID string `db:"alert_id"`
+	RouteID string `db:"route_id"`
+}
+
+type AlertStop struct {
+	AlertID    string `db:"alert_id"`
+	LocationID string `db:"location_id"`
+}
+
+type LostItem struct {
+	ID           string     `db:"id"`
+	UserID       string     `db:"user_id"`
+	Kind         string     `db:"kind"`
+	Description  string     `db:"description"`
+	RouteID      *string    `db:"route_id"`
+	OccurredAt   time.Time  `db:"occurred_at"`
+	PhotoURL     *string    `db:"photo_url"`
+	Status       string     `db:"status"`
+	HeldLocation *string    `db:"held_location"`
+	HandledBy    *string    `db:"handled_by"`
+	ClaimedAt    *time.Time `db:"claimed_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+type VehiclePosition struct {
+	ID            int64     `db:"id"`
+	VehicleID     string    `db:"vehicle_id"`
+	RouteID       string    `db:"route_id"`
+	Latitude      float64   `db:"latitude"`
+	Longitude     float64   `db:"longitude"`
+	Heading       *float64  `db:"heading"`
+	FromStopOrder *int      `db:"from_stop_order"`
+	ToStopOrder   *int      `db:"to_stop_order"`
+	Progress      *float64  `db:"progress"`
+	RecordedAt    time.Time `db:"recorded_at"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// SegmentTravelTime is how long vehicles took between two consecutive located
+// stops of a route at an hour of the day, aggregated from vehicle_positions
+type SegmentTravelTime struct {
+	FromStopOrder int     `db:"from_stop_order"`
+	ToStopOrder   int     `db:"to_stop_order"`
+	Hour          int     `db:"hour"`
+	Seconds       float64 `db:"seconds"`
+	Samples       int     `db:"samples"`
+}
+
+type Vehicle struct {
+	ID                   string    `db:"id"`
+	Plate                string    `db:"plate"`
+	Capacity             int       `db:"capacity"`
+	WheelchairAccessible bool      `db:"wheelchair_accessible"`
+	LowFloor             bool      `db:"low_floor"`
+	Status               string    `db:"status"`
+	CreatedAt            time.Time `db:"created_at"`
+	UpdatedAt            time.Time `db:"updated_at"`
+}
+
+type Driver struct {
+	ID            string    `db:"id"`
+	Name          string    `db:"name"`
+	LicenseNumber string    `db:"license_number"`
+	Phone         *string   `db:"phone"`
+	UserID        *string   `db:"user_id"`
+	Active        bool      `db:"active"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+type VehicleAssignment struct {
+	ID          string    `db:"id"`
+	ServiceDate time.Time `db:"service_date"`
+	RouteID     string    `db:"route_id"`
+	DepartureID string    `db:"departure_id"`
+	VehicleID   string    `db:"vehicle_id"`
+	DriverID    string    `db:"driver_id"`
+	StartsAt    time.Time `db:"starts_at"`
+	EndsAt      time.Time `db:"ends_at"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+type OccupancyReport struct {
+	ID         string    `db:"id"`
+	UserID     string    `db:"user_id"`
+	RouteID    string    `db:"route_id"`
+	LocationID string    `db:"location_id"`
+	Level      string    `db:"level"`
+	ReportedAt time.Time `db:"reported_at"`
+}
+
+// OccupancyAggregate is the average occupancy score of a route's reports,
+// grouped by weekday and hour when the query asks for it
+type OccupancyAggregate struct {
+	RouteID string  `db:"route_id"`
+	Weekday int     `db:"weekday"`
+	Hour    int     `db:"hour"`
+	Score   float64 `db:"score"`
+	Reports int     `db:"reports"`
+}
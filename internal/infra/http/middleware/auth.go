@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/token"
@@ -19,11 +20,19 @@ type AuthKey struct{}
 
 type middleware struct {
 	secretKey string
+	sessions  SessionValidator
 }
 
-func NewWithAuth(secretKey string) *middleware {
+// NewWithAuth builds the auth middleware. The session validator is required,
+// access tokens of logged out and revoked sessions are rejected through it.
+func NewWithAuth(secretKey string, sessions SessionValidator) *middleware {
+	if sessions == nil {
+		panic("middleware: a session validator is required")
+	}
+
 	return &middleware{
 		secretKey: secretKey,
+		sessions:  sessions,
 	}
 }
 
@@ -58,8 +67,73 @@ func (m *middleware) WithAuth(next http.Handler) http.Handler {
 			return
 		}
 
+		// The token is valid until it expires, so logging out or revoking a session
+		// must also be checked against the session itself
+		if claims.SessionID == "" {
+			logging.Info("access token without session",
+				zap.String("journey", authJourney),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path))
+			fault.NewHTTPError(w, fault.NewUnauthorized("invalid access token"))
+			return
+		}
+
+		active, err := m.sessions.IsSessionActive(r.Context(), claims.SessionID)
+		if err != nil {
+			logging.Error("failed to check session", err,
+				zap.String("journey", authJourney),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path))
+			fault.NewHTTPError(w, fault.NewInternalServerError("failed to check session"))
+			return
+		}
+		if !active {
+			logging.Info("session has been revoked",
+				zap.String("journey", authJourney),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("sessionID", claims.SessionID))
+			fault.NewHTTPError(w, fault.NewUnauthorized("session has been revoked"))
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), AuthKey{}, claims)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// WithRole only lets through users with one of the given roles.
+// It must be chained after WithAuth, which puts the claims in the context.
+//
+// Example:
+//
+//	r.With(m.WithAuth, m.WithRole(role.Admin)).Post("/", h.handleCreate)
+func (m *middleware) WithRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(AuthKey{}).(*token.Claims)
+			if !ok {
+				logging.Info("claims not found in context",
+					zap.String("journey", authJourney),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path))
+				fault.NewHTTPError(w, fault.NewUnauthorized("access token not provided"))
+				return
+			}
+
+			if !slices.Contains(roles, claims.Role) {
+				logging.Info("insufficient role",
+					zap.String("journey", authJourney),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("userID", claims.UserID),
+					zap.String("role", claims.Role))
+				fault.NewHTTPError(w, fault.NewForbidden("insufficient permissions"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
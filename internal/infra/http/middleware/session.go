@@ -0,0 +1,12 @@
+package middleware
+
+import (
+	"context"
+)
+
+// SessionValidator tells whether the session an access token was issued for is
+// still active. It is an interface so the middleware does not depend on the
+// session module, which depends on it.
+type SessionValidator interface {
+	IsSessionActive(ctx context.Context, sessionId string) (bool, error)
+}
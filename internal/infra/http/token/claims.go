@@ -9,12 +9,17 @@ import (
 
 type Claims struct {
 	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+	// SessionID is the session the token was issued for
+	SessionID string `json:"session_id"`
 	jwt.RegisteredClaims
 }
 
-func NewClaims(userId string, duration time.Duration) (*Claims, error) {
+func NewClaims(userId, role, sessionId string, duration time.Duration) (*Claims, error) {
 	return &Claims{
-		UserID: userId,
+		UserID:    userId,
+		Role:      role,
+		SessionID: sessionId,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
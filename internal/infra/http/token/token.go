@@ -9,12 +9,12 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func Gen(secretKey, userId string, duration time.Duration) (string, *Claims, error) {
+func Gen(secretKey, userId, role, sessionId string, duration time.Duration) (string, *Claims, error) {
 	if len(secretKey) != chacha20poly1305.KeySize {
 		return "", nil, fmt.Errorf("invalid secret key")
 	}
 
-	claims, err := NewClaims(userId, duration)
+	claims, err := NewClaims(userId, role, sessionId, duration)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to create session claims: %w", err)
 	}
@@ -6,5 +6,5 @@ package mail
 //	NotificationSender = "Notification <notification@sender.com>"
 //	SupportSender     = "Support <support@sender.com>"
 const (
-// Put your senders here
+	NotificationSender = "Meu Buzufba <notificacoes@meubuzufba.com>"
 )
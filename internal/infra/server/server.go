@@ -46,6 +46,13 @@ func (s *Server) Start() error {
 	return s.server.ListenAndServe()
 }
 
+// RegisterOnShutdown calls f when the server starts shutting down. Handlers
+// holding long-lived connections, as streams, use it to end them since
+// Shutdown does not interrupt active connections.
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.server.RegisterOnShutdown(f)
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
 	logging.Info("shutting down server",
 		zap.String("journey", "server"))
@@ -0,0 +1,183 @@
+package alert
+
+import (
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+)
+
+const (
+	// KindSuspension is a route not running at all
+	KindSuspension = "suspension"
+	// KindDetour is a route taking another path, skipping some stops
+	KindDetour = "detour"
+	// KindDelay is a route running late
+	KindDelay = "delay"
+	// KindStopClosed is a stop not being served
+	KindStopClosed = "stop_closed"
+	// KindOther is anything riders should know about
+	KindOther = "other"
+
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeveritySevere  = "severe"
+
+	defaultSeverity = SeverityWarning
+	// maxAlertEntities caps how many routes, and how many stops, an alert may affect
+	maxAlertEntities = 100
+)
+
+var (
+	kinds      = []string{KindSuspension, KindDetour, KindDelay, KindStopClosed, KindOther}
+	severities = []string{SeverityInfo, SeverityWarning, SeveritySevere}
+)
+
+type alert struct {
+	id          string
+	title       string
+	description string
+	kind        string
+	severity    string
+	routeIds    []string
+	stopIds     []string
+	startsAt    time.Time
+	endsAt      *time.Time
+	createdBy   *string
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+func NewAlert(
+	title, description, kind, severity string,
+	routeIds, stopIds []string,
+	startsAt time.Time,
+	endsAt *time.Time,
+	createdBy string,
+) (*alert, error) {
+	a := alert{
+		id:        uid.New("alert"),
+		createdBy: &createdBy,
+		createdAt: time.Now(),
+	}
+	a.set(title, description, kind, severity, routeIds, stopIds, startsAt, endsAt)
+
+	if err := a.validate(); err != nil {
+		return nil, fault.New(
+			"failed to create alert entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return &a, nil
+}
+
+func NewAlertFromModel(m model.Alert, routeIds, stopIds []string) *alert {
+	return &alert{
+		id:          m.ID,
+		title:       m.Title,
+		description: m.Description,
+		kind:        m.Kind,
+		severity:    m.Severity,
+		routeIds:    routeIds,
+		stopIds:     stopIds,
+		startsAt:    m.StartsAt,
+		endsAt:      m.EndsAt,
+		createdBy:   m.CreatedBy,
+		createdAt:   m.CreatedAt,
+		updatedAt:   m.UpdatedAt,
+	}
+}
+
+func (a *alert) Update(
+	title, description, kind, severity string,
+	routeIds, stopIds []string,
+	startsAt time.Time,
+	endsAt *time.Time,
+) error {
+	a.set(title, description, kind, severity, routeIds, stopIds, startsAt, endsAt)
+
+	if err := a.validate(); err != nil {
+		return fault.New(
+			"failed to update alert entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return nil
+}
+
+func (a *alert) set(
+	title, description, kind, severity string,
+	routeIds, stopIds []string,
+	startsAt time.Time,
+	endsAt *time.Time,
+) {
+	if severity == "" {
+		severity = defaultSeverity
+	}
+
+	a.title = title
+	a.description = description
+	a.kind = kind
+	a.severity = severity
+	a.routeIds = uniqueSorted(routeIds)
+	a.stopIds = uniqueSorted(stopIds)
+	a.startsAt = startsAt
+	a.endsAt = endsAt
+	a.updatedAt = time.Now()
+}
+
+func (a *alert) validate() error {
+	if a.title == "" {
+		return fault.New("title is required")
+	}
+	if !slices.Contains(kinds, a.kind) {
+		return fault.New("kind must be one of suspension, detour, delay, stop_closed or other")
+	}
+	if !slices.Contains(severities, a.severity) {
+		return fault.New("severity must be one of info, warning or severe")
+	}
+	if len(a.routeIds) > maxAlertEntities || len(a.stopIds) > maxAlertEntities {
+		return fault.New("too many routes or stops")
+	}
+	if a.startsAt.IsZero() {
+		return fault.New("start is required")
+	}
+	if a.endsAt != nil && !a.endsAt.After(a.startsAt) {
+		return fault.New("end must be after start")
+	}
+
+	return nil
+}
+
+func (a *alert) RouteIDs() []string { return a.routeIds }
+func (a *alert) StopIDs() []string  { return a.stopIds }
+
+func (a *alert) Model() model.Alert {
+	return model.Alert{
+		ID:          a.id,
+		Title:       a.title,
+		Description: a.description,
+		Kind:        a.kind,
+		Severity:    a.severity,
+		StartsAt:    a.startsAt,
+		EndsAt:      a.endsAt,
+		CreatedBy:   a.createdBy,
+		CreatedAt:   a.createdAt,
+		UpdatedAt:   a.updatedAt,
+	}
+}
+
+func uniqueSorted(ids []string) []string {
+	res := slices.Clone(ids)
+	if res == nil {
+		res = make([]string, 0)
+	}
+	slices.Sort(res)
+	return slices.Compact(res)
+}
@@ -0,0 +1,159 @@
+package alert
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/role"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/token"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	alertHandlerJourney = "alert handler"
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	alertService Service
+	secretKey    string
+	sessions     middleware.SessionValidator
+}
+
+func NewHandler(alertService Service, secretKey string, sessions middleware.SessionValidator) *handler {
+	once.Do(func() {
+		instance = &handler{
+			alertService: alertService,
+			secretKey:    secretKey,
+			sessions:     sessions,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
+
+	r.Route("/api/v1/alerts", func(r chi.Router) {
+		// Admin
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Post("/", h.handleCreateAlert)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Put("/{alertId}", h.handleUpdateAlert)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Delete("/{alertId}", h.handleDeleteAlert)
+		// Public
+		r.Get("/", h.handleGetAlerts)
+		r.Get("/{alertId}", h.handleGetAlert)
+	})
+}
+
+func (h handler) handleGetAlerts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qs := r.URL.Query()
+
+	res, err := h.alertService.GetAlerts(ctx, dto.AlertsQuery{
+		RouteID:  qs.Get("route_id"),
+		StopID:   qs.Get("stop_id"),
+		Kind:     qs.Get("kind"),
+		Severity: qs.Get("severity"),
+		Status:   qs.Get("status"),
+	})
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetAlert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	alertId := chi.URLParam(r, "alertId")
+
+	res, err := h.alertService.GetAlertByID(ctx, alertId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleCreateAlert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	c, ok := ctx.Value(middleware.AuthKey{}).(*token.Claims)
+	if !ok {
+		logging.Info("Unable to retrieve claims from token",
+			zap.String("journey", alertHandlerJourney),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		fault.NewHTTPError(w, fault.NewUnauthorized("invalid access token"))
+		return
+	}
+
+	var body dto.CreateAlert
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.alertService.CreateAlert(ctx, c.UserID, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, res)
+}
+
+func (h handler) handleUpdateAlert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	alertId := chi.URLParam(r, "alertId")
+
+	var body dto.CreateAlert
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.alertService.UpdateAlert(ctx, alertId, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleDeleteAlert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	alertId := chi.URLParam(r, "alertId")
+
+	err := h.alertService.DeleteAlert(ctx, alertId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+func logErrorInReadRequestBody(err error, r *http.Request) {
+	logging.Error("failed to read request body", err,
+		zap.String("journey", alertHandlerJourney),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path))
+}
@@ -0,0 +1,33 @@
+package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+type Repository interface {
+	Insert(ctx context.Context, alert model.Alert, routeIds, locationIds []string) error
+	Update(ctx context.Context, alert model.Alert, routeIds, locationIds []string) error
+	GetByID(ctx context.Context, alertId string) (*model.Alert, error)
+	GetAll(ctx context.Context) ([]model.Alert, error)
+	// GetNotEndedAt returns the alerts active or upcoming at the given time
+	GetNotEndedAt(ctx context.Context, at time.Time) ([]model.Alert, error)
+	GetRoutesByAlertIDs(ctx context.Context, alertIds []string) ([]model.AlertRoute, error)
+	GetStopsByAlertIDs(ctx context.Context, alertIds []string) ([]model.AlertStop, error)
+	Delete(ctx context.Context, alertId string) error
+}
+
+type Service interface {
+	GetAlerts(ctx context.Context, query dto.AlertsQuery) ([]dto.AlertResponse, error)
+	GetAlertByID(ctx context.Context, alertId string) (*dto.AlertResponse, error)
+	// GetActiveAlerts returns the alerts in effect at the given time, see route.AlertProvider
+	GetActiveAlerts(ctx context.Context, at time.Time) ([]dto.AlertResponse, error)
+	// GetCurrentAlerts returns the alerts active now or starting later
+	GetCurrentAlerts(ctx context.Context) ([]dto.AlertResponse, error)
+	CreateAlert(ctx context.Context, userId string, input dto.CreateAlert) (*dto.AlertResponse, error)
+	UpdateAlert(ctx context.Context, alertId string, input dto.CreateAlert) (*dto.AlertResponse, error)
+	DeleteAlert(ctx context.Context, alertId string) error
+}
@@ -0,0 +1,222 @@
+package alert
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/dbutil"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) Insert(ctx context.Context, alert model.Alert, routeIds, locationIds []string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO alerts (
+			id,
+			title,
+			description,
+			kind,
+			severity,
+			starts_at,
+			ends_at,
+			created_by,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:title,
+			:description,
+			:kind,
+			:severity,
+			:starts_at,
+			:ends_at,
+			:created_by,
+			:created_at,
+			:updated_at
+		)
+	`
+
+	return dbutil.ExecTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		_, err := tx.NamedExecContext(ctx, query, alert)
+		if err != nil {
+			return fault.New("failed to insert alert", fault.WithError(err))
+		}
+
+		return insertLinks(ctx, tx, alert.ID, routeIds, locationIds)
+	})
+}
+
+func (r repo) Update(ctx context.Context, alert model.Alert, routeIds, locationIds []string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE alerts
+		SET
+			title = :title,
+			description = :description,
+			kind = :kind,
+			severity = :severity,
+			starts_at = :starts_at,
+			ends_at = :ends_at,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	return dbutil.ExecTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		_, err := tx.NamedExecContext(ctx, query, alert)
+		if err != nil {
+			return fault.New("failed to update alert", fault.WithError(err))
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM alert_routes WHERE alert_id = $1", alert.ID)
+		if err != nil {
+			return fault.New("failed to delete alert routes", fault.WithError(err))
+		}
+		_, err = tx.ExecContext(ctx, "DELETE FROM alert_stops WHERE alert_id = $1", alert.ID)
+		if err != nil {
+			return fault.New("failed to delete alert stops", fault.WithError(err))
+		}
+
+		return insertLinks(ctx, tx, alert.ID, routeIds, locationIds)
+	})
+}
+
+// insertLinks stores the routes and stops affected by the alert
+func insertLinks(ctx context.Context, tx *sqlx.Tx, alertId string, routeIds, locationIds []string) error {
+	if len(routeIds) > 0 {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO alert_routes (alert_id, route_id) SELECT $1, unnest($2::VARCHAR[])",
+			alertId,
+			pq.Array(routeIds),
+		)
+		if err != nil {
+			return fault.New("failed to insert alert routes", fault.WithError(err))
+		}
+	}
+
+	if len(locationIds) > 0 {
+		_, err := tx.ExecContext(
+			ctx,
+			"INSERT INTO alert_stops (alert_id, location_id) SELECT $1, unnest($2::VARCHAR[])",
+			alertId,
+			pq.Array(locationIds),
+		)
+		if err != nil {
+			return fault.New("failed to insert alert stops", fault.WithError(err))
+		}
+	}
+
+	return nil
+}
+
+func (r repo) GetByID(ctx context.Context, alertId string) (*model.Alert, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var alert model.Alert
+	err := r.db.GetContext(ctx, &alert, "SELECT * FROM alerts WHERE id = $1 LIMIT 1", alertId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve alert", fault.WithError(err))
+	}
+
+	return &alert, nil
+}
+
+func (r repo) GetAll(ctx context.Context) ([]model.Alert, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var alerts = make([]model.Alert, 0)
+	err := r.db.SelectContext(ctx, &alerts, "SELECT * FROM alerts ORDER BY starts_at DESC, id")
+	if err != nil {
+		return nil, fault.New("failed to retrieve alerts", fault.WithError(err))
+	}
+
+	return alerts, nil
+}
+
+func (r repo) GetNotEndedAt(ctx context.Context, at time.Time) ([]model.Alert, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var alerts = make([]model.Alert, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&alerts,
+		"SELECT * FROM alerts WHERE ends_at IS NULL OR ends_at > $1 ORDER BY starts_at DESC, id",
+		at,
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve alerts", fault.WithError(err))
+	}
+
+	return alerts, nil
+}
+
+func (r repo) GetRoutesByAlertIDs(ctx context.Context, alertIds []string) ([]model.AlertRoute, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var routes = make([]model.AlertRoute, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&routes,
+		"SELECT * FROM alert_routes WHERE alert_id = ANY($1) ORDER BY alert_id, route_id",
+		pq.Array(alertIds),
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve alert routes", fault.WithError(err))
+	}
+
+	return routes, nil
+}
+
+func (r repo) GetStopsByAlertIDs(ctx context.Context, alertIds []string) ([]model.AlertStop, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var stops = make([]model.AlertStop, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&stops,
+		"SELECT * FROM alert_stops WHERE alert_id = ANY($1) ORDER BY alert_id, location_id",
+		pq.Array(alertIds),
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve alert stops", fault.WithError(err))
+	}
+
+	return stops, nil
+}
+
+func (r repo) Delete(ctx context.Context, alertId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM alerts WHERE id = $1", alertId)
+	if err != nil {
+		return fault.New("failed to delete alert", fault.WithError(err))
+	}
+
+	return nil
+}
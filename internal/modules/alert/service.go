@@ -0,0 +1,384 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/cache"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const (
+	alertServiceJourney = "alert service"
+	// currentAlertsCacheKey holds the alerts not ended yet, read on every route and departure query
+	currentAlertsCacheKey = "alerts:current"
+	// currentAlertsTTL bounds how long an alert takes to start or end in the cached list
+	currentAlertsTTL = time.Minute
+
+	StatusActive   = "active"
+	StatusUpcoming = "upcoming"
+	StatusEnded    = "ended"
+	StatusAll      = "all"
+)
+
+type ServiceConfig struct {
+	AlertRepo Repository
+	Cache     *cache.Cache
+}
+
+type service struct {
+	alertRepo Repository
+	cache     *cache.Cache
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		alertRepo: c.AlertRepo,
+		cache:     c.Cache,
+	}
+}
+
+func (s service) GetAlerts(ctx context.Context, query dto.AlertsQuery) ([]dto.AlertResponse, error) {
+	if query.Status == "" {
+		query.Status = StatusActive
+	}
+	if !slices.Contains([]string{StatusActive, StatusUpcoming, StatusEnded, StatusAll}, query.Status) {
+		return nil, fault.NewBadRequest("status must be one of active, upcoming, ended or all")
+	}
+
+	now := time.Now()
+
+	var alerts []dto.AlertResponse
+	var err error
+	if query.Status == StatusActive || query.Status == StatusUpcoming {
+		alerts, err = s.currentAlerts(ctx)
+	} else {
+		alerts, err = s.allAlerts(ctx)
+	}
+	if err != nil {
+		return nil, err // The error is already being handled in the loaders
+	}
+
+	res := make([]dto.AlertResponse, 0, len(alerts))
+	for _, a := range alerts {
+		if status(a, now) != query.Status && query.Status != StatusAll {
+			continue
+		}
+		if query.Kind != "" && a.Kind != query.Kind {
+			continue
+		}
+		if query.Severity != "" && a.Severity != query.Severity {
+			continue
+		}
+		// Network-wide alerts affect every route and stop
+		networkWide := len(a.RouteIDs) == 0 && len(a.StopIDs) == 0
+		if query.RouteID != "" && !networkWide && !slices.Contains(a.RouteIDs, query.RouteID) {
+			continue
+		}
+		if query.StopID != "" && !networkWide && !slices.Contains(a.StopIDs, query.StopID) {
+			continue
+		}
+		res = append(res, a)
+	}
+
+	return res, nil
+}
+
+func (s service) GetAlertByID(ctx context.Context, alertId string) (*dto.AlertResponse, error) {
+	record, err := s.alertRepo.GetByID(ctx, alertId)
+	if err != nil {
+		logging.Error("failed to retrieve alert", err,
+			zap.String("journey", alertServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve alert")
+	} else if record == nil {
+		logging.Info("alert not found",
+			zap.String("journey", alertServiceJourney),
+			zap.String("alertID", alertId))
+		return nil, fault.NewNotFound("alert not found")
+	}
+
+	alerts, err := s.buildAlerts(ctx, []model.Alert{*record})
+	if err != nil {
+		return nil, err // The error is already being handled in buildAlerts
+	}
+
+	return &alerts[0], nil
+}
+
+func (s service) GetActiveAlerts(ctx context.Context, at time.Time) ([]dto.AlertResponse, error) {
+	alerts, err := s.currentAlerts(ctx)
+	if err != nil {
+		return nil, err // The error is already being handled in currentAlerts
+	}
+
+	res := make([]dto.AlertResponse, 0, len(alerts))
+	for _, a := range alerts {
+		if status(a, at) == StatusActive {
+			res = append(res, a)
+		}
+	}
+
+	return res, nil
+}
+
+func (s service) GetCurrentAlerts(ctx context.Context) ([]dto.AlertResponse, error) {
+	alerts, err := s.currentAlerts(ctx)
+	if err != nil {
+		return nil, err // The error is already being handled in currentAlerts
+	}
+
+	// The cached list may hold alerts ended since it was loaded
+	now := time.Now()
+	res := make([]dto.AlertResponse, 0, len(alerts))
+	for _, a := range alerts {
+		if status(a, now) != StatusEnded {
+			res = append(res, a)
+		}
+	}
+
+	return res, nil
+}
+
+func (s service) CreateAlert(ctx context.Context, userId string, input dto.CreateAlert) (*dto.AlertResponse, error) {
+	startsAt := time.Now()
+	if input.StartsAt != nil {
+		startsAt = *input.StartsAt
+	}
+
+	alert, err := NewAlert(
+		input.Title, input.Description, input.Kind, input.Severity,
+		input.RouteIDs, input.StopIDs,
+		startsAt, input.EndsAt,
+		userId,
+	)
+	if err != nil {
+		logging.Error("failed to create alert", err,
+			zap.String("journey", alertServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to create alert entity")
+	}
+	model := alert.Model()
+
+	err = s.alertRepo.Insert(ctx, model, alert.RouteIDs(), alert.StopIDs())
+	if err != nil {
+		return nil, s.writeError("failed to insert alert", err)
+	}
+	s.invalidate(ctx)
+
+	res := newAlertResponse(model, alert.RouteIDs(), alert.StopIDs())
+	return &res, nil
+}
+
+func (s service) UpdateAlert(ctx context.Context, alertId string, input dto.CreateAlert) (*dto.AlertResponse, error) {
+	record, err := s.alertRepo.GetByID(ctx, alertId)
+	if err != nil {
+		logging.Error("failed to retrieve alert", err,
+			zap.String("journey", alertServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve alert")
+	} else if record == nil {
+		logging.Info("alert not found",
+			zap.String("journey", alertServiceJourney),
+			zap.String("alertID", alertId))
+		return nil, fault.NewNotFound("alert not found")
+	}
+
+	startsAt := record.StartsAt
+	if input.StartsAt != nil {
+		startsAt = *input.StartsAt
+	}
+
+	alert := NewAlertFromModel(*record, nil, nil)
+	err = alert.Update(
+		input.Title, input.Description, input.Kind, input.Severity,
+		input.RouteIDs, input.StopIDs,
+		startsAt, input.EndsAt,
+	)
+	if err != nil {
+		logging.Error("failed to update alert", err,
+			zap.String("journey", alertServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to update alert entity")
+	}
+	model := alert.Model()
+
+	err = s.alertRepo.Update(ctx, model, alert.RouteIDs(), alert.StopIDs())
+	if err != nil {
+		return nil, s.writeError("failed to update alert", err)
+	}
+	s.invalidate(ctx)
+
+	res := newAlertResponse(model, alert.RouteIDs(), alert.StopIDs())
+	return &res, nil
+}
+
+func (s service) DeleteAlert(ctx context.Context, alertId string) error {
+	record, err := s.alertRepo.GetByID(ctx, alertId)
+	if err != nil {
+		logging.Error("failed to retrieve alert", err,
+			zap.String("journey", alertServiceJourney))
+		return fault.NewBadRequest("failed to retrieve alert")
+	} else if record == nil {
+		logging.Info("alert not found",
+			zap.String("journey", alertServiceJourney),
+			zap.String("alertID", alertId))
+		return fault.NewNotFound("alert not found")
+	}
+
+	err = s.alertRepo.Delete(ctx, alertId)
+	if err != nil {
+		logging.Error("failed to delete alert", err,
+			zap.String("journey", alertServiceJourney))
+		return fault.NewBadRequest("failed to delete alert")
+	}
+	s.invalidate(ctx)
+
+	return nil
+}
+
+// currentAlerts returns the alerts not ended yet, from the cache when possible
+func (s service) currentAlerts(ctx context.Context) ([]dto.AlertResponse, error) {
+	var cached []dto.AlertResponse
+	err := s.cache.GetStruct(ctx, currentAlertsCacheKey, &cached)
+	if err == nil {
+		return cached, nil
+	}
+	if fault.GetTag(err) != fault.CACHE_MISS {
+		logging.Error("failed to query alerts from cache", err,
+			zap.String("journey", alertServiceJourney))
+	}
+
+	records, err := s.alertRepo.GetNotEndedAt(ctx, time.Now())
+	if err != nil {
+		logging.Error("failed to retrieve current alerts", err,
+			zap.String("journey", alertServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve alerts")
+	}
+
+	alerts, err := s.buildAlerts(ctx, records)
+	if err != nil {
+		return nil, err // The error is already being handled in buildAlerts
+	}
+
+	err = s.cache.SetStruct(ctx, currentAlertsCacheKey, alerts, currentAlertsTTL)
+	if err != nil {
+		logging.Error("failed to cache alerts", err,
+			zap.String("journey", alertServiceJourney))
+	}
+
+	return alerts, nil
+}
+
+func (s service) allAlerts(ctx context.Context) ([]dto.AlertResponse, error) {
+	records, err := s.alertRepo.GetAll(ctx)
+	if err != nil {
+		logging.Error("failed to retrieve alerts", err,
+			zap.String("journey", alertServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve alerts")
+	}
+
+	return s.buildAlerts(ctx, records)
+}
+
+// buildAlerts loads the routes and stops of the given alerts, keeping the order of the records
+func (s service) buildAlerts(ctx context.Context, records []model.Alert) ([]dto.AlertResponse, error) {
+	res := make([]dto.AlertResponse, len(records))
+	if len(records) == 0 {
+		return res, nil
+	}
+
+	alertIds := make([]string, len(records))
+	for i, a := range records {
+		alertIds[i] = a.ID
+	}
+
+	routes, err := s.alertRepo.GetRoutesByAlertIDs(ctx, alertIds)
+	if err != nil {
+		logging.Error("failed to retrieve alert routes", err,
+			zap.String("journey", alertServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve alert routes")
+	}
+
+	stops, err := s.alertRepo.GetStopsByAlertIDs(ctx, alertIds)
+	if err != nil {
+		logging.Error("failed to retrieve alert stops", err,
+			zap.String("journey", alertServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve alert stops")
+	}
+
+	routesByAlert := make(map[string][]string, len(records))
+	for _, r := range routes {
+		routesByAlert[r.AlertID] = append(routesByAlert[r.AlertID], r.RouteID)
+	}
+	stopsByAlert := make(map[string][]string, len(records))
+	for _, st := range stops {
+		stopsByAlert[st.AlertID] = append(stopsByAlert[st.AlertID], st.LocationID)
+	}
+
+	for i, a := range records {
+		res[i] = newAlertResponse(a, routesByAlert[a.ID], stopsByAlert[a.ID])
+	}
+
+	return res, nil
+}
+
+// writeError turns a failed write into the error returned to the client,
+// telling apart references to routes or stops that do not exist
+func (s service) writeError(msg string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" { // 23503 is the code for foreign key violation
+		return fault.NewUnprocessableEntity("unknown route or stop")
+	}
+
+	logging.Error(msg, err,
+		zap.String("journey", alertServiceJourney))
+	return fault.NewBadRequest(msg)
+}
+
+// invalidate drops the cached current alerts after they change
+func (s service) invalidate(ctx context.Context) {
+	err := s.cache.Delete(ctx, currentAlertsCacheKey)
+	if err != nil {
+		logging.Error("failed to delete alerts from cache", err,
+			zap.String("journey", alertServiceJourney))
+	}
+}
+
+func status(a dto.AlertResponse, at time.Time) string {
+	switch {
+	case at.Before(a.StartsAt):
+		return StatusUpcoming
+	case a.EndsAt != nil && !at.Before(*a.EndsAt):
+		return StatusEnded
+	default:
+		return StatusActive
+	}
+}
+
+func newAlertResponse(a model.Alert, routeIds, stopIds []string) dto.AlertResponse {
+	// Always return arrays, even for network-wide alerts
+	if routeIds == nil {
+		routeIds = make([]string, 0)
+	}
+	if stopIds == nil {
+		stopIds = make([]string, 0)
+	}
+
+	return dto.AlertResponse{
+		ID:          a.ID,
+		Title:       a.Title,
+		Description: a.Description,
+		Kind:        a.Kind,
+		Severity:    a.Severity,
+		RouteIDs:    routeIds,
+		StopIDs:     stopIds,
+		StartsAt:    a.StartsAt,
+		EndsAt:      a.EndsAt,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+	}
+}
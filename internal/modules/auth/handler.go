@@ -26,30 +26,40 @@ var (
 type handler struct {
 	authService Service
 	secretKey   string
+	sessions    middleware.SessionValidator
 }
 
-func NewHandler(authService Service, secretKey string) *handler {
+func NewHandler(authService Service, secretKey string, sessions middleware.SessionValidator) *handler {
 	Once.Do(func() {
 		instance = &handler{
 			authService: authService,
 			secretKey:   secretKey,
+			sessions:    sessions,
 		}
 	})
 	return instance
 }
 
 func (h handler) Register(r *chi.Mux) {
-	m := middleware.NewWithAuth(h.secretKey)
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
 
 	r.Route("/api/v1/auth", func(r chi.Router) {
 		// Private
 		r.With(m.WithAuth).Get("/me", h.handleGetSigned)
 		r.With(m.WithAuth).Patch("/logout", h.handleLogout)
 		// Public
-		r.With(m.WithAuth).Get("/activate/{userId}", h.handleActivate)
-		r.With(m.WithAuth).Post("/register", h.handleRegister)
-		r.With(m.WithAuth).Post("/login", h.handleLogin)
+		r.Post("/activate", h.handleActivate)
+		r.Post("/activate/resend", h.handleResendVerification)
+		r.Post("/register", h.handleRegister)
+		r.Post("/login", h.handleLogin)
+		r.Post("/password/forgot", h.handleForgotPassword)
+		r.Post("/password/reset", h.handleResetPassword)
 	})
+
+	// Private
+	r.With(m.WithAuth).Put("/api/v1/me/password", h.handleChangePassword)
+	r.With(m.WithAuth).Put("/api/v1/me/email", h.handleChangeEmail)
+	r.With(m.WithAuth).Post("/api/v1/me/email/confirm", h.handleConfirmEmail)
 }
 
 func (h handler) handleLogout(w http.ResponseWriter, r *http.Request) {
@@ -66,9 +76,16 @@ func (h handler) handleLogout(w http.ResponseWriter, r *http.Request) {
 
 func (h handler) handleActivate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	userId := chi.URLParam(r, "userId")
 
-	err := h.authService.Activate(ctx, userId)
+	var body dto.ActivateUser
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	err = h.authService.Activate(ctx, body.Token)
 	if err != nil {
 		logging.Error("failed to activate user", err, zap.String("journey", authHandlerJourney))
 		fault.NewHTTPError(w, err)
@@ -78,6 +95,129 @@ func (h handler) handleActivate(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteSuccess(w, http.StatusOK)
 }
 
+// handleResendVerification answers the same whether or not the email has an account
+func (h handler) handleResendVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.ResendVerification
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	err = h.authService.ResendVerification(ctx, body.Email)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusAccepted)
+}
+
+// handleForgotPassword answers the same whether or not the email has an account
+func (h handler) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.ForgotPassword
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	err = h.authService.ForgotPassword(ctx, body.Email)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusAccepted)
+}
+
+func (h handler) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.ResetPassword
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	err = h.authService.ResetPassword(ctx, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+func (h handler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.ChangePassword
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	err = h.authService.ChangePassword(ctx, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+// handleChangeEmail only mails the confirmation, the email changes on handleConfirmEmail
+func (h handler) handleChangeEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.ChangeEmail
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	err = h.authService.ChangeEmail(ctx, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusAccepted)
+}
+
+func (h handler) handleConfirmEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.ConfirmEmail
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	err = h.authService.ConfirmEmail(ctx, body.Token)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
 func (h handler) handleGetSigned(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user, err := h.authService.GetSignedUser(ctx)
@@ -4,12 +4,37 @@ import (
 	"context"
 
 	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
 )
 
+type Repository interface {
+	InsertToken(ctx context.Context, token model.UserToken) error
+	// ConsumeToken marks the unused and unexpired token with the hash as used and
+	// returns it, or nil when there is no such token
+	ConsumeToken(ctx context.Context, purpose, tokenHash string) (*model.UserToken, error)
+	// ConsumeUserToken works like ConsumeToken, only matching tokens that belong to the user
+	ConsumeUserToken(ctx context.Context, userId, purpose, tokenHash string) (*model.UserToken, error)
+	// RevokeTokens marks every unused token of the user for the purpose as used
+	RevokeTokens(ctx context.Context, userId, purpose string) error
+}
+
 type Service interface {
 	Register(ctx context.Context, input dto.CreateUser) error
 	Login(ctx context.Context, email, password, ip, agent string) (*dto.LoginResponse, error)
 	GetSignedUser(ctx context.Context) (*dto.UserResponse, error)
-	Activate(ctx context.Context, userId string) error
+	// Activate consumes a verification token, activating the account it was sent for
+	Activate(ctx context.Context, token string) error
+	// ResendVerification mails a new verification token, when the email belongs to an inactive account
+	ResendVerification(ctx context.Context, email string) error
+	// ForgotPassword mails a password reset token, when the email belongs to an account
+	ForgotPassword(ctx context.Context, email string) error
+	// ResetPassword consumes a password reset token, setting the new password and ending every session of the user
+	ResetPassword(ctx context.Context, input dto.ResetPassword) error
+	// ChangePassword sets a new password for the signed user, ending their other sessions
+	ChangePassword(ctx context.Context, input dto.ChangePassword) error
+	// ChangeEmail mails a confirmation token to the new email, the account keeps its email until ConfirmEmail
+	ChangeEmail(ctx context.Context, input dto.ChangeEmail) error
+	// ConfirmEmail consumes an email change token of the signed user, ending their other sessions
+	ConfirmEmail(ctx context.Context, token string) error
 	Logout(ctx context.Context) error
 }
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+)
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) InsertToken(ctx context.Context, token model.UserToken) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO user_tokens (
+			id,
+			user_id,
+			purpose,
+			token_hash,
+			email,
+			expires_at,
+			used_at,
+			created_at
+		) VALUES (
+			:id,
+			:user_id,
+			:purpose,
+			:token_hash,
+			:email,
+			:expires_at,
+			:used_at,
+			:created_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, token)
+	if err != nil {
+		return fault.New("failed to insert user token", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) ConsumeToken(ctx context.Context, purpose, tokenHash string) (*model.UserToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	// Marking the token as used in the same statement that finds it keeps two
+	// requests from both consuming it
+	var query = `
+		UPDATE user_tokens
+		SET used_at = now()
+		WHERE token_hash = $1
+			AND purpose = $2
+			AND used_at IS NULL
+			AND expires_at > now()
+		RETURNING *
+	`
+
+	var token model.UserToken
+	err := r.db.GetContext(ctx, &token, query, tokenHash, purpose)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to consume user token", fault.WithError(err))
+	}
+
+	return &token, nil
+}
+
+func (r repo) ConsumeUserToken(ctx context.Context, userId, purpose, tokenHash string) (*model.UserToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	// The owner is part of the condition, so a token sent to another user is left untouched
+	var query = `
+		UPDATE user_tokens
+		SET used_at = now()
+		WHERE token_hash = $1
+			AND purpose = $2
+			AND user_id = $3
+			AND used_at IS NULL
+			AND expires_at > now()
+		RETURNING *
+	`
+
+	var token model.UserToken
+	err := r.db.GetContext(ctx, &token, query, tokenHash, purpose, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to consume user token", fault.WithError(err))
+	}
+
+	return &token, nil
+}
+
+func (r repo) RevokeTokens(ctx context.Context, userId, purpose string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE user_tokens
+		SET used_at = now()
+		WHERE user_id = $1
+			AND purpose = $2
+			AND used_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userId, purpose)
+	if err != nil {
+		return fault.New("failed to revoke user tokens", fault.WithError(err))
+	}
+
+	return nil
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
 	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
 	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/token"
 	"github.com/brnocorreia/api-meu-buzufba/internal/infra/mail"
@@ -27,119 +29,271 @@ const (
 	accessTokenDuration  = time.Minute * 15    // 15 minutes
 	refreshTokenDuration = time.Hour * 24 * 30 // 30 days
 	authServiceJourney   = "auth service"
+	// verifyEmailTokenDuration is how long a verification link stays valid
+	verifyEmailTokenDuration = time.Hour * 24
+	// resendVerificationCooldown is how long before another verification email may be requested
+	resendVerificationCooldown = time.Minute * 2
+	// resetPasswordTokenDuration is how long a password reset link stays valid
+	resetPasswordTokenDuration = time.Minute * 30
+	// forgotPasswordCooldown is how long before another password reset may be requested
+	forgotPasswordCooldown = time.Minute * 2
+	// changeEmailTokenDuration is how long an email change link stays valid
+	changeEmailTokenDuration = time.Hour
 )
 
 type ServiceConfig struct {
 	SecretKey      string
+	AuthRepo       Repository
 	UserService    user.Service
 	UserRepo       user.Repository
 	SessionService session.Service
 	SessionRepo    session.Repository
 	Mailer         *mail.Mail
 	Cache          *cache.Cache
+	// FrontEndURL is where the links mailed to users point to
+	FrontEndURL string
 }
 
 type service struct {
+	authRepo       Repository
 	userRepo       user.Repository
 	sessionService session.Service
 	sessionRepo    session.Repository
 	mailer         *mail.Mail
 	cache          *cache.Cache
 	secretKey      string
+	frontEndURL    string
 }
 
 func NewService(c ServiceConfig) Service {
 	return &service{
+		authRepo:       c.AuthRepo,
 		userRepo:       c.UserRepo,
 		sessionService: c.SessionService,
 		sessionRepo:    c.SessionRepo,
 		mailer:         c.Mailer,
 		cache:          c.Cache,
 		secretKey:      c.SecretKey,
+		frontEndURL:    c.FrontEndURL,
 	}
 }
 
 func (s service) Logout(ctx context.Context) error {
-	c, ok := ctx.Value(middleware.AuthKey{}).(*token.Claims)
-	if !ok {
-		logging.Error("context does not contain auth key",
-			fmt.Errorf("access token not provided"),
+	c, err := signedClaims(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Only the session of this device ends, the user stays logged in elsewhere
+	return s.sessionService.RevokeSession(ctx, c.UserID, c.SessionID)
+}
+
+func (s service) Activate(ctx context.Context, plainToken string) error {
+	if plainToken == "" {
+		return fault.NewBadRequest("token is required")
+	}
+
+	tokenRecord, err := s.authRepo.ConsumeToken(ctx, PurposeVerifyEmail, crypto.HashToken(plainToken))
+	if err != nil {
+		logging.Error("failed to consume verification token", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewBadRequest("failed to activate user")
+	} else if tokenRecord == nil {
+		logging.Info("invalid or expired verification token",
 			zap.String("journey", authServiceJourney))
-		return fault.NewUnauthorized("access token not provided")
+		return expiredActivationLink()
 	}
 
-	sessRecord, err := s.sessionRepo.GetActiveByUserID(ctx, c.UserID)
+	userRecord, err := s.userRepo.GetByID(ctx, tokenRecord.UserID)
 	if err != nil {
-		logging.Error("failed to retrieve active session", err,
+		logging.Error("failed to retrieve user", err,
 			zap.String("journey", authServiceJourney))
-		return fault.NewBadRequest("failed to retrieve active session")
-	} else if sessRecord == nil {
-		logging.Error("active session not found", err,
+		return fault.NewBadRequest("failed to get user by id")
+	} else if userRecord == nil {
+		logging.Info("user not found",
+			zap.String("journey", authServiceJourney),
+			zap.String("userID", tokenRecord.UserID))
+		return fault.NewNotFound("user not found")
+	}
+
+	// A token verifies the address it was sent to, not one the user changed to since
+	if userRecord.Activated || userRecord.Email != tokenRecord.Email {
+		logging.Info("verification token no longer applies",
+			zap.String("journey", authServiceJourney),
+			zap.String("userID", userRecord.ID))
+		return expiredActivationLink()
+	}
+
+	u := user.NewFromModel(*userRecord)
+	u.Activate()
+
+	err = s.userRepo.Update(ctx, u.Model())
+	if err != nil {
+		logging.Error("failed to update user", err,
 			zap.String("journey", authServiceJourney))
-		return fault.NewNotFound("active session not found")
+		return fault.NewBadRequest("failed to update user")
 	}
 
-	sess := session.NewFromModel(*sessRecord)
-	sess.Deactivate()
+	return nil
+}
 
-	err = s.sessionRepo.Update(ctx, sess.Model())
+func (s service) ResendVerification(ctx context.Context, email string) error {
+	if email == "" {
+		return fault.NewBadRequest("email is required")
+	}
+
+	// The cooldown is keyed by the email whether or not it has an account, so
+	// the responses do not tell which emails are registered
+	key := fmt.Sprintf("auth:verify:cooldown:%s", strings.ToLower(email))
+	ok, err := s.cache.SetNX(ctx, key, email, resendVerificationCooldown)
 	if err != nil {
-		logging.Error("failed to deactivate session", err,
+		logging.Error("failed to set verification cooldown", err,
 			zap.String("journey", authServiceJourney))
-		return fault.NewBadRequest("failed to deactivate session")
+		return fault.NewBadRequest("failed to resend verification email")
+	}
+	if !ok {
+		return fault.NewTooManyRequests(fmt.Sprintf("verification email may be resent once every %d minutes", int(resendVerificationCooldown.Minutes())))
 	}
 
-	err = s.cache.Delete(ctx, fmt.Sprintf("sess:%s", c.UserID))
+	userRecord, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		logging.Error("failed to delete session from cache", err,
+		logging.Error("failed to retrieve user", err,
 			zap.String("journey", authServiceJourney))
+		return fault.NewBadRequest("failed to resend verification email")
+	} else if userRecord == nil || userRecord.Activated {
+		return nil
 	}
 
-	// Delete the session from the cache when the user logs out
-	go func() {
-		ctx := context.Background()
+	return s.sendVerification(ctx, *userRecord)
+}
 
-		cacheKey := fmt.Sprintf("sess:%s", c.UserID)
-		has, err := s.cache.Has(ctx, cacheKey)
-		if err != nil {
-			logging.Error("failed to check if session is in cache", err,
-				zap.String("journey", authServiceJourney))
-		} else if has {
-			err = s.cache.Delete(ctx, c.UserID)
-			if err != nil {
-				logging.Error("failed to delete session from cache", err,
-					zap.String("journey", authServiceJourney))
-			}
-		}
-	}()
+func (s service) ForgotPassword(ctx context.Context, email string) error {
+	if email == "" {
+		return fault.NewBadRequest("email is required")
+	}
+
+	// As in ResendVerification, unknown emails answer like registered ones
+	key := fmt.Sprintf("auth:reset:cooldown:%s", strings.ToLower(email))
+	ok, err := s.cache.SetNX(ctx, key, email, forgotPasswordCooldown)
+	if err != nil {
+		logging.Error("failed to set password reset cooldown", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewBadRequest("failed to request password reset")
+	}
+	if !ok {
+		return fault.NewTooManyRequests(fmt.Sprintf("password reset may be requested once every %d minutes", int(forgotPasswordCooldown.Minutes())))
+	}
+
+	userRecord, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		logging.Error("failed to retrieve user", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewBadRequest("failed to request password reset")
+	} else if userRecord == nil {
+		logging.Info("password reset requested for unknown email",
+			zap.String("journey", authServiceJourney))
+		return nil
+	}
+
+	plainToken, err := s.issueToken(ctx, *userRecord, PurposeResetPassword, resetPasswordTokenDuration)
+	if err != nil {
+		return nil // The error is already being handled in issueToken
+	}
+
+	s.sendMail(userRecord.ID, mail.SendParams{
+		From:    mail.NotificationSender,
+		To:      userRecord.Email,
+		Subject: "Redefina sua senha",
+		File:    "reset_password.html",
+		Data: struct {
+			Name             string
+			ResetURL         string
+			ExpiresInMinutes int
+		}{
+			Name:             userRecord.Name,
+			ResetURL:         s.frontEndLink("/reset-password", plainToken),
+			ExpiresInMinutes: int(resetPasswordTokenDuration.Minutes()),
+		},
+	})
 
 	return nil
 }
 
-func (s service) Activate(ctx context.Context, userId string) error {
-	userRecord, err := s.userRepo.GetByID(ctx, userId)
+func (s service) ResetPassword(ctx context.Context, input dto.ResetPassword) error {
+	if input.Token == "" {
+		return fault.NewBadRequest("token is required")
+	}
+	if input.Password == "" {
+		return fault.NewBadRequest("password is required")
+	}
+	if len(input.Password) > crypto.MaxPasswordLength {
+		return passwordTooLong()
+	}
+
+	tokenRecord, err := s.authRepo.ConsumeToken(ctx, PurposeResetPassword, crypto.HashToken(input.Token))
+	if err != nil {
+		logging.Error("failed to consume password reset token", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewBadRequest("failed to reset password")
+	} else if tokenRecord == nil {
+		logging.Info("invalid or expired password reset token",
+			zap.String("journey", authServiceJourney))
+		return expiredResetLink()
+	}
+
+	userRecord, err := s.userRepo.GetByID(ctx, tokenRecord.UserID)
 	if err != nil {
 		logging.Error("failed to retrieve user", err,
 			zap.String("journey", authServiceJourney))
-		return fault.NewBadRequest("failed to get user by id")
-	} else if userRecord == nil {
-		logging.Error("user not found", err,
+		return fault.NewBadRequest("failed to reset password")
+	} else if userRecord == nil || userRecord.Email != tokenRecord.Email {
+		logging.Info("password reset token no longer applies",
+			zap.String("journey", authServiceJourney),
+			zap.String("userID", tokenRecord.UserID))
+		return expiredResetLink()
+	}
+
+	u := user.NewFromModel(*userRecord)
+	if err := u.ChangePassword(input.Password); err != nil {
+		logging.Error("failed to change password", err,
 			zap.String("journey", authServiceJourney))
-		return fault.NewNotFound("user not found")
+		return fault.NewUnprocessableEntity("failed to change password")
 	}
 
-	if userRecord.Activated {
-		logging.Error("user already activated", err,
+	err = s.userRepo.Update(ctx, u.Model())
+	if err != nil {
+		logging.Error("failed to update user", err,
 			zap.String("journey", authServiceJourney))
-		return fault.New(
-			"expired activation link",
-			fault.WithHTTPCode(http.StatusBadRequest),
-			fault.WithTag(fault.EXPIRED),
-		)
+		return fault.NewBadRequest("failed to update user")
+	}
+
+	// Whoever knew the old password is logged out of every device
+	return s.sessionService.RevokeSessions(ctx, userRecord.ID, "")
+}
+
+func (s service) ChangePassword(ctx context.Context, input dto.ChangePassword) error {
+	c, err := signedClaims(ctx)
+	if err != nil {
+		return err
+	}
+	if input.NewPassword == "" {
+		return fault.NewBadRequest("new password is required")
+	}
+	if len(input.NewPassword) > crypto.MaxPasswordLength {
+		return passwordTooLong()
+	}
+
+	userRecord, err := s.getSignedUserRecord(ctx, c.UserID, input.CurrentPassword)
+	if err != nil {
+		return err // The error is already being handled in getSignedUserRecord
 	}
 
 	u := user.NewFromModel(*userRecord)
-	u.Activate()
+	if err := u.ChangePassword(input.NewPassword); err != nil {
+		logging.Error("failed to change password", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewUnprocessableEntity("failed to change password")
+	}
 
 	err = s.userRepo.Update(ctx, u.Model())
 	if err != nil {
@@ -148,9 +302,123 @@ func (s service) Activate(ctx context.Context, userId string) error {
 		return fault.NewBadRequest("failed to update user")
 	}
 
+	// A reset link mailed before the change would otherwise undo it
+	err = s.authRepo.RevokeTokens(ctx, userRecord.ID, PurposeResetPassword)
+	if err != nil {
+		logging.Error("failed to revoke password reset tokens", err,
+			zap.String("journey", authServiceJourney))
+	}
+
+	return s.sessionService.RevokeSessions(ctx, userRecord.ID, c.SessionID)
+}
+
+func (s service) ChangeEmail(ctx context.Context, input dto.ChangeEmail) error {
+	c, err := signedClaims(ctx)
+	if err != nil {
+		return err
+	}
+	if input.Email == "" {
+		return fault.NewBadRequest("email is required")
+	}
+
+	// The current password keeps a leaked access token from taking the account over
+	userRecord, err := s.getSignedUserRecord(ctx, c.UserID, input.CurrentPassword)
+	if err != nil {
+		return err // The error is already being handled in getSignedUserRecord
+	}
+	if strings.EqualFold(userRecord.Email, input.Email) {
+		return fault.NewBadRequest("email is the same as the current one")
+	}
+
+	taken, err := s.userRepo.GetByEmail(ctx, input.Email)
+	if err != nil {
+		logging.Error("failed to retrieve user", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewBadRequest("failed to get user by email")
+	} else if taken != nil {
+		return fault.NewConflict("e-mail already taken")
+	}
+
+	pending := *userRecord
+	pending.Email = input.Email
+	plainToken, err := s.issueToken(ctx, pending, PurposeChangeEmail, changeEmailTokenDuration)
+	if err != nil {
+		return fault.NewBadRequest("failed to send confirmation email")
+	}
+
+	s.sendMail(userRecord.ID, mail.SendParams{
+		From:    mail.NotificationSender,
+		To:      input.Email,
+		Subject: "Confirme seu novo email",
+		File:    "change_email.html",
+		Data: struct {
+			Name             string
+			ConfirmationURL  string
+			ExpiresInMinutes int
+		}{
+			Name:             userRecord.Name,
+			ConfirmationURL:  s.frontEndLink("/confirm-email", plainToken),
+			ExpiresInMinutes: int(changeEmailTokenDuration.Minutes()),
+		},
+	})
+
 	return nil
 }
 
+func (s service) ConfirmEmail(ctx context.Context, plainToken string) error {
+	c, err := signedClaims(ctx)
+	if err != nil {
+		return err
+	}
+	if plainToken == "" {
+		return fault.NewBadRequest("token is required")
+	}
+
+	tokenRecord, err := s.authRepo.ConsumeUserToken(ctx, c.UserID, PurposeChangeEmail, crypto.HashToken(plainToken))
+	if err != nil {
+		logging.Error("failed to consume email change token", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewBadRequest("failed to confirm email")
+	} else if tokenRecord == nil {
+		logging.Info("invalid or expired email change token",
+			zap.String("journey", authServiceJourney),
+			zap.String("userID", c.UserID))
+		return expiredConfirmationLink()
+	}
+
+	userRecord, err := s.userRepo.GetByID(ctx, c.UserID)
+	if err != nil {
+		logging.Error("failed to retrieve user", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewBadRequest("failed to get user by id")
+	} else if userRecord == nil {
+		logging.Info("user not found",
+			zap.String("journey", authServiceJourney),
+			zap.String("userID", c.UserID))
+		return fault.NewNotFound("user not found")
+	}
+
+	u := user.NewFromModel(*userRecord)
+	if err := u.ChangeEmail(tokenRecord.Email, checkIfUserEmailIsUfba(tokenRecord.Email)); err != nil {
+		logging.Error("failed to change email", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewUnprocessableEntity("failed to change email")
+	}
+
+	err = s.userRepo.Update(ctx, u.Model())
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // Taken by another account since the change was requested
+			return fault.NewConflict("e-mail already taken")
+		}
+		logging.Error("failed to update user", err,
+			zap.String("journey", authServiceJourney))
+		return fault.NewBadRequest("failed to update user")
+	}
+
+	return s.sessionService.RevokeSessions(ctx, userRecord.ID, c.SessionID)
+}
+
 func (s service) GetSignedUser(ctx context.Context) (*dto.UserResponse, error) {
 	c, ok := ctx.Value(middleware.AuthKey{}).(*token.Claims)
 	if !ok {
@@ -177,6 +445,7 @@ func (s service) GetSignedUser(ctx context.Context) (*dto.UserResponse, error) {
 		Username:    userRecord.Username,
 		Email:       userRecord.Email,
 		IsUfba:      userRecord.IsUfba,
+		Role:        userRecord.Role,
 		Activated:   userRecord.Activated,
 		ActivatedAt: userRecord.ActivatedAt,
 		CreatedAt:   userRecord.CreatedAt,
@@ -219,6 +488,13 @@ func (s service) Register(ctx context.Context, input dto.CreateUser) error {
 		return fault.NewBadRequest("failed to insert user")
 	}
 
+	// The account is created either way, a failed email can be sent again with ResendVerification
+	if err = s.sendVerification(ctx, model); err != nil {
+		logging.Error("failed to send verification email on register", err,
+			zap.String("journey", authServiceJourney),
+			zap.String("userID", model.ID))
+	}
+
 	// TODO: Send a welcome email here in the future
 
 	return nil
@@ -241,21 +517,16 @@ func (s service) Login(ctx context.Context, email, password, ip, agent string) (
 		return nil, fault.NewUnauthorized("invalid credentials")
 	}
 
-	err = s.sessionRepo.DeactivateAll(ctx, userID)
-	if err != nil {
-		logging.Error("failed to deactivate user sessions", err,
-			zap.String("journey", authServiceJourney))
-		return nil, fault.NewBadRequest("failed to deactivate user sessions")
-	}
+	sessionID := session.NewID()
 
-	accessToken, _, err := token.Gen(s.secretKey, userID, accessTokenDuration)
+	accessToken, _, err := token.Gen(s.secretKey, userID, userRecord.Role, sessionID, accessTokenDuration)
 	if err != nil {
 		logging.Error("failed to generate access token", err,
 			zap.String("journey", authServiceJourney))
 		return nil, fault.NewUnauthorized(err.Error())
 	}
 
-	refreshToken, _, err := token.Gen(s.secretKey, userID, refreshTokenDuration)
+	refreshToken, _, err := token.Gen(s.secretKey, userID, userRecord.Role, sessionID, refreshTokenDuration)
 	if err != nil {
 		logging.Error("failed to generate refresh token", err,
 			zap.String("journey", authServiceJourney))
@@ -263,6 +534,7 @@ func (s service) Login(ctx context.Context, email, password, ip, agent string) (
 	}
 
 	params := dto.CreateSession{
+		ID:           sessionID,
 		IP:           ip,
 		Agent:        agent,
 		UserID:       userID,
@@ -282,6 +554,147 @@ func (s service) Login(ctx context.Context, email, password, ip, agent string) (
 	return &response, nil
 }
 
+// sendVerification replaces the user's pending verification tokens with a new
+// one and mails it
+func (s service) sendVerification(ctx context.Context, userRecord model.User) error {
+	plainToken, err := s.issueToken(ctx, userRecord, PurposeVerifyEmail, verifyEmailTokenDuration)
+	if err != nil {
+		return fault.NewBadRequest("failed to send verification email")
+	}
+
+	s.sendMail(userRecord.ID, mail.SendParams{
+		From:    mail.NotificationSender,
+		To:      userRecord.Email,
+		Subject: "Verifique seu email",
+		File:    "verify_email.html",
+		Data: struct {
+			Name            string
+			VerificationURL string
+		}{
+			Name:            userRecord.Name,
+			VerificationURL: s.frontEndLink("/verify-email", plainToken),
+		},
+	})
+
+	return nil
+}
+
+// issueToken revokes the user's pending tokens for the purpose and stores a new
+// one, returning the plain token to be mailed. The error is logged here.
+func (s service) issueToken(ctx context.Context, userRecord model.User, purpose string, duration time.Duration) (string, error) {
+	err := s.authRepo.RevokeTokens(ctx, userRecord.ID, purpose)
+	if err != nil {
+		logging.Error("failed to revoke user tokens", err,
+			zap.String("journey", authServiceJourney),
+			zap.String("purpose", purpose))
+		return "", err
+	}
+
+	t, plainToken, err := NewUserToken(userRecord.ID, purpose, userRecord.Email, duration)
+	if err != nil {
+		logging.Error("failed to create user token", err,
+			zap.String("journey", authServiceJourney),
+			zap.String("purpose", purpose))
+		return "", err
+	}
+
+	err = s.authRepo.InsertToken(ctx, t.Model())
+	if err != nil {
+		logging.Error("failed to insert user token", err,
+			zap.String("journey", authServiceJourney),
+			zap.String("purpose", purpose))
+		return "", err
+	}
+
+	return plainToken, nil
+}
+
+// sendMail sends the email in the background so a slow mail provider does not
+// hold the request
+func (s service) sendMail(userId string, params mail.SendParams) {
+	go func() {
+		err := s.mailer.Send(params)
+		if err != nil {
+			logging.Error("failed to send email", err,
+				zap.String("journey", authServiceJourney),
+				zap.String("userID", userId),
+				zap.String("file", params.File))
+		}
+	}()
+}
+
+// getSignedUserRecord returns the user, checking the password they gave matches the current one
+func (s service) getSignedUserRecord(ctx context.Context, userId, password string) (*model.User, error) {
+	userRecord, err := s.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		logging.Error("failed to retrieve user", err,
+			zap.String("journey", authServiceJourney))
+		return nil, fault.NewBadRequest("failed to get user by id")
+	} else if userRecord == nil {
+		logging.Info("user not found",
+			zap.String("journey", authServiceJourney),
+			zap.String("userID", userId))
+		return nil, fault.NewNotFound("user not found")
+	}
+
+	if !crypto.PasswordMatches(password, userRecord.Password) {
+		logging.Info("current password does not match",
+			zap.String("journey", authServiceJourney),
+			zap.String("userID", userId))
+		return nil, fault.NewForbidden("current password does not match")
+	}
+
+	return userRecord, nil
+}
+
+// frontEndLink is the front end page at path, carrying the token in its query
+func (s service) frontEndLink(path, plainToken string) string {
+	return fmt.Sprintf("%s%s?token=%s", strings.TrimRight(s.frontEndURL, "/"), path, url.QueryEscape(plainToken))
+}
+
+func expiredActivationLink() error {
+	return fault.New(
+		"expired activation link",
+		fault.WithHTTPCode(http.StatusBadRequest),
+		fault.WithTag(fault.EXPIRED),
+	)
+}
+
+func expiredResetLink() error {
+	return fault.New(
+		"expired password reset link",
+		fault.WithHTTPCode(http.StatusBadRequest),
+		fault.WithTag(fault.EXPIRED),
+	)
+}
+
+func expiredConfirmationLink() error {
+	return fault.New(
+		"expired email confirmation link",
+		fault.WithHTTPCode(http.StatusBadRequest),
+		fault.WithTag(fault.EXPIRED),
+	)
+}
+
+// passwordTooLong rejects passwords bcrypt cannot hash
+func passwordTooLong() error {
+	return fault.NewUnprocessableEntity(
+		fmt.Sprintf("password must have at most %d bytes", crypto.MaxPasswordLength),
+	)
+}
+
+// signedClaims returns the claims put in the context by WithAuth
+func signedClaims(ctx context.Context) (*token.Claims, error) {
+	c, ok := ctx.Value(middleware.AuthKey{}).(*token.Claims)
+	if !ok {
+		logging.Error("context does not contain auth key",
+			fmt.Errorf("access token not provided"),
+			zap.String("journey", authServiceJourney))
+		return nil, fault.NewUnauthorized("access token not provided")
+	}
+	return c, nil
+}
+
 func checkIfUserEmailIsUfba(email string) bool {
 	return strings.HasSuffix(email, "@ufba.br")
 }
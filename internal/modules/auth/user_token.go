@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/crypto"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+)
+
+const (
+	// PurposeVerifyEmail tokens activate the account of the email they were sent to
+	PurposeVerifyEmail = "verify_email"
+	// PurposeResetPassword tokens let the owner of the email set a new password
+	PurposeResetPassword = "reset_password"
+	// PurposeChangeEmail tokens confirm the new email they were sent to
+	PurposeChangeEmail = "change_email"
+)
+
+type userToken struct {
+	id        string
+	userId    string
+	purpose   string
+	tokenHash string
+	email     string
+	expiresAt time.Time
+	createdAt time.Time
+}
+
+// NewUserToken creates a token for the user, returning it along with the plain
+// token to be mailed. The plain token is not kept, only its hash is stored.
+func NewUserToken(userId, purpose, email string, duration time.Duration) (*userToken, string, error) {
+	plain, err := crypto.GenerateToken()
+	if err != nil {
+		return nil, "", fault.New("failed to generate token", fault.WithError(err))
+	}
+
+	now := time.Now()
+	t := userToken{
+		id:        uid.New("token"),
+		userId:    userId,
+		purpose:   purpose,
+		tokenHash: crypto.HashToken(plain),
+		email:     email,
+		expiresAt: now.Add(duration),
+		createdAt: now,
+	}
+
+	if err := t.validate(); err != nil {
+		return nil, "", fault.New(
+			"failed to create user token entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return &t, plain, nil
+}
+
+func (t *userToken) validate() error {
+	if t.userId == "" {
+		return fault.New("user is required")
+	}
+	if t.purpose == "" {
+		return fault.New("purpose is required")
+	}
+	if t.email == "" {
+		return fault.New("email is required")
+	}
+
+	return nil
+}
+
+func (t *userToken) Model() model.UserToken {
+	return model.UserToken{
+		ID:        t.id,
+		UserID:    t.userId,
+		Purpose:   t.purpose,
+		TokenHash: t.tokenHash,
+		Email:     t.email,
+		ExpiresAt: t.expiresAt,
+		CreatedAt: t.createdAt,
+	}
+}
@@ -0,0 +1,76 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+)
+
+const (
+	// ExceptionAdded makes a calendar run on a day it normally would not
+	ExceptionAdded = "added"
+	// ExceptionRemoved suspends a calendar on a day it normally would run
+	ExceptionRemoved = "removed"
+)
+
+type exception struct {
+	id            string
+	calendarId    *string
+	date          time.Time
+	exceptionType string
+	description   string
+	createdAt     time.Time
+	updatedAt     time.Time
+}
+
+// NewException creates a calendar exception. A nil calendarId makes it
+// apply to every calendar, which is how holidays are registered.
+func NewException(calendarId *string, date time.Time, exceptionType, description string) (*exception, error) {
+	e := exception{
+		id:            uid.New("calex"),
+		calendarId:    calendarId,
+		date:          date,
+		exceptionType: exceptionType,
+		description:   description,
+		createdAt:     time.Now(),
+		updatedAt:     time.Now(),
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, fault.New(
+			"failed to create calendar exception entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return &e, nil
+}
+
+func (e *exception) validate() error {
+	if e.date.IsZero() {
+		return fault.New("date is required")
+	}
+	if e.exceptionType != ExceptionAdded && e.exceptionType != ExceptionRemoved {
+		return fault.New("exception type must be either added or removed")
+	}
+	if e.calendarId == nil && e.exceptionType == ExceptionAdded {
+		return fault.New("exceptions for every calendar can only remove service")
+	}
+
+	return nil
+}
+
+func (e *exception) Model() model.CalendarException {
+	return model.CalendarException{
+		ID:            e.id,
+		CalendarID:    e.calendarId,
+		Date:          e.date,
+		ExceptionType: e.exceptionType,
+		Description:   e.description,
+		CreatedAt:     e.createdAt,
+		UpdatedAt:     e.updatedAt,
+	}
+}
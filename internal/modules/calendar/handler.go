@@ -0,0 +1,202 @@
+package calendar
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/role"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	calendarHandlerJourney = "calendar handler"
+	// defaultExceptionsWindow is how far ahead exceptions are listed when no end date is given
+	defaultExceptionsWindow = 90
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	calendarService Service
+	secretKey       string
+	sessions        middleware.SessionValidator
+}
+
+func NewHandler(calendarService Service, secretKey string, sessions middleware.SessionValidator) *handler {
+	once.Do(func() {
+		instance = &handler{
+			calendarService: calendarService,
+			secretKey:       secretKey,
+			sessions:        sessions,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
+
+	r.Route("/api/v1/calendars", func(r chi.Router) {
+		// Admin
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Post("/exceptions", h.handleCreateException)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Delete("/exceptions/{exceptionId}", h.handleDeleteException)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Post("/terms", h.handleCreateTerm)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Put("/terms/{termId}", h.handleUpdateTerm)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Delete("/terms/{termId}", h.handleDeleteTerm)
+		// Public
+		r.Get("/", h.handleGetCalendars)
+		r.Get("/exceptions", h.handleGetExceptions)
+		r.Get("/terms", h.handleGetTerms)
+	})
+}
+
+func (h handler) handleGetCalendars(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	calendars, err := h.calendarService.GetCalendars(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, calendars)
+}
+
+func (h handler) handleGetExceptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qs := r.URL.Query()
+
+	today := time.Now().Format(DateLayout)
+	from, err := ParseDate(httputil.ReadQueryString(qs, "from", today))
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+	to, err := ParseDate(httputil.ReadQueryString(qs, "to", from.AddDate(0, 0, defaultExceptionsWindow).Format(DateLayout)))
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	exceptions, err := h.calendarService.GetExceptions(ctx, from, to)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, exceptions)
+}
+
+func (h handler) handleCreateException(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.CreateCalendarException
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.calendarService.CreateException(ctx, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, res)
+}
+
+func (h handler) handleDeleteException(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	exceptionId := chi.URLParam(r, "exceptionId")
+
+	err := h.calendarService.DeleteException(ctx, exceptionId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+func (h handler) handleGetTerms(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	terms, err := h.calendarService.GetTerms(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, terms)
+}
+
+func (h handler) handleCreateTerm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.CreateAcademicTerm
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.calendarService.CreateTerm(ctx, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, res)
+}
+
+func (h handler) handleUpdateTerm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	termId := chi.URLParam(r, "termId")
+
+	var body dto.CreateAcademicTerm
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.calendarService.UpdateTerm(ctx, termId, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleDeleteTerm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	termId := chi.URLParam(r, "termId")
+
+	err := h.calendarService.DeleteTerm(ctx, termId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+func logErrorInReadRequestBody(err error, r *http.Request) {
+	logging.Error("failed to read request body", err,
+		zap.String("journey", calendarHandlerJourney),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path))
+}
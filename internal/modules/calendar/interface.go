@@ -0,0 +1,41 @@
+package calendar
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+type Repository interface {
+	GetAllCalendars(ctx context.Context) ([]model.ServiceCalendar, error)
+	GetCalendarByID(ctx context.Context, calendarId string) (*model.ServiceCalendar, error)
+	GetExceptionsBetween(ctx context.Context, from, to time.Time) ([]model.CalendarException, error)
+	GetExceptionByID(ctx context.Context, exceptionId string) (*model.CalendarException, error)
+	InsertException(ctx context.Context, exception model.CalendarException) error
+	DeleteException(ctx context.Context, exceptionId string) error
+	GetAllTerms(ctx context.Context) ([]model.AcademicTerm, error)
+	GetTermsBetween(ctx context.Context, from, to time.Time) ([]model.AcademicTerm, error)
+	GetTermByID(ctx context.Context, termId string) (*model.AcademicTerm, error)
+	InsertTerm(ctx context.Context, term model.AcademicTerm) error
+	UpdateTerm(ctx context.Context, term model.AcademicTerm) error
+	DeleteTerm(ctx context.Context, termId string) error
+}
+
+// Writer persists calendars inside a transaction, see dbutil.ExecTx
+type Writer interface {
+	UpsertCalendar(ctx context.Context, calendar model.ServiceCalendar) error
+}
+
+type Service interface {
+	GetCalendars(ctx context.Context) ([]dto.CalendarResponse, error)
+	GetServiceDays(ctx context.Context, from time.Time, days int) (ServiceDays, error)
+	GetExceptions(ctx context.Context, from, to time.Time) ([]dto.CalendarExceptionResponse, error)
+	CreateException(ctx context.Context, input dto.CreateCalendarException) (*dto.CalendarExceptionResponse, error)
+	DeleteException(ctx context.Context, exceptionId string) error
+	GetTerms(ctx context.Context) ([]dto.AcademicTermResponse, error)
+	CreateTerm(ctx context.Context, input dto.CreateAcademicTerm) (*dto.AcademicTermResponse, error)
+	UpdateTerm(ctx context.Context, termId string, input dto.CreateAcademicTerm) (*dto.AcademicTermResponse, error)
+	DeleteTerm(ctx context.Context, termId string) error
+}
@@ -0,0 +1,243 @@
+package calendar
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+)
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) GetAllCalendars(ctx context.Context) ([]model.ServiceCalendar, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var calendars = make([]model.ServiceCalendar, 0)
+	err := r.db.SelectContext(ctx, &calendars, "SELECT * FROM service_calendars ORDER BY id")
+	if err != nil {
+		return nil, fault.New("failed to retrieve calendars", fault.WithError(err))
+	}
+
+	return calendars, nil
+}
+
+func (r repo) GetCalendarByID(ctx context.Context, calendarId string) (*model.ServiceCalendar, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var calendar model.ServiceCalendar
+	err := r.db.GetContext(ctx, &calendar, "SELECT * FROM service_calendars WHERE id = $1 LIMIT 1", calendarId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve calendar", fault.WithError(err))
+	}
+
+	return &calendar, nil
+}
+
+func (r repo) GetExceptionsBetween(ctx context.Context, from, to time.Time) ([]model.CalendarException, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var exceptions = make([]model.CalendarException, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&exceptions,
+		"SELECT * FROM calendar_exceptions WHERE date BETWEEN $1::date AND $2::date ORDER BY date",
+		from.Format(DateLayout),
+		to.Format(DateLayout),
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve calendar exceptions", fault.WithError(err))
+	}
+
+	return exceptions, nil
+}
+
+func (r repo) GetExceptionByID(ctx context.Context, exceptionId string) (*model.CalendarException, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var exception model.CalendarException
+	err := r.db.GetContext(ctx, &exception, "SELECT * FROM calendar_exceptions WHERE id = $1 LIMIT 1", exceptionId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve calendar exception", fault.WithError(err))
+	}
+
+	return &exception, nil
+}
+
+func (r repo) InsertException(ctx context.Context, exception model.CalendarException) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO calendar_exceptions (
+			id,
+			calendar_id,
+			date,
+			exception_type,
+			description,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:calendar_id,
+			:date,
+			:exception_type,
+			:description,
+			:created_at,
+			:updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, exception)
+	if err != nil {
+		return fault.New("failed to insert calendar exception", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) DeleteException(ctx context.Context, exceptionId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM calendar_exceptions WHERE id = $1", exceptionId)
+	if err != nil {
+		return fault.New("failed to delete calendar exception", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) GetAllTerms(ctx context.Context) ([]model.AcademicTerm, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var terms = make([]model.AcademicTerm, 0)
+	err := r.db.SelectContext(ctx, &terms, "SELECT * FROM academic_terms ORDER BY start_date")
+	if err != nil {
+		return nil, fault.New("failed to retrieve academic terms", fault.WithError(err))
+	}
+
+	return terms, nil
+}
+
+func (r repo) GetTermsBetween(ctx context.Context, from, to time.Time) ([]model.AcademicTerm, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var terms = make([]model.AcademicTerm, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&terms,
+		"SELECT * FROM academic_terms WHERE start_date <= $2::date AND end_date >= $1::date ORDER BY start_date",
+		from.Format(DateLayout),
+		to.Format(DateLayout),
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve academic terms", fault.WithError(err))
+	}
+
+	return terms, nil
+}
+
+func (r repo) GetTermByID(ctx context.Context, termId string) (*model.AcademicTerm, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var term model.AcademicTerm
+	err := r.db.GetContext(ctx, &term, "SELECT * FROM academic_terms WHERE id = $1 LIMIT 1", termId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve academic term", fault.WithError(err))
+	}
+
+	return &term, nil
+}
+
+func (r repo) InsertTerm(ctx context.Context, term model.AcademicTerm) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO academic_terms (
+			id,
+			name,
+			kind,
+			start_date,
+			end_date,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:name,
+			:kind,
+			:start_date,
+			:end_date,
+			:created_at,
+			:updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, term)
+	if err != nil {
+		return fault.New("failed to insert academic term", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) UpdateTerm(ctx context.Context, term model.AcademicTerm) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE academic_terms
+		SET
+			name = :name,
+			kind = :kind,
+			start_date = :start_date,
+			end_date = :end_date,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, term)
+	if err != nil {
+		return fault.New("failed to update academic term", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) DeleteTerm(ctx context.Context, termId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM academic_terms WHERE id = $1", termId)
+	if err != nil {
+		return fault.New("failed to delete academic term", fault.WithError(err))
+	}
+
+	return nil
+}
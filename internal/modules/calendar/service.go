@@ -0,0 +1,333 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const calendarServiceJourney = "calendar service"
+
+type ServiceConfig struct {
+	CalendarRepo Repository
+}
+
+type service struct {
+	calendarRepo Repository
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		calendarRepo: c.CalendarRepo,
+	}
+}
+
+func (s service) GetCalendars(ctx context.Context) ([]dto.CalendarResponse, error) {
+	records, err := s.calendarRepo.GetAllCalendars(ctx)
+	if err != nil {
+		logging.Error("failed to retrieve calendars", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve calendars")
+	}
+
+	calendars := make([]dto.CalendarResponse, len(records))
+	for i, c := range records {
+		calendars[i] = newCalendarResponse(c)
+	}
+
+	return calendars, nil
+}
+
+func (s service) GetServiceDays(ctx context.Context, from time.Time, days int) (ServiceDays, error) {
+	to := from.AddDate(0, 0, days)
+
+	calendars, err := s.calendarRepo.GetAllCalendars(ctx)
+	if err != nil {
+		logging.Error("failed to retrieve calendars", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve calendars")
+	}
+
+	exceptions, err := s.calendarRepo.GetExceptionsBetween(ctx, from, to)
+	if err != nil {
+		logging.Error("failed to retrieve calendar exceptions", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve calendar exceptions")
+	}
+
+	terms, err := s.calendarRepo.GetTermsBetween(ctx, from, to)
+	if err != nil {
+		logging.Error("failed to retrieve academic terms", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve academic terms")
+	}
+
+	return resolveServiceDays(calendars, exceptions, terms, from, days), nil
+}
+
+func (s service) GetExceptions(ctx context.Context, from, to time.Time) ([]dto.CalendarExceptionResponse, error) {
+	if to.Before(from) {
+		return nil, fault.NewBadRequest("end date must not be before start date")
+	}
+
+	records, err := s.calendarRepo.GetExceptionsBetween(ctx, from, to)
+	if err != nil {
+		logging.Error("failed to retrieve calendar exceptions", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve calendar exceptions")
+	}
+
+	exceptions := make([]dto.CalendarExceptionResponse, len(records))
+	for i, e := range records {
+		exceptions[i] = newExceptionResponse(e)
+	}
+
+	return exceptions, nil
+}
+
+func (s service) CreateException(ctx context.Context, input dto.CreateCalendarException) (*dto.CalendarExceptionResponse, error) {
+	date, err := ParseDate(input.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.CalendarID != nil {
+		calendarRecord, err := s.calendarRepo.GetCalendarByID(ctx, *input.CalendarID)
+		if err != nil {
+			logging.Error("failed to retrieve calendar", err,
+				zap.String("journey", calendarServiceJourney))
+			return nil, fault.NewBadRequest("failed to retrieve calendar")
+		} else if calendarRecord == nil {
+			logging.Info("calendar not found",
+				zap.String("journey", calendarServiceJourney),
+				zap.String("calendarID", *input.CalendarID))
+			return nil, fault.NewNotFound("calendar not found")
+		}
+	}
+
+	exception, err := NewException(input.CalendarID, date, input.Type, input.Description)
+	if err != nil {
+		logging.Error("failed to create calendar exception", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to create calendar exception entity")
+	}
+	model := exception.Model()
+
+	err = s.calendarRepo.InsertException(ctx, model)
+	if err != nil {
+		logging.Error("failed to insert calendar exception", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to insert calendar exception")
+	}
+
+	res := newExceptionResponse(model)
+	return &res, nil
+}
+
+func (s service) DeleteException(ctx context.Context, exceptionId string) error {
+	record, err := s.calendarRepo.GetExceptionByID(ctx, exceptionId)
+	if err != nil {
+		logging.Error("failed to retrieve calendar exception", err,
+			zap.String("journey", calendarServiceJourney))
+		return fault.NewBadRequest("failed to retrieve calendar exception")
+	} else if record == nil {
+		logging.Info("calendar exception not found",
+			zap.String("journey", calendarServiceJourney),
+			zap.String("exceptionID", exceptionId))
+		return fault.NewNotFound("calendar exception not found")
+	}
+
+	err = s.calendarRepo.DeleteException(ctx, exceptionId)
+	if err != nil {
+		logging.Error("failed to delete calendar exception", err,
+			zap.String("journey", calendarServiceJourney))
+		return fault.NewBadRequest("failed to delete calendar exception")
+	}
+
+	return nil
+}
+
+func (s service) GetTerms(ctx context.Context) ([]dto.AcademicTermResponse, error) {
+	records, err := s.calendarRepo.GetAllTerms(ctx)
+	if err != nil {
+		logging.Error("failed to retrieve academic terms", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve academic terms")
+	}
+
+	terms := make([]dto.AcademicTermResponse, len(records))
+	for i, t := range records {
+		terms[i] = newTermResponse(t)
+	}
+
+	return terms, nil
+}
+
+func (s service) CreateTerm(ctx context.Context, input dto.CreateAcademicTerm) (*dto.AcademicTermResponse, error) {
+	startDate, endDate, err := parseDateRange(input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	term, err := NewTerm(input.Name, input.Kind, startDate, endDate)
+	if err != nil {
+		logging.Error("failed to create academic term", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to create academic term entity")
+	}
+	model := term.Model()
+
+	err = s.calendarRepo.InsertTerm(ctx, model)
+	if err != nil {
+		logging.Error("failed to insert academic term", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to insert academic term")
+	}
+
+	res := newTermResponse(model)
+	return &res, nil
+}
+
+func (s service) UpdateTerm(ctx context.Context, termId string, input dto.CreateAcademicTerm) (*dto.AcademicTermResponse, error) {
+	startDate, endDate, err := parseDateRange(input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.calendarRepo.GetTermByID(ctx, termId)
+	if err != nil {
+		logging.Error("failed to retrieve academic term", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve academic term")
+	} else if record == nil {
+		logging.Info("academic term not found",
+			zap.String("journey", calendarServiceJourney),
+			zap.String("termID", termId))
+		return nil, fault.NewNotFound("academic term not found")
+	}
+
+	term := NewTermFromModel(*record)
+	if err := term.Update(input.Name, input.Kind, startDate, endDate); err != nil {
+		logging.Error("failed to update academic term", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to update academic term entity")
+	}
+	model := term.Model()
+
+	err = s.calendarRepo.UpdateTerm(ctx, model)
+	if err != nil {
+		logging.Error("failed to update academic term", err,
+			zap.String("journey", calendarServiceJourney))
+		return nil, fault.NewBadRequest("failed to update academic term")
+	}
+
+	res := newTermResponse(model)
+	return &res, nil
+}
+
+func (s service) DeleteTerm(ctx context.Context, termId string) error {
+	record, err := s.calendarRepo.GetTermByID(ctx, termId)
+	if err != nil {
+		logging.Error("failed to retrieve academic term", err,
+			zap.String("journey", calendarServiceJourney))
+		return fault.NewBadRequest("failed to retrieve academic term")
+	} else if record == nil {
+		logging.Info("academic term not found",
+			zap.String("journey", calendarServiceJourney),
+			zap.String("termID", termId))
+		return fault.NewNotFound("academic term not found")
+	}
+
+	err = s.calendarRepo.DeleteTerm(ctx, termId)
+	if err != nil {
+		logging.Error("failed to delete academic term", err,
+			zap.String("journey", calendarServiceJourney))
+		return fault.NewBadRequest("failed to delete academic term")
+	}
+
+	return nil
+}
+
+// ParseDate parses a date in the DateLayout format
+func ParseDate(v string) (time.Time, error) {
+	date, err := time.Parse(DateLayout, v)
+	if err != nil {
+		return time.Time{}, fault.NewBadRequest(fmt.Sprintf("invalid date %q, expected format YYYY-MM-DD", v))
+	}
+	return date, nil
+}
+
+func parseDateRange(start, end string) (time.Time, time.Time, error) {
+	startDate, err := ParseDate(start)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endDate, err := ParseDate(end)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return startDate, endDate, nil
+}
+
+func newCalendarResponse(c model.ServiceCalendar) dto.CalendarResponse {
+	days := make([]string, 0, 7)
+	for _, d := range []struct {
+		name string
+		runs bool
+	}{
+		{"monday", c.Monday},
+		{"tuesday", c.Tuesday},
+		{"wednesday", c.Wednesday},
+		{"thursday", c.Thursday},
+		{"friday", c.Friday},
+		{"saturday", c.Saturday},
+		{"sunday", c.Sunday},
+	} {
+		if d.runs {
+			days = append(days, d.name)
+		}
+	}
+
+	return dto.CalendarResponse{
+		ID:        c.ID,
+		Name:      c.Name,
+		Days:      days,
+		TermKind:  c.TermKind,
+		StartDate: formatOptionalDate(c.StartDate),
+		EndDate:   formatOptionalDate(c.EndDate),
+	}
+}
+
+func newExceptionResponse(e model.CalendarException) dto.CalendarExceptionResponse {
+	return dto.CalendarExceptionResponse{
+		ID:          e.ID,
+		CalendarID:  e.CalendarID,
+		Date:        e.Date.Format(DateLayout),
+		Type:        e.ExceptionType,
+		Description: e.Description,
+	}
+}
+
+func newTermResponse(t model.AcademicTerm) dto.AcademicTermResponse {
+	return dto.AcademicTermResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Kind:      t.Kind,
+		StartDate: t.StartDate.Format(DateLayout),
+		EndDate:   t.EndDate.Format(DateLayout),
+	}
+}
+
+func formatOptionalDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	v := t.Format(DateLayout)
+	return &v
+}
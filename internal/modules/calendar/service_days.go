@@ -0,0 +1,147 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+const (
+	// DateLayout is the format used for dates in requests, responses and keys
+	DateLayout = "2006-01-02"
+
+	// TermKindRegular calendars run outside exam weeks
+	TermKindRegular = "regular"
+	// TermKindExam calendars only run during exam weeks
+	TermKindExam = "exam"
+)
+
+// ServiceDays tells which calendars run on each day of a date range
+type ServiceDays map[string]map[string]bool
+
+// Runs reports whether the calendar runs on the given day.
+// Days outside the resolved range never run.
+func (d ServiceDays) Runs(calendarId string, day time.Time) bool {
+	return d[day.Format(DateLayout)][calendarId]
+}
+
+// resolveServiceDays computes which calendars run on each of the days starting at from.
+//
+// The rules are applied in order:
+//  1. an exception for the calendar on that day wins (added runs, removed does not);
+//  2. an exception without calendar (a holiday) suspends every calendar;
+//  3. days outside the calendar start and end dates do not run;
+//  4. recess terms suspend every calendar, exam terms only run exam calendars
+//     and any other day only runs regular calendars;
+//  5. the calendar weekday flags decide.
+func resolveServiceDays(
+	calendars []model.ServiceCalendar,
+	exceptions []model.CalendarException,
+	terms []model.AcademicTerm,
+	from time.Time,
+	days int,
+) ServiceDays {
+	res := make(ServiceDays, days)
+
+	for i := range days {
+		day := from.AddDate(0, 0, i)
+		key := day.Format(DateLayout)
+		kind := termKindOn(terms, key)
+
+		running := make(map[string]bool, len(calendars))
+		for _, c := range calendars {
+			if runs, ok := exceptionOn(exceptions, &c.ID, key); ok {
+				running[c.ID] = runs
+				continue
+			}
+			if runs, ok := exceptionOn(exceptions, nil, key); ok {
+				running[c.ID] = runs
+				continue
+			}
+			if c.StartDate != nil && key < c.StartDate.Format(DateLayout) {
+				continue
+			}
+			if c.EndDate != nil && key > c.EndDate.Format(DateLayout) {
+				continue
+			}
+
+			switch kind {
+			case TermRecess:
+				continue
+			case TermExam:
+				if c.TermKind != TermKindExam {
+					continue
+				}
+			default:
+				if c.TermKind == TermKindExam {
+					continue
+				}
+			}
+
+			running[c.ID] = runsOnWeekday(c, day.Weekday())
+		}
+
+		res[key] = running
+	}
+
+	return res
+}
+
+// termKindOn returns the kind of the academic term covering the day,
+// recess taking precedence over exams and exams over semesters
+func termKindOn(terms []model.AcademicTerm, key string) string {
+	kind := ""
+	for _, t := range terms {
+		if key < t.StartDate.Format(DateLayout) || key > t.EndDate.Format(DateLayout) {
+			continue
+		}
+
+		switch {
+		case t.Kind == TermRecess:
+			return TermRecess
+		case t.Kind == TermExam:
+			kind = TermExam
+		case kind == "":
+			kind = t.Kind
+		}
+	}
+
+	return kind
+}
+
+func exceptionOn(exceptions []model.CalendarException, calendarId *string, key string) (bool, bool) {
+	for _, e := range exceptions {
+		if e.Date.Format(DateLayout) != key {
+			continue
+		}
+		if (calendarId == nil) != (e.CalendarID == nil) {
+			continue
+		}
+		if calendarId != nil && *calendarId != *e.CalendarID {
+			continue
+		}
+
+		return e.ExceptionType == ExceptionAdded, true
+	}
+
+	return false, false
+}
+
+func runsOnWeekday(c model.ServiceCalendar, weekday time.Weekday) bool {
+	switch weekday {
+	case time.Monday:
+		return c.Monday
+	case time.Tuesday:
+		return c.Tuesday
+	case time.Wednesday:
+		return c.Wednesday
+	case time.Thursday:
+		return c.Thursday
+	case time.Friday:
+		return c.Friday
+	case time.Saturday:
+		return c.Saturday
+	default:
+		return c.Sunday
+	}
+}
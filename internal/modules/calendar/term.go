@@ -0,0 +1,109 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+)
+
+const (
+	// TermSemester is a regular class period
+	TermSemester = "semester"
+	// TermRecess is a break with no service at all
+	TermRecess = "recess"
+	// TermExam is an exam period, served by the exam calendars only
+	TermExam = "exam"
+)
+
+type term struct {
+	id        string
+	name      string
+	kind      string
+	startDate time.Time
+	endDate   time.Time
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+func NewTerm(name, kind string, startDate, endDate time.Time) (*term, error) {
+	t := term{
+		id:        uid.New("term"),
+		name:      name,
+		kind:      kind,
+		startDate: startDate,
+		endDate:   endDate,
+		createdAt: time.Now(),
+		updatedAt: time.Now(),
+	}
+
+	if err := t.validate(); err != nil {
+		return nil, fault.New(
+			"failed to create academic term entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return &t, nil
+}
+
+func NewTermFromModel(m model.AcademicTerm) *term {
+	return &term{
+		id:        m.ID,
+		name:      m.Name,
+		kind:      m.Kind,
+		startDate: m.StartDate,
+		endDate:   m.EndDate,
+		createdAt: m.CreatedAt,
+		updatedAt: m.UpdatedAt,
+	}
+}
+
+func (t *term) Update(name, kind string, startDate, endDate time.Time) error {
+	t.name = name
+	t.kind = kind
+	t.startDate = startDate
+	t.endDate = endDate
+	t.updatedAt = time.Now()
+
+	if err := t.validate(); err != nil {
+		return fault.New(
+			"failed to update academic term entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return nil
+}
+
+func (t *term) validate() error {
+	if t.name == "" {
+		return fault.New("name is required")
+	}
+	if t.kind != TermSemester && t.kind != TermRecess && t.kind != TermExam {
+		return fault.New("kind must be one of semester, recess or exam")
+	}
+	if t.startDate.IsZero() || t.endDate.IsZero() {
+		return fault.New("start and end dates are required")
+	}
+	if t.endDate.Before(t.startDate) {
+		return fault.New("end date must not be before start date")
+	}
+
+	return nil
+}
+
+func (t *term) Model() model.AcademicTerm {
+	return model.AcademicTerm{
+		ID:        t.id,
+		Name:      t.name,
+		Kind:      t.kind,
+		StartDate: t.startDate,
+		EndDate:   t.endDate,
+		CreatedAt: t.createdAt,
+		UpdatedAt: t.updatedAt,
+	}
+}
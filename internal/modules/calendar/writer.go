@@ -0,0 +1,78 @@
+package calendar
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+)
+
+type writer struct {
+	tx *sqlx.Tx
+}
+
+func NewWriter(tx *sqlx.Tx) Writer {
+	return &writer{tx: tx}
+}
+
+func (w writer) UpsertCalendar(ctx context.Context, calendar model.ServiceCalendar) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO service_calendars (
+			id,
+			name,
+			monday,
+			tuesday,
+			wednesday,
+			thursday,
+			friday,
+			saturday,
+			sunday,
+			term_kind,
+			start_date,
+			end_date,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:name,
+			:monday,
+			:tuesday,
+			:wednesday,
+			:thursday,
+			:friday,
+			:saturday,
+			:sunday,
+			:term_kind,
+			:start_date,
+			:end_date,
+			:created_at,
+			:updated_at
+		)
+		ON CONFLICT (id) DO UPDATE
+		SET
+			name = EXCLUDED.name,
+			monday = EXCLUDED.monday,
+			tuesday = EXCLUDED.tuesday,
+			wednesday = EXCLUDED.wednesday,
+			thursday = EXCLUDED.thursday,
+			friday = EXCLUDED.friday,
+			saturday = EXCLUDED.saturday,
+			sunday = EXCLUDED.sunday,
+			term_kind = EXCLUDED.term_kind,
+			start_date = EXCLUDED.start_date,
+			end_date = EXCLUDED.end_date,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := w.tx.NamedExecContext(ctx, query, calendar)
+	if err != nil {
+		return fault.New("failed to upsert calendar", fault.WithError(err))
+	}
+
+	return nil
+}
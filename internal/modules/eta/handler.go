@@ -0,0 +1,66 @@
+package eta
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// defaultStopArrivals is the number of arrivals returned when no limit is given
+	defaultStopArrivals = 5
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	etaService Service
+}
+
+func NewHandler(etaService Service) *handler {
+	once.Do(func() {
+		instance = &handler{
+			etaService: etaService,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	// Public
+	r.Get("/api/v1/routes/{routeId}/eta", h.handleGetRouteETA)
+	r.Get("/api/v1/stops/{stopId}/eta", h.handleGetStopETA)
+}
+
+func (h handler) handleGetRouteETA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routeId := chi.URLParam(r, "routeId")
+
+	res, err := h.etaService.GetRouteETA(ctx, routeId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetStopETA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	stopId := chi.URLParam(r, "stopId")
+	limit := httputil.ReadQueryInt(r.URL.Query(), "limit", defaultStopArrivals)
+
+	res, err := h.etaService.GetStopETA(ctx, stopId, limit)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,25 @@
+package eta
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+type Repository interface {
+	// GetSegmentTravelTimes aggregates, per hour of the day in the given timezone, how long
+	// the route's vehicles took between consecutive located stops since the given time
+	GetSegmentTravelTimes(ctx context.Context, routeId string, since time.Time, timezone string) ([]model.SegmentTravelTime, error)
+}
+
+type Service interface {
+	// GetRouteETA predicts the arrival of the route's live vehicles at their next stops,
+	// followed by the upcoming scheduled trips no vehicle is running yet
+	GetRouteETA(ctx context.Context, routeId string) (*dto.RouteETAResponse, error)
+	// GetStopETA returns the next arrivals at the stop, live when a vehicle is on its way
+	GetStopETA(ctx context.Context, locationId string, limit int) (*dto.StopETAsResponse, error)
+	// GetLiveTrips returns the predicted trips of the live vehicles of every route
+	GetLiveTrips(ctx context.Context) ([]dto.RouteETAResponse, error)
+}
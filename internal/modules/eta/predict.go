@@ -0,0 +1,234 @@
+package eta
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+)
+
+const (
+	// minSamples is how many trips a segment needs before its history is trusted
+	minSamples = 3
+	// maxScheduleDeviation is how early or late a vehicle may be and still be
+	// running a given departure
+	maxScheduleDeviation = time.Minute * 30
+)
+
+type segmentKey struct {
+	from, to int
+}
+
+type segmentSample struct {
+	seconds float64
+	samples int
+}
+
+// segmentStats holds the travel time of a segment at each hour of the day and
+// the average over all hours, for hours with too few trips
+type segmentStats struct {
+	hours map[int]segmentSample
+	all   segmentSample
+}
+
+type segmentTimes map[segmentKey]*segmentStats
+
+func newSegmentTimes(rows []model.SegmentTravelTime) segmentTimes {
+	times := make(segmentTimes)
+	for _, row := range rows {
+		key := segmentKey{from: row.FromStopOrder, to: row.ToStopOrder}
+		stats, ok := times[key]
+		if !ok {
+			stats = &segmentStats{hours: make(map[int]segmentSample)}
+			times[key] = stats
+		}
+
+		stats.hours[row.Hour] = segmentSample{seconds: row.Seconds, samples: row.Samples}
+		total := stats.all.samples + row.Samples
+		if total > 0 {
+			stats.all.seconds = (stats.all.seconds*float64(stats.all.samples) + row.Seconds*float64(row.Samples)) / float64(total)
+		}
+		stats.all.samples = total
+	}
+	return times
+}
+
+// duration returns how long the segment usually takes at the hour, reporting
+// false when there is not enough history for it
+func (t segmentTimes) duration(from, to, hour int) (time.Duration, bool) {
+	stats, ok := t[segmentKey{from: from, to: to}]
+	if !ok {
+		return 0, false
+	}
+
+	sample := stats.hours[hour]
+	if sample.samples < minSamples {
+		sample = stats.all
+	}
+	if sample.samples < minSamples {
+		return 0, false
+	}
+	return time.Duration(sample.seconds * float64(time.Second)), true
+}
+
+// liveTrip is the prediction for a vehicle running the route
+type liveTrip struct {
+	vehicle dto.VehiclePositionResponse
+	// position is where the vehicle is along the route, in stop orders
+	position    float64
+	departureId *string
+	// departsAt is when the matched departure left, set along with departureId
+	departsAt time.Time
+	stops     []dto.StopETAResponse
+}
+
+// anchor is a point of the route with a predicted time
+type anchor struct {
+	order float64
+	at    time.Time
+}
+
+// predictTrip predicts the arrival of the vehicle at the stops ahead of it. It
+// walks the located stops from the vehicle on, using the history of each segment
+// at the hour the vehicle will be on it or, without history, the share of the trip
+// length the timetable gives to it. Stops without coordinates are placed between
+// their located neighbours. It returns nil when the vehicle is not on the route.
+func predictTrip(r dto.RouteResponse, times segmentTimes, vehicle dto.VehiclePositionResponse, now time.Time) *liveTrip {
+	snap := vehicle.Snap
+	if snap == nil {
+		return nil
+	}
+
+	lastOrder := 0
+	for _, st := range r.Stops {
+		lastOrder = max(lastOrder, st.StopOrder)
+	}
+
+	segment := func(from, to int, at time.Time) time.Duration {
+		if d, ok := times.duration(from, to, at.In(route.Timezone()).Hour()); ok {
+			return d
+		}
+		return staticDuration(r.TripLength, float64(from), float64(to), lastOrder)
+	}
+
+	position := float64(snap.FromStopOrder) + snap.Progress*float64(snap.ToStopOrder-snap.FromStopOrder)
+	at := vehicle.RecordedAt.Add(time.Duration((1 - snap.Progress) * float64(segment(snap.FromStopOrder, snap.ToStopOrder, vehicle.RecordedAt))))
+	anchors := []anchor{
+		{order: position, at: vehicle.RecordedAt},
+		{order: float64(snap.ToStopOrder), at: at},
+	}
+
+	previous := snap.ToStopOrder
+	for _, st := range r.Stops {
+		if st.StopOrder <= previous || st.Location.Latitude == nil || st.Location.Longitude == nil {
+			continue
+		}
+		at = at.Add(segment(previous, st.StopOrder, at))
+		anchors = append(anchors, anchor{order: float64(st.StopOrder), at: at})
+		previous = st.StopOrder
+	}
+
+	trip := &liveTrip{
+		vehicle:  vehicle,
+		position: position,
+		stops:    make([]dto.StopETAResponse, 0, len(r.Stops)),
+	}
+	for _, st := range r.Stops {
+		order := float64(st.StopOrder)
+		if order <= position {
+			continue
+		}
+
+		arrivesAt := interpolate(anchors, order, r.TripLength, lastOrder)
+		// A vehicle running late is still expected, just not in the past
+		if arrivesAt.Before(now) {
+			arrivesAt = now
+		}
+
+		trip.stops = append(trip.stops, dto.StopETAResponse{
+			LocationID: st.Location.ID,
+			Name:       st.Location.Name,
+			StopOrder:  st.StopOrder,
+			ArrivesAt:  arrivesAt,
+			Minutes:    minutesUntil(now, arrivesAt),
+		})
+	}
+
+	return trip
+}
+
+// interpolate places the order between the anchors around it, past the last
+// anchor it follows the timetable
+func interpolate(anchors []anchor, order float64, tripLength float64, lastOrder int) time.Time {
+	for i := 1; i < len(anchors); i++ {
+		a, b := anchors[i-1], anchors[i]
+		if order > b.order {
+			continue
+		}
+		if b.order == a.order {
+			return b.at
+		}
+		share := (order - a.order) / (b.order - a.order)
+		return a.at.Add(time.Duration(share * float64(b.at.Sub(a.at))))
+	}
+
+	last := anchors[len(anchors)-1]
+	return last.at.Add(staticDuration(tripLength, last.order, order, lastOrder))
+}
+
+// matchDepartures finds the departure each vehicle is running, comparing where
+// the vehicle is with where the timetable expects each of the day's departures.
+// The closest pairs are matched first, so each departure goes to a single vehicle.
+func matchDepartures(trips []*liveTrip, departures []dto.DepartureTimeResponse, day time.Time, tripLength float64, lastOrder int) {
+	type candidate struct {
+		trip        *liveTrip
+		departureId string
+		departsAt   time.Time
+		deviation   time.Duration
+	}
+
+	var candidates []candidate
+	for _, trip := range trips {
+		for _, d := range departures {
+			departsAt := d.Time.On(day)
+			expectedAt := departsAt.Add(staticDuration(tripLength, 0, trip.position, lastOrder))
+			deviation := trip.vehicle.RecordedAt.Sub(expectedAt).Abs()
+			if deviation <= maxScheduleDeviation {
+				candidates = append(candidates, candidate{trip: trip, departureId: d.ID, departsAt: departsAt, deviation: deviation})
+			}
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(a.deviation, b.deviation)
+	})
+
+	taken := make(map[string]bool)
+	for _, c := range candidates {
+		if c.trip.departureId != nil || taken[c.departureId] {
+			continue
+		}
+		c.trip.departureId = &c.departureId
+		c.trip.departsAt = c.departsAt
+		taken[c.departureId] = true
+	}
+}
+
+// staticDuration is the share of the trip length the timetable gives to the
+// stretch between the two stop orders
+func staticDuration(tripLength float64, from, to float64, lastOrder int) time.Duration {
+	if lastOrder <= 0 || to <= from {
+		return 0
+	}
+
+	minutes := tripLength * (to - from) / float64(lastOrder)
+	return time.Duration(minutes * float64(time.Minute))
+}
+
+func minutesUntil(now, at time.Time) int {
+	return max(0, int(math.Round(at.Sub(now).Minutes())))
+}
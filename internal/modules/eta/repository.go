@@ -0,0 +1,69 @@
+package eta
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxSegmentSeconds discards segments that took longer than a bus ever should,
+// as when a vehicle stopped reporting or left the route for a while
+const maxSegmentSeconds = 30 * 60
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) GetSegmentTravelTimes(ctx context.Context, routeId string, since time.Time, timezone string) ([]model.SegmentTravelTime, error) {
+	// Aggregating weeks of positions takes longer than the other queries
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// A vehicle enters a segment at its first position on it, and the segment took
+	// until the vehicle entered the one starting at its end stop
+	var query = `
+		WITH positions AS (
+			SELECT
+				vehicle_id,
+				from_stop_order,
+				to_stop_order,
+				recorded_at,
+				LAG(from_stop_order) OVER (PARTITION BY vehicle_id ORDER BY recorded_at) AS previous_from
+			FROM vehicle_positions
+			WHERE route_id = $1 AND recorded_at >= $2 AND from_stop_order IS NOT NULL
+		), entries AS (
+			SELECT
+				from_stop_order,
+				to_stop_order,
+				recorded_at,
+				LEAD(from_stop_order) OVER (PARTITION BY vehicle_id ORDER BY recorded_at) AS next_from,
+				LEAD(recorded_at) OVER (PARTITION BY vehicle_id ORDER BY recorded_at) AS next_at
+			FROM positions
+			WHERE previous_from IS DISTINCT FROM from_stop_order
+		)
+		SELECT
+			from_stop_order,
+			to_stop_order,
+			EXTRACT(HOUR FROM recorded_at AT TIME ZONE $3)::int AS hour,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM next_at - recorded_at)) AS seconds,
+			COUNT(*) AS samples
+		FROM entries
+		WHERE next_from = to_stop_order AND EXTRACT(EPOCH FROM next_at - recorded_at) <= $4
+		GROUP BY from_stop_order, to_stop_order, hour
+	`
+
+	var times []model.SegmentTravelTime
+	err := r.db.SelectContext(ctx, &times, query, routeId, since, timezone, maxSegmentSeconds)
+	if err != nil {
+		return nil, fault.New("failed to retrieve segment travel times", fault.WithError(err))
+	}
+
+	return times, nil
+}
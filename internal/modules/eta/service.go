@@ -0,0 +1,353 @@
+package eta
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/tracking"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/cache"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	etaServiceJourney = "eta service"
+	// historyWindow is how far back the segment travel times are computed from
+	historyWindow = time.Hour * 24 * 28
+	// segmentTimesTTL is how long the computed segment travel times are reused
+	segmentTimesTTL = time.Hour
+	// scheduledTrips is how many upcoming departures follow the live trips of a route
+	scheduledTrips = 5
+	// maxArrivals bounds the arrivals returned for a stop
+	maxArrivals = 50
+
+	SourceLive     = "live"
+	SourceSchedule = "schedule"
+)
+
+type ServiceConfig struct {
+	SegmentRepo     Repository
+	RouteService    route.Service
+	TrackingService tracking.Service
+	Cache           *cache.Cache
+}
+
+type service struct {
+	segmentRepo     Repository
+	routeService    route.Service
+	trackingService tracking.Service
+	cache           *cache.Cache
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		segmentRepo:     c.SegmentRepo,
+		routeService:    c.RouteService,
+		trackingService: c.TrackingService,
+		cache:           c.Cache,
+	}
+}
+
+func (s service) GetRouteETA(ctx context.Context, routeId string) (*dto.RouteETAResponse, error) {
+	r, err := s.routeService.GetRouteByID(ctx, routeId)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	vehicles, err := s.trackingService.GetVehiclePositions(ctx, routeId)
+	if err != nil {
+		return nil, err // The error is already being handled in the tracking service
+	}
+
+	trips, err := s.liveTrips(ctx, *r, vehicles)
+	if err != nil {
+		return nil, err // The error is already being handled in liveTrips
+	}
+
+	next, err := s.routeService.GetRouteNextDepartures(ctx, routeId, scheduledTrips)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	res := &dto.RouteETAResponse{
+		RouteID:   r.ID,
+		RouteName: r.Name,
+		Trips:     make([]dto.TripETAResponse, 0, len(trips)+len(next.Departures)),
+	}
+
+	running := make(map[string]bool, len(trips))
+	for _, trip := range trips {
+		if trip.departureId != nil {
+			running[runKey(*trip.departureId, trip.departsAt)] = true
+		}
+		res.Trips = append(res.Trips, newLiveTripResponse(trip))
+	}
+
+	now := route.Now()
+	for _, d := range next.Departures {
+		if running[runKey(d.DepartureID, d.DepartsAt)] {
+			continue
+		}
+
+		stops := make([]dto.StopETAResponse, len(d.Stops))
+		for i, st := range d.Stops {
+			stops[i] = dto.StopETAResponse{
+				LocationID: st.LocationID,
+				Name:       st.Name,
+				StopOrder:  st.StopOrder,
+				ArrivesAt:  st.EstimatedArrival,
+				Minutes:    minutesUntil(now, st.EstimatedArrival),
+			}
+		}
+		res.Trips = append(res.Trips, dto.TripETAResponse{
+			Source:      SourceSchedule,
+			DepartureID: &d.DepartureID,
+			Stops:       stops,
+		})
+	}
+
+	return res, nil
+}
+
+func (s service) GetStopETA(ctx context.Context, locationId string, limit int) (*dto.StopETAsResponse, error) {
+	limit = max(1, min(limit, maxArrivals))
+
+	next, err := s.routeService.GetStopNextDepartures(ctx, locationId, limit)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	vehicles, err := s.trackingService.GetVehiclePositions(ctx, "")
+	if err != nil {
+		return nil, err // The error is already being handled in the tracking service
+	}
+
+	routes, err := s.liveRoutes(ctx, vehicles, func(r dto.RouteResponse) bool {
+		return slices.ContainsFunc(r.Stops, func(st dto.RouteStopResponse) bool { return st.Location.ID == locationId })
+	})
+	if err != nil {
+		return nil, err // The error is already being handled in liveRoutes
+	}
+
+	res := &dto.StopETAsResponse{
+		Stop:     next.Stop,
+		Arrivals: make([]dto.StopArrivalETAResponse, 0, limit),
+	}
+
+	running := make(map[string]bool)
+	for _, rt := range routes {
+		for _, trip := range rt.trips {
+			if trip.departureId != nil {
+				running[runKey(*trip.departureId, trip.departsAt)] = true
+			}
+
+			i := slices.IndexFunc(trip.stops, func(st dto.StopETAResponse) bool { return st.LocationID == locationId })
+			if i < 0 {
+				continue
+			}
+			res.Arrivals = append(res.Arrivals, dto.StopArrivalETAResponse{
+				RouteID:     rt.route.ID,
+				RouteName:   rt.route.Name,
+				Source:      SourceLive,
+				VehicleID:   &trip.vehicle.VehicleID,
+				DepartureID: trip.departureId,
+				StopOrder:   trip.stops[i].StopOrder,
+				ArrivesAt:   trip.stops[i].ArrivesAt,
+				Minutes:     trip.stops[i].Minutes,
+			})
+		}
+	}
+
+	now := route.Now()
+	for _, d := range next.Departures {
+		if running[runKey(d.DepartureID, d.DepartsAt)] {
+			continue
+		}
+		res.Arrivals = append(res.Arrivals, dto.StopArrivalETAResponse{
+			RouteID:     d.RouteID,
+			RouteName:   d.RouteName,
+			Source:      SourceSchedule,
+			DepartureID: &d.DepartureID,
+			StopOrder:   d.StopOrder,
+			ArrivesAt:   d.EstimatedArrival,
+			Minutes:     minutesUntil(now, d.EstimatedArrival),
+		})
+	}
+
+	slices.SortStableFunc(res.Arrivals, func(a, b dto.StopArrivalETAResponse) int {
+		return a.ArrivesAt.Compare(b.ArrivesAt)
+	})
+	if len(res.Arrivals) > limit {
+		res.Arrivals = res.Arrivals[:limit]
+	}
+
+	return res, nil
+}
+
+func (s service) GetLiveTrips(ctx context.Context) ([]dto.RouteETAResponse, error) {
+	vehicles, err := s.trackingService.GetVehiclePositions(ctx, "")
+	if err != nil {
+		return nil, err // The error is already being handled in the tracking service
+	}
+
+	routes, err := s.liveRoutes(ctx, vehicles, func(dto.RouteResponse) bool { return true })
+	if err != nil {
+		return nil, err // The error is already being handled in liveRoutes
+	}
+
+	res := make([]dto.RouteETAResponse, 0, len(routes))
+	for _, rt := range routes {
+		if len(rt.trips) == 0 {
+			continue
+		}
+
+		trips := make([]dto.TripETAResponse, len(rt.trips))
+		for i, trip := range rt.trips {
+			trips[i] = newLiveTripResponse(trip)
+		}
+		res = append(res, dto.RouteETAResponse{
+			RouteID:   rt.route.ID,
+			RouteName: rt.route.Name,
+			Trips:     trips,
+		})
+	}
+
+	return res, nil
+}
+
+// routeTrips are the live trips of a route
+type routeTrips struct {
+	route dto.RouteResponse
+	trips []*liveTrip
+}
+
+// liveRoutes predicts the trips of the vehicles, grouped by route and sorted by
+// route ID. Only the routes for which keep returns true are predicted.
+func (s service) liveRoutes(ctx context.Context, vehicles []dto.VehiclePositionResponse, keep func(dto.RouteResponse) bool) ([]routeTrips, error) {
+	vehiclesByRoute := make(map[string][]dto.VehiclePositionResponse)
+	for _, v := range vehicles {
+		vehiclesByRoute[v.RouteID] = append(vehiclesByRoute[v.RouteID], v)
+	}
+
+	res := make([]routeTrips, 0, len(vehiclesByRoute))
+	for routeId, routeVehicles := range vehiclesByRoute {
+		r, err := s.routeService.GetRouteByID(ctx, routeId)
+		if err != nil {
+			// The route may have been removed while its vehicles are still live
+			if fault.GetTag(err) == fault.NOT_FOUND {
+				continue
+			}
+			return nil, err // The error is already being handled in the route service
+		}
+		if !keep(*r) {
+			continue
+		}
+
+		trips, err := s.liveTrips(ctx, *r, routeVehicles)
+		if err != nil {
+			return nil, err // The error is already being handled in liveTrips
+		}
+		res = append(res, routeTrips{route: *r, trips: trips})
+	}
+
+	slices.SortFunc(res, func(a, b routeTrips) int {
+		return strings.Compare(a.route.ID, b.route.ID)
+	})
+
+	return res, nil
+}
+
+// liveTrips predicts the trips of the route's vehicles still ahead of a stop,
+// matched to the departures of the day they are running
+func (s service) liveTrips(ctx context.Context, r dto.RouteResponse, vehicles []dto.VehiclePositionResponse) ([]*liveTrip, error) {
+	if len(vehicles) == 0 {
+		return nil, nil
+	}
+
+	now := route.Now()
+	times := s.segmentTimes(ctx, r.ID)
+
+	trips := make([]*liveTrip, 0, len(vehicles))
+	for _, v := range vehicles {
+		trip := predictTrip(r, times, v, now)
+		// Vehicles off the route or past its last stop have nothing to predict
+		if trip == nil || len(trip.stops) == 0 {
+			continue
+		}
+		trips = append(trips, trip)
+	}
+	if len(trips) == 0 {
+		return trips, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	timetable, err := s.routeService.GetRouteTimetable(ctx, r.ID, today)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	lastOrder := 0
+	for _, st := range r.Stops {
+		lastOrder = max(lastOrder, st.StopOrder)
+	}
+	matchDepartures(trips, timetable.Departures, today, r.TripLength, lastOrder)
+
+	return trips, nil
+}
+
+// segmentTimes returns the travel time history of the route's segments. It is
+// best effort: without history the predictions follow the timetable.
+func (s service) segmentTimes(ctx context.Context, routeId string) segmentTimes {
+	var rows []model.SegmentTravelTime
+	err := s.cache.GetStruct(ctx, segmentTimesKey(routeId), &rows)
+	if err == nil {
+		return newSegmentTimes(rows)
+	}
+	if fault.GetTag(err) != fault.CACHE_MISS {
+		logging.Error("failed to query segment travel times from cache", err,
+			zap.String("journey", etaServiceJourney))
+	}
+
+	rows, err = s.segmentRepo.GetSegmentTravelTimes(ctx, routeId, time.Now().Add(-historyWindow), route.Timezone().String())
+	if err != nil {
+		logging.Error("failed to retrieve segment travel times", err,
+			zap.String("journey", etaServiceJourney),
+			zap.String("routeID", routeId))
+		return nil
+	}
+
+	err = s.cache.SetStruct(ctx, segmentTimesKey(routeId), rows, segmentTimesTTL)
+	if err != nil {
+		logging.Error("failed to cache segment travel times", err,
+			zap.String("journey", etaServiceJourney))
+	}
+
+	return newSegmentTimes(rows)
+}
+
+func newLiveTripResponse(trip *liveTrip) dto.TripETAResponse {
+	return dto.TripETAResponse{
+		Source:      SourceLive,
+		VehicleID:   &trip.vehicle.VehicleID,
+		DepartureID: trip.departureId,
+		Stops:       trip.stops,
+	}
+}
+
+func segmentTimesKey(routeId string) string {
+	return fmt.Sprintf("eta:segments:%s", routeId)
+}
+
+// runKey identifies a single run of a departure, which repeats on every service
+// day, so a live trip only hides the scheduled run it is making
+func runKey(departureId string, departsAt time.Time) string {
+	return fmt.Sprintf("%s@%s", departureId, departsAt.In(route.Timezone()).Format(calendar.DateLayout))
+}
@@ -0,0 +1,23 @@
+package favorite
+
+import (
+	"context"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+type Repository interface {
+	Insert(ctx context.Context, favorite model.Favorite) error
+	GetByID(ctx context.Context, favoriteId string) (*model.Favorite, error)
+	GetAllByUserID(ctx context.Context, userId string) ([]model.Favorite, error)
+	UpdatePositions(ctx context.Context, userId string, favoriteIds []string) error
+	Delete(ctx context.Context, favoriteId string) error
+}
+
+type Service interface {
+	GetFavorites(ctx context.Context, userId string) ([]dto.FavoriteResponse, error)
+	AddFavorite(ctx context.Context, userId string, input dto.CreateFavorite) (*dto.FavoriteResponse, error)
+	ReorderFavorites(ctx context.Context, userId string, input dto.ReorderFavorites) ([]dto.FavoriteResponse, error)
+	RemoveFavorite(ctx context.Context, userId, favoriteId string) error
+}
@@ -0,0 +1,123 @@
+package favorite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) Insert(ctx context.Context, favorite model.Favorite) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO favorites (
+			id,
+			user_id,
+			kind,
+			route_id,
+			location_id,
+			position,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:user_id,
+			:kind,
+			:route_id,
+			:location_id,
+			:position,
+			:created_at,
+			:updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, favorite)
+	if err != nil {
+		return fault.New("failed to insert favorite", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) GetByID(ctx context.Context, favoriteId string) (*model.Favorite, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var favorite model.Favorite
+	err := r.db.GetContext(ctx, &favorite, "SELECT * FROM favorites WHERE id = $1 LIMIT 1", favoriteId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve favorite", fault.WithError(err))
+	}
+
+	return &favorite, nil
+}
+
+func (r repo) GetAllByUserID(ctx context.Context, userId string) ([]model.Favorite, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var favorites = make([]model.Favorite, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&favorites,
+		"SELECT * FROM favorites WHERE user_id = $1 ORDER BY position, created_at",
+		userId,
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve favorites", fault.WithError(err))
+	}
+
+	return favorites, nil
+}
+
+// UpdatePositions sets the position of each favorite to its index in favoriteIds
+func (r repo) UpdatePositions(ctx context.Context, userId string, favoriteIds []string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE favorites f
+		SET
+			position = o.position - 1,
+			updated_at = $3
+		FROM unnest($2::VARCHAR[]) WITH ORDINALITY AS o(id, position)
+		WHERE f.id = o.id AND f.user_id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userId, pq.Array(favoriteIds), time.Now())
+	if err != nil {
+		return fault.New("failed to update favorite positions", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) Delete(ctx context.Context, favoriteId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM favorites WHERE id = $1", favoriteId)
+	if err != nil {
+		return fault.New("failed to delete favorite", fault.WithError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package favorite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/cache"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const (
+	favoriteServiceJourney = "favorite service"
+	// maxFavorites caps how many favorites a user may keep
+	maxFavorites = 20
+	// favoriteDepartures is the number of next departures shown for each favorite
+	favoriteDepartures = 3
+	// favoritesTTL is short since the next departures go stale as buses leave
+	favoritesTTL = time.Minute
+
+	KindRoute = "route"
+	KindStop  = "stop"
+)
+
+type ServiceConfig struct {
+	FavoriteRepo Repository
+	RouteService route.Service
+	Cache        *cache.Cache
+}
+
+type service struct {
+	favoriteRepo Repository
+	routeService route.Service
+	cache        *cache.Cache
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		favoriteRepo: c.FavoriteRepo,
+		routeService: c.RouteService,
+		cache:        c.Cache,
+	}
+}
+
+func (s service) GetFavorites(ctx context.Context, userId string) ([]dto.FavoriteResponse, error) {
+	var cached []dto.FavoriteResponse
+	err := s.cache.GetStruct(ctx, cacheKey(userId), &cached)
+	if err == nil {
+		return cached, nil
+	}
+	if fault.GetTag(err) != fault.CACHE_MISS {
+		logging.Error("failed to query favorites from cache", err,
+			zap.String("journey", favoriteServiceJourney))
+	}
+
+	records, err := s.favoriteRepo.GetAllByUserID(ctx, userId)
+	if err != nil {
+		logging.Error("failed to retrieve favorites", err,
+			zap.String("journey", favoriteServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve favorites")
+	}
+
+	res := make([]dto.FavoriteResponse, 0, len(records))
+	for _, f := range records {
+		favorite, err := s.newFavoriteResponse(ctx, f)
+		if err != nil {
+			// The route or stop may be gone before the cascade reached the favorite
+			if fault.GetTag(err) == fault.NOT_FOUND {
+				continue
+			}
+			return nil, err // The error is already being handled in the route service
+		}
+		res = append(res, *favorite)
+	}
+
+	err = s.cache.SetStruct(ctx, cacheKey(userId), res, favoritesTTL)
+	if err != nil {
+		logging.Error("failed to cache favorites", err,
+			zap.String("journey", favoriteServiceJourney))
+	}
+
+	return res, nil
+}
+
+func (s service) AddFavorite(ctx context.Context, userId string, input dto.CreateFavorite) (*dto.FavoriteResponse, error) {
+	if (input.RouteID == nil) == (input.StopID == nil) {
+		return nil, fault.NewBadRequest("either route_id or stop_id must be given")
+	}
+
+	records, err := s.favoriteRepo.GetAllByUserID(ctx, userId)
+	if err != nil {
+		logging.Error("failed to retrieve favorites", err,
+			zap.String("journey", favoriteServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve favorites")
+	}
+	if len(records) >= maxFavorites {
+		return nil, fault.NewUnprocessableEntity(fmt.Sprintf("a user may keep at most %d favorites", maxFavorites))
+	}
+
+	now := time.Now()
+	favorite := model.Favorite{
+		ID:         uid.New("fav"),
+		UserID:     userId,
+		RouteID:    input.RouteID,
+		LocationID: input.StopID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if input.RouteID != nil {
+		favorite.Kind = KindRoute
+	} else {
+		favorite.Kind = KindStop
+	}
+
+	for _, f := range records {
+		if sameTarget(f, favorite) {
+			return nil, fault.NewConflict(fmt.Sprintf("%s already in favorites", favorite.Kind))
+		}
+		favorite.Position = max(favorite.Position, f.Position+1)
+	}
+
+	// Building the response first also checks the route or stop exists
+	res, err := s.newFavoriteResponse(ctx, favorite)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	err = s.favoriteRepo.Insert(ctx, favorite)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // 23505 is the code for unique constraint violation
+			return nil, fault.NewConflict(fmt.Sprintf("%s already in favorites", favorite.Kind))
+		}
+		logging.Error("failed to insert favorite", err,
+			zap.String("journey", favoriteServiceJourney))
+		return nil, fault.NewBadRequest("failed to add favorite")
+	}
+	s.invalidate(ctx, userId)
+
+	return res, nil
+}
+
+func (s service) ReorderFavorites(ctx context.Context, userId string, input dto.ReorderFavorites) ([]dto.FavoriteResponse, error) {
+	records, err := s.favoriteRepo.GetAllByUserID(ctx, userId)
+	if err != nil {
+		logging.Error("failed to retrieve favorites", err,
+			zap.String("journey", favoriteServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve favorites")
+	}
+
+	// The new order must list every favorite of the user exactly once
+	current := make([]string, len(records))
+	for i, f := range records {
+		current[i] = f.ID
+	}
+	given := slices.Clone(input.IDs)
+	slices.Sort(current)
+	slices.Sort(given)
+	if !slices.Equal(current, given) {
+		return nil, fault.NewUnprocessableEntity("ids must list every favorite exactly once")
+	}
+
+	err = s.favoriteRepo.UpdatePositions(ctx, userId, input.IDs)
+	if err != nil {
+		logging.Error("failed to reorder favorites", err,
+			zap.String("journey", favoriteServiceJourney))
+		return nil, fault.NewBadRequest("failed to reorder favorites")
+	}
+	s.invalidate(ctx, userId)
+
+	return s.GetFavorites(ctx, userId)
+}
+
+func (s service) RemoveFavorite(ctx context.Context, userId, favoriteId string) error {
+	record, err := s.favoriteRepo.GetByID(ctx, favoriteId)
+	if err != nil {
+		logging.Error("failed to retrieve favorite", err,
+			zap.String("journey", favoriteServiceJourney))
+		return fault.NewBadRequest("failed to retrieve favorite")
+	} else if record == nil || record.UserID != userId {
+		logging.Info("favorite not found",
+			zap.String("journey", favoriteServiceJourney),
+			zap.String("favoriteID", favoriteId))
+		return fault.NewNotFound("favorite not found")
+	}
+
+	err = s.favoriteRepo.Delete(ctx, favoriteId)
+	if err != nil {
+		logging.Error("failed to delete favorite", err,
+			zap.String("journey", favoriteServiceJourney))
+		return fault.NewBadRequest("failed to remove favorite")
+	}
+	s.invalidate(ctx, userId)
+
+	return nil
+}
+
+// newFavoriteResponse enriches the favorite with the next departures of its route or stop
+func (s service) newFavoriteResponse(ctx context.Context, f model.Favorite) (*dto.FavoriteResponse, error) {
+	res := &dto.FavoriteResponse{
+		ID:        f.ID,
+		Kind:      f.Kind,
+		Position:  f.Position,
+		CreatedAt: f.CreatedAt,
+	}
+
+	var err error
+	switch f.Kind {
+	case KindRoute:
+		res.Route, err = s.routeService.GetRouteNextDepartures(ctx, *f.RouteID, favoriteDepartures)
+	case KindStop:
+		res.Stop, err = s.routeService.GetStopNextDepartures(ctx, *f.LocationID, favoriteDepartures)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// invalidate drops the cached favorites of the user after they change
+func (s service) invalidate(ctx context.Context, userId string) {
+	err := s.cache.Delete(ctx, cacheKey(userId))
+	if err != nil {
+		logging.Error("failed to delete favorites from cache", err,
+			zap.String("journey", favoriteServiceJourney))
+	}
+}
+
+func sameTarget(a, b model.Favorite) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+	if a.Kind == KindRoute {
+		return *a.RouteID == *b.RouteID
+	}
+	return *a.LocationID == *b.LocationID
+}
+
+func cacheKey(userId string) string {
+	return fmt.Sprintf("fav:%s", userId)
+}
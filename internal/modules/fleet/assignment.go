@@ -0,0 +1,117 @@
+package fleet
+
+import (
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+)
+
+type assignment struct {
+	id          string
+	serviceDate time.Time
+	routeId     string
+	departureId string
+	vehicleId   string
+	driverId    string
+	startsAt    time.Time
+	endsAt      time.Time
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+// NewAssignment puts the vehicle and the driver on the departure of the service
+// date, which runs from startsAt to endsAt
+func NewAssignment(serviceDate time.Time, routeId, departureId, vehicleId, driverId string, startsAt, endsAt time.Time) (*assignment, error) {
+	now := time.Now()
+	a := assignment{
+		id:          uid.New("assignment"),
+		serviceDate: serviceDate,
+		routeId:     routeId,
+		departureId: departureId,
+		vehicleId:   vehicleId,
+		driverId:    driverId,
+		startsAt:    startsAt,
+		endsAt:      endsAt,
+		createdAt:   now,
+		updatedAt:   now,
+	}
+
+	if err := a.validate(); err != nil {
+		return nil, fault.New(
+			"failed to create assignment entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return &a, nil
+}
+
+func NewAssignmentFromModel(m model.VehicleAssignment) *assignment {
+	return &assignment{
+		id:          m.ID,
+		serviceDate: m.ServiceDate,
+		routeId:     m.RouteID,
+		departureId: m.DepartureID,
+		vehicleId:   m.VehicleID,
+		driverId:    m.DriverID,
+		startsAt:    m.StartsAt,
+		endsAt:      m.EndsAt,
+		createdAt:   m.CreatedAt,
+		updatedAt:   m.UpdatedAt,
+	}
+}
+
+// Reassign swaps the vehicle or the driver running the trip
+func (a *assignment) Reassign(vehicleId, driverId string) error {
+	a.vehicleId = vehicleId
+	a.driverId = driverId
+	a.updatedAt = time.Now()
+
+	if err := a.validate(); err != nil {
+		return fault.New(
+			"failed to update assignment entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return nil
+}
+
+func (a *assignment) validate() error {
+	if a.serviceDate.IsZero() {
+		return fault.New("date is required")
+	}
+	if a.routeId == "" || a.departureId == "" {
+		return fault.New("route and departure are required")
+	}
+	if a.vehicleId == "" {
+		return fault.New("vehicle is required")
+	}
+	if a.driverId == "" {
+		return fault.New("driver is required")
+	}
+	if !a.endsAt.After(a.startsAt) {
+		return fault.New("trip must end after it starts")
+	}
+
+	return nil
+}
+
+func (a *assignment) Model() model.VehicleAssignment {
+	return model.VehicleAssignment{
+		ID:          a.id,
+		ServiceDate: a.serviceDate,
+		RouteID:     a.routeId,
+		DepartureID: a.departureId,
+		VehicleID:   a.vehicleId,
+		DriverID:    a.driverId,
+		StartsAt:    a.startsAt,
+		EndsAt:      a.endsAt,
+		CreatedAt:   a.createdAt,
+		UpdatedAt:   a.updatedAt,
+	}
+}
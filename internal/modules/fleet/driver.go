@@ -0,0 +1,120 @@
+package fleet
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+)
+
+const (
+	maxNameLength = 255
+	// licenseNumberLength is the number of digits of a CNH
+	licenseNumberLength = 11
+	maxPhoneLength      = 20
+)
+
+type driver struct {
+	id            string
+	name          string
+	licenseNumber string
+	phone         *string
+	userId        *string
+	active        bool
+	createdAt     time.Time
+	updatedAt     time.Time
+}
+
+func NewDriver(name, licenseNumber string, phone, userId *string, active bool) (*driver, error) {
+	d := driver{
+		id:        uid.New("driver"),
+		createdAt: time.Now(),
+	}
+	d.set(name, licenseNumber, phone, userId, active)
+
+	if err := d.validate(); err != nil {
+		return nil, fault.New(
+			"failed to create driver entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return &d, nil
+}
+
+func NewDriverFromModel(m model.Driver) *driver {
+	return &driver{
+		id:            m.ID,
+		name:          m.Name,
+		licenseNumber: m.LicenseNumber,
+		phone:         m.Phone,
+		userId:        m.UserID,
+		active:        m.Active,
+		createdAt:     m.CreatedAt,
+		updatedAt:     m.UpdatedAt,
+	}
+}
+
+func (d *driver) Update(name, licenseNumber string, phone, userId *string, active bool) error {
+	d.set(name, licenseNumber, phone, userId, active)
+
+	if err := d.validate(); err != nil {
+		return fault.New(
+			"failed to update driver entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return nil
+}
+
+func (d *driver) set(name, licenseNumber string, phone, userId *string, active bool) {
+	if phone != nil && *phone == "" {
+		phone = nil
+	}
+	if userId != nil && *userId == "" {
+		userId = nil
+	}
+
+	d.name = strings.TrimSpace(name)
+	d.licenseNumber = strings.TrimSpace(licenseNumber)
+	d.phone = phone
+	d.userId = userId
+	d.active = active
+	d.updatedAt = time.Now()
+}
+
+func (d *driver) validate() error {
+	if d.name == "" {
+		return fault.New("name is required")
+	}
+	if len(d.name) > maxNameLength {
+		return fault.New("name is too long")
+	}
+	if len(d.licenseNumber) != licenseNumberLength || strings.ContainsFunc(d.licenseNumber, func(r rune) bool { return !unicode.IsDigit(r) }) {
+		return fault.New("license number must have 11 digits")
+	}
+	if d.phone != nil && len(*d.phone) > maxPhoneLength {
+		return fault.New("phone is too long")
+	}
+
+	return nil
+}
+
+func (d *driver) Model() model.Driver {
+	return model.Driver{
+		ID:            d.id,
+		Name:          d.name,
+		LicenseNumber: d.licenseNumber,
+		Phone:         d.phone,
+		UserID:        d.userId,
+		Active:        d.active,
+		CreatedAt:     d.createdAt,
+		UpdatedAt:     d.updatedAt,
+	}
+}
@@ -0,0 +1,314 @@
+package fleet
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/role"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	fleetHandlerJourney = "fleet handler"
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	fleetService Service
+	secretKey    string
+	sessions     middleware.SessionValidator
+}
+
+func NewHandler(fleetService Service, secretKey string, sessions middleware.SessionValidator) *handler {
+	once.Do(func() {
+		instance = &handler{
+			fleetService: fleetService,
+			secretKey:    secretKey,
+			sessions:     sessions,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
+
+	r.Route("/api/v1/fleet", func(r chi.Router) {
+		// Admin
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Get("/vehicles", h.handleGetVehicles)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Get("/vehicles/{vehicleId}", h.handleGetVehicle)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Post("/vehicles", h.handleCreateVehicle)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Put("/vehicles/{vehicleId}", h.handleUpdateVehicle)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Delete("/vehicles/{vehicleId}", h.handleDeleteVehicle)
+
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Get("/drivers", h.handleGetDrivers)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Get("/drivers/{driverId}", h.handleGetDriver)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Post("/drivers", h.handleCreateDriver)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Put("/drivers/{driverId}", h.handleUpdateDriver)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Delete("/drivers/{driverId}", h.handleDeleteDriver)
+
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Get("/assignments", h.handleGetAssignments)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Post("/assignments", h.handleCreateAssignment)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Put("/assignments/{assignmentId}", h.handleUpdateAssignment)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Delete("/assignments/{assignmentId}", h.handleDeleteAssignment)
+	})
+}
+
+func (h handler) handleGetVehicles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.fleetService.GetVehicles(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetVehicle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vehicleId := chi.URLParam(r, "vehicleId")
+
+	res, err := h.fleetService.GetVehicleByID(ctx, vehicleId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleCreateVehicle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.CreateVehicle
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.fleetService.CreateVehicle(ctx, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, res)
+}
+
+func (h handler) handleUpdateVehicle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vehicleId := chi.URLParam(r, "vehicleId")
+
+	var body dto.CreateVehicle
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.fleetService.UpdateVehicle(ctx, vehicleId, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleDeleteVehicle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vehicleId := chi.URLParam(r, "vehicleId")
+
+	err := h.fleetService.DeleteVehicle(ctx, vehicleId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+func (h handler) handleGetDrivers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.fleetService.GetDrivers(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetDriver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	driverId := chi.URLParam(r, "driverId")
+
+	res, err := h.fleetService.GetDriverByID(ctx, driverId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleCreateDriver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.CreateDriver
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.fleetService.CreateDriver(ctx, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, res)
+}
+
+func (h handler) handleUpdateDriver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	driverId := chi.URLParam(r, "driverId")
+
+	var body dto.CreateDriver
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.fleetService.UpdateDriver(ctx, driverId, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleDeleteDriver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	driverId := chi.URLParam(r, "driverId")
+
+	err := h.fleetService.DeleteDriver(ctx, driverId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+// handleGetAssignments lists the assignments of a date (YYYY-MM-DD, defaulting to today),
+// optionally of a single route_id, vehicle_id or driver_id
+func (h handler) handleGetAssignments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qs := r.URL.Query()
+
+	query := dto.AssignmentsQuery{
+		RouteID:   qs.Get("route_id"),
+		VehicleID: qs.Get("vehicle_id"),
+		DriverID:  qs.Get("driver_id"),
+	}
+	if v := qs.Get("date"); v != "" {
+		date, err := calendar.ParseDate(v)
+		if err != nil {
+			fault.NewHTTPError(w, err)
+			return
+		}
+		query.Date = &date
+	}
+
+	res, err := h.fleetService.GetAssignments(ctx, query)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleCreateAssignment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.CreateAssignment
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.fleetService.CreateAssignment(ctx, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, res)
+}
+
+func (h handler) handleUpdateAssignment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	assignmentId := chi.URLParam(r, "assignmentId")
+
+	var body dto.UpdateAssignment
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.fleetService.UpdateAssignment(ctx, assignmentId, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleDeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	assignmentId := chi.URLParam(r, "assignmentId")
+
+	err := h.fleetService.DeleteAssignment(ctx, assignmentId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+func logErrorInReadRequestBody(err error, r *http.Request) {
+	logging.Error("failed to read request body", err,
+		zap.String("journey", fleetHandlerJourney),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path))
+}
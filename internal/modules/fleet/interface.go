@@ -0,0 +1,55 @@
+package fleet
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+// AssignmentFilter narrows the assignments listed by GetAssignments, empty fields match every assignment
+type AssignmentFilter struct {
+	ServiceDate time.Time
+	RouteID     string
+	VehicleID   string
+	DriverID    string
+}
+
+type Repository interface {
+	GetAllVehicles(ctx context.Context) ([]model.Vehicle, error)
+	GetVehicleByID(ctx context.Context, vehicleId string) (*model.Vehicle, error)
+	InsertVehicle(ctx context.Context, vehicle model.Vehicle) error
+	UpdateVehicle(ctx context.Context, vehicle model.Vehicle) error
+	DeleteVehicle(ctx context.Context, vehicleId string) error
+	GetAllDrivers(ctx context.Context) ([]model.Driver, error)
+	GetDriverByID(ctx context.Context, driverId string) (*model.Driver, error)
+	InsertDriver(ctx context.Context, driver model.Driver) error
+	UpdateDriver(ctx context.Context, driver model.Driver) error
+	DeleteDriver(ctx context.Context, driverId string) error
+	GetAssignments(ctx context.Context, filter AssignmentFilter) ([]model.VehicleAssignment, error)
+	GetAssignmentByID(ctx context.Context, assignmentId string) (*model.VehicleAssignment, error)
+	// GetOverlappingAssignments returns the other assignments of the vehicle or the driver
+	// whose trips overlap the one of the given assignment
+	GetOverlappingAssignments(ctx context.Context, assignment model.VehicleAssignment) ([]model.VehicleAssignment, error)
+	InsertAssignment(ctx context.Context, assignment model.VehicleAssignment) error
+	UpdateAssignment(ctx context.Context, assignment model.VehicleAssignment) error
+	DeleteAssignment(ctx context.Context, assignmentId string) error
+}
+
+type Service interface {
+	GetVehicles(ctx context.Context) ([]dto.VehicleResponse, error)
+	GetVehicleByID(ctx context.Context, vehicleId string) (*dto.VehicleResponse, error)
+	CreateVehicle(ctx context.Context, input dto.CreateVehicle) (*dto.VehicleResponse, error)
+	UpdateVehicle(ctx context.Context, vehicleId string, input dto.CreateVehicle) (*dto.VehicleResponse, error)
+	DeleteVehicle(ctx context.Context, vehicleId string) error
+	GetDrivers(ctx context.Context) ([]dto.DriverResponse, error)
+	GetDriverByID(ctx context.Context, driverId string) (*dto.DriverResponse, error)
+	CreateDriver(ctx context.Context, input dto.CreateDriver) (*dto.DriverResponse, error)
+	UpdateDriver(ctx context.Context, driverId string, input dto.CreateDriver) (*dto.DriverResponse, error)
+	DeleteDriver(ctx context.Context, driverId string) error
+	GetAssignments(ctx context.Context, query dto.AssignmentsQuery) ([]dto.AssignmentResponse, error)
+	CreateAssignment(ctx context.Context, input dto.CreateAssignment) (*dto.AssignmentResponse, error)
+	UpdateAssignment(ctx context.Context, assignmentId string, input dto.UpdateAssignment) (*dto.AssignmentResponse, error)
+	DeleteAssignment(ctx context.Context, assignmentId string) error
+}
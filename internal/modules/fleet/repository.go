@@ -0,0 +1,354 @@
+package fleet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+)
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) GetAllVehicles(ctx context.Context) ([]model.Vehicle, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var vehicles = make([]model.Vehicle, 0)
+	err := r.db.SelectContext(ctx, &vehicles, "SELECT * FROM vehicles ORDER BY plate")
+	if err != nil {
+		return nil, fault.New("failed to retrieve vehicles", fault.WithError(err))
+	}
+
+	return vehicles, nil
+}
+
+func (r repo) GetVehicleByID(ctx context.Context, vehicleId string) (*model.Vehicle, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var vehicle model.Vehicle
+	err := r.db.GetContext(ctx, &vehicle, "SELECT * FROM vehicles WHERE id = $1 LIMIT 1", vehicleId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve vehicle", fault.WithError(err))
+	}
+
+	return &vehicle, nil
+}
+
+func (r repo) InsertVehicle(ctx context.Context, vehicle model.Vehicle) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO vehicles (
+			id,
+			plate,
+			capacity,
+			wheelchair_accessible,
+			low_floor,
+			status,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:plate,
+			:capacity,
+			:wheelchair_accessible,
+			:low_floor,
+			:status,
+			:created_at,
+			:updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, vehicle)
+	if err != nil {
+		return fault.New("failed to insert vehicle", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) UpdateVehicle(ctx context.Context, vehicle model.Vehicle) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE vehicles
+		SET
+			plate = :plate,
+			capacity = :capacity,
+			wheelchair_accessible = :wheelchair_accessible,
+			low_floor = :low_floor,
+			status = :status,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, vehicle)
+	if err != nil {
+		return fault.New("failed to update vehicle", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) DeleteVehicle(ctx context.Context, vehicleId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM vehicles WHERE id = $1", vehicleId)
+	if err != nil {
+		return fault.New("failed to delete vehicle", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) GetAllDrivers(ctx context.Context) ([]model.Driver, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var drivers = make([]model.Driver, 0)
+	err := r.db.SelectContext(ctx, &drivers, "SELECT * FROM drivers ORDER BY name, id")
+	if err != nil {
+		return nil, fault.New("failed to retrieve drivers", fault.WithError(err))
+	}
+
+	return drivers, nil
+}
+
+func (r repo) GetDriverByID(ctx context.Context, driverId string) (*model.Driver, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var driver model.Driver
+	err := r.db.GetContext(ctx, &driver, "SELECT * FROM drivers WHERE id = $1 LIMIT 1", driverId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve driver", fault.WithError(err))
+	}
+
+	return &driver, nil
+}
+
+func (r repo) InsertDriver(ctx context.Context, driver model.Driver) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO drivers (
+			id,
+			name,
+			license_number,
+			phone,
+			user_id,
+			active,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:name,
+			:license_number,
+			:phone,
+			:user_id,
+			:active,
+			:created_at,
+			:updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, driver)
+	if err != nil {
+		return fault.New("failed to insert driver", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) UpdateDriver(ctx context.Context, driver model.Driver) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE drivers
+		SET
+			name = :name,
+			license_number = :license_number,
+			phone = :phone,
+			user_id = :user_id,
+			active = :active,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, driver)
+	if err != nil {
+		return fault.New("failed to update driver", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) DeleteDriver(ctx context.Context, driverId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM drivers WHERE id = $1", driverId)
+	if err != nil {
+		return fault.New("failed to delete driver", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) GetAssignments(ctx context.Context, filter AssignmentFilter) ([]model.VehicleAssignment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		SELECT * FROM vehicle_assignments
+		WHERE service_date = $1
+			AND ($2 = '' OR route_id = $2)
+			AND ($3 = '' OR vehicle_id = $3)
+			AND ($4 = '' OR driver_id = $4)
+		ORDER BY starts_at, id
+	`
+
+	var assignments = make([]model.VehicleAssignment, 0)
+	err := r.db.SelectContext(ctx, &assignments, query, filter.ServiceDate, filter.RouteID, filter.VehicleID, filter.DriverID)
+	if err != nil {
+		return nil, fault.New("failed to retrieve vehicle assignments", fault.WithError(err))
+	}
+
+	return assignments, nil
+}
+
+func (r repo) GetAssignmentByID(ctx context.Context, assignmentId string) (*model.VehicleAssignment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var assignment model.VehicleAssignment
+	err := r.db.GetContext(ctx, &assignment, "SELECT * FROM vehicle_assignments WHERE id = $1 LIMIT 1", assignmentId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve vehicle assignment", fault.WithError(err))
+	}
+
+	return &assignment, nil
+}
+
+func (r repo) GetOverlappingAssignments(ctx context.Context, assignment model.VehicleAssignment) ([]model.VehicleAssignment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		SELECT * FROM vehicle_assignments
+		WHERE id <> $1
+			AND (vehicle_id = $2 OR driver_id = $3)
+			AND starts_at < $5
+			AND ends_at > $4
+		ORDER BY starts_at, id
+	`
+
+	var assignments = make([]model.VehicleAssignment, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&assignments,
+		query,
+		assignment.ID, assignment.VehicleID, assignment.DriverID, assignment.StartsAt, assignment.EndsAt,
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve overlapping vehicle assignments", fault.WithError(err))
+	}
+
+	return assignments, nil
+}
+
+func (r repo) InsertAssignment(ctx context.Context, assignment model.VehicleAssignment) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO vehicle_assignments (
+			id,
+			service_date,
+			route_id,
+			departure_id,
+			vehicle_id,
+			driver_id,
+			starts_at,
+			ends_at,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:service_date,
+			:route_id,
+			:departure_id,
+			:vehicle_id,
+			:driver_id,
+			:starts_at,
+			:ends_at,
+			:created_at,
+			:updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, assignment)
+	if err != nil {
+		return fault.New("failed to insert vehicle assignment", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) UpdateAssignment(ctx context.Context, assignment model.VehicleAssignment) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE vehicle_assignments
+		SET
+			vehicle_id = :vehicle_id,
+			driver_id = :driver_id,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, assignment)
+	if err != nil {
+		return fault.New("failed to update vehicle assignment", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) DeleteAssignment(ctx context.Context, assignmentId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM vehicle_assignments WHERE id = $1", assignmentId)
+	if err != nil {
+		return fault.New("failed to delete vehicle assignment", fault.WithError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,528 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const (
+	fleetServiceJourney = "fleet service"
+)
+
+type ServiceConfig struct {
+	FleetRepo    Repository
+	RouteService route.Service
+}
+
+type service struct {
+	fleetRepo    Repository
+	routeService route.Service
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		fleetRepo:    c.FleetRepo,
+		routeService: c.RouteService,
+	}
+}
+
+func (s service) GetVehicles(ctx context.Context) ([]dto.VehicleResponse, error) {
+	records, err := s.fleetRepo.GetAllVehicles(ctx)
+	if err != nil {
+		logging.Error("failed to retrieve vehicles", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve vehicles")
+	}
+
+	res := make([]dto.VehicleResponse, len(records))
+	for i, r := range records {
+		res[i] = newVehicleResponse(r)
+	}
+
+	return res, nil
+}
+
+func (s service) GetVehicleByID(ctx context.Context, vehicleId string) (*dto.VehicleResponse, error) {
+	record, err := s.getVehicle(ctx, vehicleId)
+	if err != nil {
+		return nil, err // The error is already being handled in getVehicle
+	}
+
+	res := newVehicleResponse(*record)
+	return &res, nil
+}
+
+func (s service) CreateVehicle(ctx context.Context, input dto.CreateVehicle) (*dto.VehicleResponse, error) {
+	vehicle, err := NewVehicle(input.Plate, input.Capacity, input.WheelchairAccessible, input.LowFloor, input.Status)
+	if err != nil {
+		logging.Error("failed to create vehicle", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to create vehicle entity")
+	}
+	model := vehicle.Model()
+
+	err = s.fleetRepo.InsertVehicle(ctx, model)
+	if err != nil {
+		return nil, s.writeError("failed to create vehicle", err)
+	}
+
+	res := newVehicleResponse(model)
+	return &res, nil
+}
+
+func (s service) UpdateVehicle(ctx context.Context, vehicleId string, input dto.CreateVehicle) (*dto.VehicleResponse, error) {
+	record, err := s.getVehicle(ctx, vehicleId)
+	if err != nil {
+		return nil, err // The error is already being handled in getVehicle
+	}
+
+	vehicle := NewVehicleFromModel(*record)
+	err = vehicle.Update(input.Plate, input.Capacity, input.WheelchairAccessible, input.LowFloor, input.Status)
+	if err != nil {
+		logging.Error("failed to update vehicle", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to update vehicle entity")
+	}
+	model := vehicle.Model()
+
+	err = s.fleetRepo.UpdateVehicle(ctx, model)
+	if err != nil {
+		return nil, s.writeError("failed to update vehicle", err)
+	}
+
+	res := newVehicleResponse(model)
+	return &res, nil
+}
+
+func (s service) DeleteVehicle(ctx context.Context, vehicleId string) error {
+	_, err := s.getVehicle(ctx, vehicleId)
+	if err != nil {
+		return err // The error is already being handled in getVehicle
+	}
+
+	err = s.fleetRepo.DeleteVehicle(ctx, vehicleId)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" { // 23503 is the code for foreign key violation
+			return fault.NewConflict("vehicle has assignments, retire it instead")
+		}
+		logging.Error("failed to delete vehicle", err,
+			zap.String("journey", fleetServiceJourney))
+		return fault.NewBadRequest("failed to delete vehicle")
+	}
+
+	return nil
+}
+
+func (s service) GetDrivers(ctx context.Context) ([]dto.DriverResponse, error) {
+	records, err := s.fleetRepo.GetAllDrivers(ctx)
+	if err != nil {
+		logging.Error("failed to retrieve drivers", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve drivers")
+	}
+
+	res := make([]dto.DriverResponse, len(records))
+	for i, r := range records {
+		res[i] = newDriverResponse(r)
+	}
+
+	return res, nil
+}
+
+func (s service) GetDriverByID(ctx context.Context, driverId string) (*dto.DriverResponse, error) {
+	record, err := s.getDriver(ctx, driverId)
+	if err != nil {
+		return nil, err // The error is already being handled in getDriver
+	}
+
+	res := newDriverResponse(*record)
+	return &res, nil
+}
+
+func (s service) CreateDriver(ctx context.Context, input dto.CreateDriver) (*dto.DriverResponse, error) {
+	active := input.Active == nil || *input.Active
+
+	driver, err := NewDriver(input.Name, input.LicenseNumber, input.Phone, input.UserID, active)
+	if err != nil {
+		logging.Error("failed to create driver", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to create driver entity")
+	}
+	model := driver.Model()
+
+	err = s.fleetRepo.InsertDriver(ctx, model)
+	if err != nil {
+		return nil, s.writeError("failed to create driver", err)
+	}
+
+	res := newDriverResponse(model)
+	return &res, nil
+}
+
+func (s service) UpdateDriver(ctx context.Context, driverId string, input dto.CreateDriver) (*dto.DriverResponse, error) {
+	record, err := s.getDriver(ctx, driverId)
+	if err != nil {
+		return nil, err // The error is already being handled in getDriver
+	}
+
+	active := input.Active == nil || *input.Active
+
+	driver := NewDriverFromModel(*record)
+	err = driver.Update(input.Name, input.LicenseNumber, input.Phone, input.UserID, active)
+	if err != nil {
+		logging.Error("failed to update driver", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to update driver entity")
+	}
+	model := driver.Model()
+
+	err = s.fleetRepo.UpdateDriver(ctx, model)
+	if err != nil {
+		return nil, s.writeError("failed to update driver", err)
+	}
+
+	res := newDriverResponse(model)
+	return &res, nil
+}
+
+func (s service) DeleteDriver(ctx context.Context, driverId string) error {
+	_, err := s.getDriver(ctx, driverId)
+	if err != nil {
+		return err // The error is already being handled in getDriver
+	}
+
+	err = s.fleetRepo.DeleteDriver(ctx, driverId)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" { // 23503 is the code for foreign key violation
+			return fault.NewConflict("driver has assignments, deactivate them instead")
+		}
+		logging.Error("failed to delete driver", err,
+			zap.String("journey", fleetServiceJourney))
+		return fault.NewBadRequest("failed to delete driver")
+	}
+
+	return nil
+}
+
+func (s service) GetAssignments(ctx context.Context, query dto.AssignmentsQuery) ([]dto.AssignmentResponse, error) {
+	date := today()
+	if query.Date != nil {
+		date = *query.Date
+	}
+
+	records, err := s.fleetRepo.GetAssignments(ctx, AssignmentFilter{
+		ServiceDate: date,
+		RouteID:     query.RouteID,
+		VehicleID:   query.VehicleID,
+		DriverID:    query.DriverID,
+	})
+	if err != nil {
+		logging.Error("failed to retrieve vehicle assignments", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve assignments")
+	}
+
+	res := make([]dto.AssignmentResponse, len(records))
+	for i, r := range records {
+		res[i] = newAssignmentResponse(r)
+	}
+
+	return res, nil
+}
+
+func (s service) CreateAssignment(ctx context.Context, input dto.CreateAssignment) (*dto.AssignmentResponse, error) {
+	date, err := calendar.ParseDate(input.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.routeService.GetRouteByID(ctx, input.RouteID)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+	if r.TripLength <= 0 {
+		return nil, fault.NewUnprocessableEntity("route has no trip length to schedule its trips")
+	}
+
+	// The day of the timetable, whose departures are in its timezone
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, route.Timezone())
+	timetable, err := s.routeService.GetRouteTimetable(ctx, r.ID, day)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	var departure *dto.DepartureTimeResponse
+	for _, d := range timetable.Departures {
+		if d.ID == input.DepartureID {
+			departure = &d
+			break
+		}
+	}
+	if departure == nil {
+		return nil, fault.NewUnprocessableEntity("departure does not run on this date")
+	}
+
+	err = s.checkAssignable(ctx, input.VehicleID, input.DriverID)
+	if err != nil {
+		return nil, err // The error is already being handled in checkAssignable
+	}
+
+	startsAt := departure.Time.On(day)
+	endsAt := startsAt.Add(time.Duration(r.TripLength * float64(time.Minute)))
+
+	assignment, err := NewAssignment(date, r.ID, departure.ID, input.VehicleID, input.DriverID, startsAt, endsAt)
+	if err != nil {
+		logging.Error("failed to create vehicle assignment", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to create assignment entity")
+	}
+	model := assignment.Model()
+
+	err = s.checkOverlap(ctx, model)
+	if err != nil {
+		return nil, err // The error is already being handled in checkOverlap
+	}
+
+	err = s.fleetRepo.InsertAssignment(ctx, model)
+	if err != nil {
+		return nil, s.writeError("failed to create assignment", err)
+	}
+
+	res := newAssignmentResponse(model)
+	return &res, nil
+}
+
+func (s service) UpdateAssignment(ctx context.Context, assignmentId string, input dto.UpdateAssignment) (*dto.AssignmentResponse, error) {
+	record, err := s.getAssignment(ctx, assignmentId)
+	if err != nil {
+		return nil, err // The error is already being handled in getAssignment
+	}
+
+	err = s.checkAssignable(ctx, input.VehicleID, input.DriverID)
+	if err != nil {
+		return nil, err // The error is already being handled in checkAssignable
+	}
+
+	assignment := NewAssignmentFromModel(*record)
+	err = assignment.Reassign(input.VehicleID, input.DriverID)
+	if err != nil {
+		logging.Error("failed to update vehicle assignment", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to update assignment entity")
+	}
+	model := assignment.Model()
+
+	err = s.checkOverlap(ctx, model)
+	if err != nil {
+		return nil, err // The error is already being handled in checkOverlap
+	}
+
+	err = s.fleetRepo.UpdateAssignment(ctx, model)
+	if err != nil {
+		return nil, s.writeError("failed to update assignment", err)
+	}
+
+	res := newAssignmentResponse(model)
+	return &res, nil
+}
+
+func (s service) DeleteAssignment(ctx context.Context, assignmentId string) error {
+	_, err := s.getAssignment(ctx, assignmentId)
+	if err != nil {
+		return err // The error is already being handled in getAssignment
+	}
+
+	err = s.fleetRepo.DeleteAssignment(ctx, assignmentId)
+	if err != nil {
+		logging.Error("failed to delete vehicle assignment", err,
+			zap.String("journey", fleetServiceJourney))
+		return fault.NewBadRequest("failed to delete assignment")
+	}
+
+	return nil
+}
+
+// checkAssignable makes sure the vehicle and the driver exist and are in service
+func (s service) checkAssignable(ctx context.Context, vehicleId, driverId string) error {
+	if vehicleId == "" || driverId == "" {
+		return fault.NewBadRequest("vehicle_id and driver_id are required")
+	}
+
+	vehicle, err := s.getVehicle(ctx, vehicleId)
+	if err != nil {
+		return err // The error is already being handled in getVehicle
+	}
+	if vehicle.Status != VehicleActive {
+		return fault.NewUnprocessableEntity("vehicle is not active")
+	}
+
+	driver, err := s.getDriver(ctx, driverId)
+	if err != nil {
+		return err // The error is already being handled in getDriver
+	}
+	if !driver.Active {
+		return fault.NewUnprocessableEntity("driver is not active")
+	}
+
+	return nil
+}
+
+// checkOverlap rejects the assignment when its vehicle or its driver is already
+// on another trip running at the same time. The exclusion constraints of
+// vehicle_assignments catch concurrent requests that both pass this check.
+func (s service) checkOverlap(ctx context.Context, assignment model.VehicleAssignment) error {
+	overlapping, err := s.fleetRepo.GetOverlappingAssignments(ctx, assignment)
+	if err != nil {
+		logging.Error("failed to retrieve overlapping vehicle assignments", err,
+			zap.String("journey", fleetServiceJourney))
+		return fault.NewBadRequest("failed to check assignments")
+	}
+
+	for _, o := range overlapping {
+		if o.VehicleID == assignment.VehicleID {
+			return fault.NewConflict("vehicle already assigned to an overlapping trip")
+		}
+	}
+	if len(overlapping) > 0 {
+		return fault.NewConflict("driver already assigned to an overlapping trip")
+	}
+
+	return nil
+}
+
+func (s service) getVehicle(ctx context.Context, vehicleId string) (*model.Vehicle, error) {
+	record, err := s.fleetRepo.GetVehicleByID(ctx, vehicleId)
+	if err != nil {
+		logging.Error("failed to retrieve vehicle", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve vehicle")
+	} else if record == nil {
+		logging.Info("vehicle not found",
+			zap.String("journey", fleetServiceJourney),
+			zap.String("vehicleID", vehicleId))
+		return nil, fault.NewNotFound("vehicle not found")
+	}
+
+	return record, nil
+}
+
+func (s service) getDriver(ctx context.Context, driverId string) (*model.Driver, error) {
+	record, err := s.fleetRepo.GetDriverByID(ctx, driverId)
+	if err != nil {
+		logging.Error("failed to retrieve driver", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve driver")
+	} else if record == nil {
+		logging.Info("driver not found",
+			zap.String("journey", fleetServiceJourney),
+			zap.String("driverID", driverId))
+		return nil, fault.NewNotFound("driver not found")
+	}
+
+	return record, nil
+}
+
+func (s service) getAssignment(ctx context.Context, assignmentId string) (*model.VehicleAssignment, error) {
+	record, err := s.fleetRepo.GetAssignmentByID(ctx, assignmentId)
+	if err != nil {
+		logging.Error("failed to retrieve vehicle assignment", err,
+			zap.String("journey", fleetServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve assignment")
+	} else if record == nil {
+		logging.Info("vehicle assignment not found",
+			zap.String("journey", fleetServiceJourney),
+			zap.String("assignmentID", assignmentId))
+		return nil, fault.NewNotFound("assignment not found")
+	}
+
+	return record, nil
+}
+
+// writeError turns the constraint violations of the fleet tables into client errors
+func (s service) writeError(msg string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Constraint {
+		case "vehicles_plate_key":
+			return fault.NewConflict("plate already registered")
+		case "drivers_license_number_key":
+			return fault.NewConflict("license number already registered")
+		case "drivers_user_id_key":
+			return fault.NewConflict("user already linked to another driver")
+		case "fk_drivers_user_id":
+			return fault.NewUnprocessableEntity("unknown user")
+		case "idx_vehicle_assignments_service_date_departure_id":
+			return fault.NewConflict("departure already assigned on this date")
+		case "excl_vehicle_assignments_vehicle_period":
+			return fault.NewConflict("vehicle already assigned to an overlapping trip")
+		case "excl_vehicle_assignments_driver_period":
+			return fault.NewConflict("driver already assigned to an overlapping trip")
+		}
+		if pqErr.Code == "23P01" { // exclusion_violation, another overlapping assignment won the race
+			return fault.NewConflict("assignment overlaps another trip")
+		}
+	}
+
+	logging.Error(msg, err,
+		zap.String("journey", fleetServiceJourney))
+	return fault.NewBadRequest(msg)
+}
+
+// today is the current service date, a date being stored at midnight UTC
+func today() time.Time {
+	y, m, d := route.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func newVehicleResponse(v model.Vehicle) dto.VehicleResponse {
+	return dto.VehicleResponse{
+		ID:                   v.ID,
+		Plate:                v.Plate,
+		Capacity:             v.Capacity,
+		WheelchairAccessible: v.WheelchairAccessible,
+		LowFloor:             v.LowFloor,
+		Status:               v.Status,
+		CreatedAt:            v.CreatedAt,
+		UpdatedAt:            v.UpdatedAt,
+	}
+}
+
+func newDriverResponse(d model.Driver) dto.DriverResponse {
+	return dto.DriverResponse{
+		ID:            d.ID,
+		Name:          d.Name,
+		LicenseNumber: d.LicenseNumber,
+		Phone:         d.Phone,
+		UserID:        d.UserID,
+		Active:        d.Active,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+}
+
+func newAssignmentResponse(a model.VehicleAssignment) dto.AssignmentResponse {
+	return dto.AssignmentResponse{
+		ID:          a.ID,
+		Date:        a.ServiceDate.Format(calendar.DateLayout),
+		RouteID:     a.RouteID,
+		DepartureID: a.DepartureID,
+		VehicleID:   a.VehicleID,
+		DriverID:    a.DriverID,
+		StartsAt:    a.StartsAt,
+		EndsAt:      a.EndsAt,
+		CreatedAt:   a.CreatedAt,
+		UpdatedAt:   a.UpdatedAt,
+	}
+}
@@ -0,0 +1,132 @@
+package fleet
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+)
+
+const (
+	// VehicleActive is a vehicle in service, the only one that may be assigned to trips
+	VehicleActive = "active"
+	// VehicleMaintenance is a vehicle temporarily out of service
+	VehicleMaintenance = "maintenance"
+	// VehicleRetired is a vehicle that left the fleet, kept for its past assignments
+	VehicleRetired = "retired"
+
+	maxCapacity = 300
+)
+
+var (
+	vehicleStatuses = []string{VehicleActive, VehicleMaintenance, VehicleRetired}
+	// platePattern matches both the old (ABC1234) and the Mercosul (ABC1D23) plates
+	platePattern = regexp.MustCompile(`^[A-Z]{3}[0-9][A-Z0-9][0-9]{2}$`)
+)
+
+type vehicle struct {
+	id                   string
+	plate                string
+	capacity             int
+	wheelchairAccessible bool
+	lowFloor             bool
+	status               string
+	createdAt            time.Time
+	updatedAt            time.Time
+}
+
+func NewVehicle(plate string, capacity int, wheelchairAccessible, lowFloor bool, status string) (*vehicle, error) {
+	now := time.Now()
+	v := vehicle{
+		id:        uid.New("vehicle"),
+		createdAt: now,
+	}
+	v.set(plate, capacity, wheelchairAccessible, lowFloor, status)
+
+	if err := v.validate(); err != nil {
+		return nil, fault.New(
+			"failed to create vehicle entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return &v, nil
+}
+
+func NewVehicleFromModel(m model.Vehicle) *vehicle {
+	return &vehicle{
+		id:                   m.ID,
+		plate:                m.Plate,
+		capacity:             m.Capacity,
+		wheelchairAccessible: m.WheelchairAccessible,
+		lowFloor:             m.LowFloor,
+		status:               m.Status,
+		createdAt:            m.CreatedAt,
+		updatedAt:            m.UpdatedAt,
+	}
+}
+
+func (v *vehicle) Update(plate string, capacity int, wheelchairAccessible, lowFloor bool, status string) error {
+	v.set(plate, capacity, wheelchairAccessible, lowFloor, status)
+
+	if err := v.validate(); err != nil {
+		return fault.New(
+			"failed to update vehicle entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return nil
+}
+
+func (v *vehicle) set(plate string, capacity int, wheelchairAccessible, lowFloor bool, status string) {
+	if status == "" {
+		status = VehicleActive
+	}
+
+	v.plate = normalizePlate(plate)
+	v.capacity = capacity
+	v.wheelchairAccessible = wheelchairAccessible
+	v.lowFloor = lowFloor
+	v.status = status
+	v.updatedAt = time.Now()
+}
+
+func (v *vehicle) validate() error {
+	if !platePattern.MatchString(v.plate) {
+		return fault.New("plate must be in the ABC1234 or ABC1D23 format")
+	}
+	if v.capacity < 1 || v.capacity > maxCapacity {
+		return fault.New("capacity must be between 1 and 300")
+	}
+	if !slices.Contains(vehicleStatuses, v.status) {
+		return fault.New("status must be one of active, maintenance or retired")
+	}
+
+	return nil
+}
+
+func (v *vehicle) Model() model.Vehicle {
+	return model.Vehicle{
+		ID:                   v.id,
+		Plate:                v.plate,
+		Capacity:             v.capacity,
+		WheelchairAccessible: v.wheelchairAccessible,
+		LowFloor:             v.lowFloor,
+		Status:               v.status,
+		CreatedAt:            v.createdAt,
+		UpdatedAt:            v.updatedAt,
+	}
+}
+
+// normalizePlate accepts plates typed in lowercase or with a dash, as ABC-1234
+func normalizePlate(plate string) string {
+	plate = strings.ToUpper(strings.TrimSpace(plate))
+	return strings.NewReplacer("-", "", " ", "").Replace(plate)
+}
@@ -0,0 +1,269 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// AgencyID identifies the BUZUFBA service in the feed
+	AgencyID = "buzufba"
+
+	agencyName = "BUZUFBA"
+	agencyURL  = "https://ufba.br"
+	agencyLang = "pt"
+
+	// routeTypeBus is the GTFS route_type for buses
+	routeTypeBus = 3
+
+	// dateLayout is the GTFS date format
+	dateLayout = "20060102"
+)
+
+// GTFS exception_type values of calendar_dates.txt
+const (
+	serviceAdded   = 1
+	serviceRemoved = 2
+)
+
+// Feed holds the tables of a GTFS static feed
+type Feed struct {
+	Agencies      []Agency
+	Stops         []Stop
+	Routes        []Route
+	Trips         []Trip
+	StopTimes     []StopTime
+	Calendars     []Calendar
+	CalendarDates []CalendarDate
+}
+
+type Agency struct {
+	ID       string
+	Name     string
+	URL      string
+	Timezone string
+	Lang     string
+}
+
+type Stop struct {
+	ID        string
+	Name      string
+	Latitude  *float64
+	Longitude *float64
+}
+
+type Route struct {
+	ID        string
+	AgencyID  string
+	ShortName string
+	LongName  string
+	Type      int
+}
+
+type Trip struct {
+	ID        string
+	RouteID   string
+	ServiceID string
+	Headsign  string
+}
+
+type StopTime struct {
+	TripID string
+	// Time is the arrival and departure time at the stop, counted from the
+	// midnight of the service day. It may go past 24h.
+	Time         time.Duration
+	StopID       string
+	Sequence     int
+	NoPickup     bool
+	NoDropOff    bool
+	Approximated bool
+}
+
+type Calendar struct {
+	ServiceID string
+	Weekdays  [7]bool // indexed by time.Weekday
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+type CalendarDate struct {
+	ServiceID string
+	Date      time.Time
+	Added     bool
+}
+
+// WriteZip encodes the feed as a GTFS zip archive. The output only depends on
+// the feed contents and modified, so equal feeds always produce equal bytes.
+func (f *Feed) WriteZip(modified time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	files := []struct {
+		name    string
+		records [][]string
+	}{
+		{"agency.txt", f.agencyRecords()},
+		{"stops.txt", f.stopRecords()},
+		{"routes.txt", f.routeRecords()},
+		{"trips.txt", f.tripRecords()},
+		{"stop_times.txt", f.stopTimeRecords()},
+		{"calendar.txt", f.calendarRecords()},
+		{"calendar_dates.txt", f.calendarDateRecords()},
+	}
+
+	for _, file := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: modified,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", file.name, err)
+		}
+
+		cw := csv.NewWriter(w)
+		cw.UseCRLF = true
+		err = cw.WriteAll(file.records)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	err := zw.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to close gtfs archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (f *Feed) agencyRecords() [][]string {
+	records := [][]string{{"agency_id", "agency_name", "agency_url", "agency_timezone", "agency_lang"}}
+	for _, a := range f.Agencies {
+		records = append(records, []string{a.ID, a.Name, a.URL, a.Timezone, a.Lang})
+	}
+	return records
+}
+
+func (f *Feed) stopRecords() [][]string {
+	records := [][]string{{"stop_id", "stop_name", "stop_lat", "stop_lon"}}
+	for _, s := range f.Stops {
+		records = append(records, []string{s.ID, s.Name, formatCoordinate(s.Latitude), formatCoordinate(s.Longitude)})
+	}
+	return records
+}
+
+func (f *Feed) routeRecords() [][]string {
+	records := [][]string{{"route_id", "agency_id", "route_short_name", "route_long_name", "route_type"}}
+	for _, r := range f.Routes {
+		records = append(records, []string{r.ID, r.AgencyID, r.ShortName, r.LongName, strconv.Itoa(r.Type)})
+	}
+	return records
+}
+
+func (f *Feed) tripRecords() [][]string {
+	records := [][]string{{"route_id", "service_id", "trip_id", "trip_headsign"}}
+	for _, t := range f.Trips {
+		records = append(records, []string{t.RouteID, t.ServiceID, t.ID, t.Headsign})
+	}
+	return records
+}
+
+func (f *Feed) stopTimeRecords() [][]string {
+	records := [][]string{{
+		"trip_id", "arrival_time", "departure_time", "stop_id", "stop_sequence",
+		"pickup_type", "drop_off_type", "timepoint",
+	}}
+	for _, st := range f.StopTimes {
+		t := formatTime(st.Time)
+		records = append(records, []string{
+			st.TripID, t, t, st.StopID, strconv.Itoa(st.Sequence),
+			formatFlag(st.NoPickup), formatFlag(st.NoDropOff), formatFlag(!st.Approximated),
+		})
+	}
+	return records
+}
+
+func (f *Feed) calendarRecords() [][]string {
+	records := [][]string{{
+		"service_id", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+		"start_date", "end_date",
+	}}
+	for _, c := range f.Calendars {
+		records = append(records, []string{
+			c.ServiceID,
+			formatFlag(c.Weekdays[time.Monday]),
+			formatFlag(c.Weekdays[time.Tuesday]),
+			formatFlag(c.Weekdays[time.Wednesday]),
+			formatFlag(c.Weekdays[time.Thursday]),
+			formatFlag(c.Weekdays[time.Friday]),
+			formatFlag(c.Weekdays[time.Saturday]),
+			formatFlag(c.Weekdays[time.Sunday]),
+			c.StartDate.Format(dateLayout),
+			c.EndDate.Format(dateLayout),
+		})
+	}
+	return records
+}
+
+func (f *Feed) calendarDateRecords() [][]string {
+	records := [][]string{{"service_id", "date", "exception_type"}}
+	for _, d := range f.CalendarDates {
+		exceptionType := serviceRemoved
+		if d.Added {
+			exceptionType = serviceAdded
+		}
+		records = append(records, []string{d.ServiceID, d.Date.Format(dateLayout), strconv.Itoa(exceptionType)})
+	}
+	return records
+}
+
+// formatTime formats a duration since midnight as the GTFS HH:MM:SS
+func formatTime(d time.Duration) string {
+	seconds := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// parseTime parses a GTFS HH:MM:SS time, hours may go past 24
+func parseTime(s string) (time.Duration, error) {
+	var h, m, sec int
+	_, err := fmt.Sscanf(s, "%d:%d:%d", &h, &m, &sec)
+	if err != nil || m > 59 || sec > 59 || h < 0 || m < 0 || sec < 0 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sec)*time.Second, nil
+}
+
+func formatCoordinate(c *float64) string {
+	if c == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*c, 'f', 6, 64)
+}
+
+func formatFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// Archive is an encoded GTFS feed ready to be served
+type Archive struct {
+	Data []byte
+	// ETag is the quoted sha256 of the data
+	ETag string
+}
+
+func newArchive(data []byte) *Archive {
+	return &Archive{
+		Data: data,
+		ETag: fmt.Sprintf("%q", fmt.Sprintf("%x", sha256.Sum256(data))),
+	}
+}
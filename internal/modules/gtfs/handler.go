@@ -0,0 +1,185 @@
+package gtfs
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/role"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/timetable"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	gtfsHandlerJourney = "gtfs handler"
+	// staticFeedFilename is the name suggested to clients downloading the feed
+	staticFeedFilename = "buzufba-gtfs.zip"
+	// staticFeedMaxAge lets clients reuse the feed without revalidating, matching the cache TTL
+	staticFeedMaxAge = "public, max-age=900"
+	// maxImportBytes caps the size of an uploaded GTFS archive
+	maxImportBytes = 32 << 20 // 32MB
+	// realtimeFeedMaxAge keeps consumers polling often enough to follow changes
+	realtimeFeedMaxAge = "public, max-age=30"
+	// liveFeedMaxAge is shorter for the feeds following the vehicles, which report every few seconds
+	liveFeedMaxAge = "public, max-age=5"
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	gtfsService Service
+	secretKey   string
+	sessions    middleware.SessionValidator
+}
+
+func NewHandler(gtfsService Service, secretKey string, sessions middleware.SessionValidator) *handler {
+	once.Do(func() {
+		instance = &handler{
+			gtfsService: gtfsService,
+			secretKey:   secretKey,
+			sessions:    sessions,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
+
+	// Public
+	r.Get("/api/v1/gtfs.zip", h.handleGetStaticFeed)
+	r.Get("/api/v1/gtfs-rt/alerts", h.handleGetAlertsFeed)
+	r.Get("/api/v1/gtfs-rt/vehicle-positions", h.handleGetVehiclePositionsFeed)
+	r.Get("/api/v1/gtfs-rt/trip-updates", h.handleGetTripUpdatesFeed)
+	// Admin
+	r.With(m.WithAuth, m.WithRole(role.Admin)).Post("/api/v1/gtfs/import", h.handleImportStaticFeed)
+}
+
+func (h handler) handleGetStaticFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	archive, err := h.gtfsService.GetStaticFeed(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", archive.ETag)
+	w.Header().Set("Cache-Control", staticFeedMaxAge)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+staticFeedFilename+`"`)
+
+	// ServeContent answers If-None-Match with 304 Not Modified based on the ETag above
+	http.ServeContent(w, r, staticFeedFilename, time.Time{}, bytes.NewReader(archive.Data))
+}
+
+// handleImportStaticFeed imports the GTFS archive sent in the "file" field of a
+// multipart form. With ?dry_run=true nothing is written and only the diff is returned,
+// with ?replace=true the routes missing from the feed are removed.
+func (h handler) handleImportStaticFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qs := r.URL.Query()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		logging.Error("failed to read uploaded gtfs archive", err,
+			zap.String("journey", gtfsHandlerJourney),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		fault.NewHTTPError(w, fault.NewBadRequest("a gtfs zip must be sent in the file field"))
+		return
+	}
+	defer file.Close()
+
+	archive, err := io.ReadAll(file)
+	if err != nil {
+		fault.NewHTTPError(w, fault.NewBadRequest("failed to read uploaded gtfs archive"))
+		return
+	}
+
+	res, err := h.gtfsService.ImportStaticFeed(ctx, archive, timetable.Options{
+		DryRun:  httputil.ReadQueryBool(qs, "dry_run", false),
+		Replace: httputil.ReadQueryBool(qs, "replace", false),
+	})
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetAlertsFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	feed, err := h.gtfsService.GetAlertsFeed(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	writeRealtimeFeed(w, r, feed, realtimeFeedMaxAge)
+}
+
+func (h handler) handleGetVehiclePositionsFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	feed, err := h.gtfsService.GetVehiclePositionsFeed(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	writeRealtimeFeed(w, r, feed, liveFeedMaxAge)
+}
+
+func (h handler) handleGetTripUpdatesFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	feed, err := h.gtfsService.GetTripUpdatesFeed(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	writeRealtimeFeed(w, r, feed, liveFeedMaxAge)
+}
+
+// writeRealtimeFeed writes the feed as protobuf, or as JSON for debugging with ?format=json,
+// letting consumers cache it for maxAge
+func writeRealtimeFeed(w http.ResponseWriter, r *http.Request, feed proto.Message, maxAge string) {
+	asJSON := httputil.ReadQueryString(r.URL.Query(), "format", "") == "json"
+
+	var data []byte
+	var err error
+	if asJSON {
+		data, err = protojson.MarshalOptions{Multiline: true}.Marshal(feed)
+	} else {
+		data, err = proto.Marshal(feed)
+	}
+	if err != nil {
+		logging.Error("failed to encode realtime feed", err,
+			zap.String("journey", gtfsHandlerJourney),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		fault.NewHTTPError(w, fault.NewInternalServerError("failed to encode realtime feed"))
+		return
+	}
+
+	if asJSON {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", RealtimeContentType)
+	}
+	w.Header().Set("Cache-Control", maxAge)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
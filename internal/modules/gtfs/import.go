@@ -0,0 +1,256 @@
+package gtfs
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/timetable"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/timeofday"
+)
+
+// importedStopTime is a stop_times.txt row of a trip being imported
+type importedStopTime struct {
+	stopId   string
+	sequence int
+	time     time.Duration
+	hasTime  bool
+	pickup   bool
+	dropOff  bool
+}
+
+// ToTimetable maps a GTFS static feed to the timetable imported by the seed command.
+//
+// The route tables keep a single stop sequence per route, so each route takes the
+// stop pattern shared by most of its trips and its departures are the first stop
+// times of the trips following that pattern. Trips with other patterns are counted
+// in skipped. Stops become locations matched by name and every service referenced
+// by the trips becomes a calendar; calendar_dates.txt is not imported.
+func ToTimetable(archive []byte) (tt *timetable.Timetable, skipped int, err error) {
+	tables, err := readTables(archive)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, required := range requiredColumns {
+		if _, ok := tables[required.file]; !ok {
+			return nil, 0, fmt.Errorf("missing required file %s", required.file)
+		}
+	}
+
+	stops := tables["stops.txt"]
+	type location struct {
+		name      string
+		latitude  *float64
+		longitude *float64
+	}
+	locations := make(map[string]location, len(stops.rows))
+	for _, row := range stops.rows {
+		locations[stops.get(row, "stop_id")] = location{
+			name:      stops.get(row, "stop_name"),
+			latitude:  parseCoordinate(stops.get(row, "stop_lat")),
+			longitude: parseCoordinate(stops.get(row, "stop_lon")),
+		}
+	}
+
+	stopTimes := tables["stop_times.txt"]
+	tripStops := make(map[string][]importedStopTime)
+	for i, row := range stopTimes.rows {
+		sequence, err := strconv.Atoi(stopTimes.get(row, "stop_sequence"))
+		if err != nil {
+			return nil, 0, fmt.Errorf("stop_times.txt line %d: invalid stop_sequence", i+2)
+		}
+
+		st := importedStopTime{
+			stopId:   stopTimes.get(row, "stop_id"),
+			sequence: sequence,
+			pickup:   stopTimes.get(row, "pickup_type") != "1",
+			dropOff:  stopTimes.get(row, "drop_off_type") != "1",
+		}
+		if raw := cmp.Or(stopTimes.get(row, "departure_time"), stopTimes.get(row, "arrival_time")); raw != "" {
+			st.time, err = parseTime(raw)
+			if err != nil {
+				return nil, 0, fmt.Errorf("stop_times.txt line %d: %w", i+2, err)
+			}
+			st.hasTime = true
+		}
+
+		tripId := stopTimes.get(row, "trip_id")
+		tripStops[tripId] = append(tripStops[tripId], st)
+	}
+	for _, sts := range tripStops {
+		slices.SortFunc(sts, func(a, b importedStopTime) int { return a.sequence - b.sequence })
+	}
+
+	type trip struct {
+		id        string
+		serviceId string
+	}
+	trips := tables["trips.txt"]
+	routeTrips := make(map[string][]trip)
+	for _, row := range trips.rows {
+		routeId := trips.get(row, "route_id")
+		routeTrips[routeId] = append(routeTrips[routeId], trip{
+			id:        trips.get(row, "trip_id"),
+			serviceId: trips.get(row, "service_id"),
+		})
+	}
+
+	tt = &timetable.Timetable{Version: timetable.SchemaVersion}
+	services := make(map[string]bool)
+
+	routes := tables["routes.txt"]
+	for _, row := range routes.rows {
+		routeId := routes.get(row, "route_id")
+
+		// The most common stop pattern among the trips of the route
+		patterns := make(map[string]int)
+		for _, t := range routeTrips[routeId] {
+			if sts := tripStops[t.id]; len(sts) > 1 && sts[0].hasTime && sts[len(sts)-1].hasTime {
+				patterns[stopPattern(sts)]++
+			}
+		}
+		var pattern string
+		for _, t := range routeTrips[routeId] {
+			key := stopPattern(tripStops[t.id])
+			if patterns[key] > patterns[pattern] {
+				pattern = key
+			}
+		}
+		if pattern == "" {
+			skipped += len(routeTrips[routeId])
+			continue
+		}
+
+		r := timetable.Route{
+			ID:   routeId,
+			Name: cmp.Or(routes.get(row, "route_long_name"), routes.get(row, "route_short_name"), routeId),
+		}
+
+		schedules := make(map[string][]string)
+		for _, t := range routeTrips[routeId] {
+			sts := tripStops[t.id]
+			if stopPattern(sts) != pattern || !sts[0].hasTime {
+				skipped++
+				continue
+			}
+
+			if r.Stops == nil {
+				first, last := sts[0], sts[len(sts)-1]
+				r.TripLength = (last.time - first.time).Minutes()
+				r.Departure = locations[first.stopId].name
+				r.Arrival = locations[last.stopId].name
+				for _, st := range sts {
+					l := locations[st.stopId]
+					r.Stops = append(r.Stops, timetable.Stop{
+						Name:      l.name,
+						Departure: st.pickup,
+						Arrival:   st.dropOff,
+						Latitude:  l.latitude,
+						Longitude: l.longitude,
+					})
+				}
+			}
+
+			// Trips past midnight belong to the previous service day, the
+			// route tables only know the time of day they leave at
+			minutes := int(sts[0].time/time.Minute) % (24 * 60)
+			departure, err := timeofday.New(minutes/60, minutes%60)
+			if err != nil {
+				return nil, 0, fmt.Errorf("trip %s: %w", t.id, err)
+			}
+			schedules[t.serviceId] = append(schedules[t.serviceId], departure.String())
+			services[t.serviceId] = true
+		}
+
+		for _, serviceId := range slices.Sorted(maps.Keys(schedules)) {
+			r.Schedules = append(r.Schedules, timetable.Schedule{
+				Calendar:   serviceId,
+				Departures: schedules[serviceId],
+			})
+		}
+		tt.Routes = append(tt.Routes, r)
+	}
+
+	calendars := make(map[string]timetable.Calendar)
+	if t, ok := tables["calendar.txt"]; ok {
+		for i, row := range t.rows {
+			c, err := newTimetableCalendar(t, row)
+			if err != nil {
+				return nil, 0, fmt.Errorf("calendar.txt line %d: %w", i+2, err)
+			}
+			calendars[c.ID] = c
+		}
+	}
+	for _, serviceId := range slices.Sorted(maps.Keys(services)) {
+		c, ok := calendars[serviceId]
+		if !ok {
+			// Services only defined by calendar_dates.txt never run on a weekday basis
+			c = timetable.Calendar{ID: serviceId, Name: serviceId}
+		}
+		tt.Calendars = append(tt.Calendars, c)
+	}
+
+	err = tt.Validate()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tt, skipped, nil
+}
+
+func newTimetableCalendar(t *table, row []string) (timetable.Calendar, error) {
+	c := timetable.Calendar{
+		ID:   t.get(row, "service_id"),
+		Name: t.get(row, "service_id"),
+	}
+
+	for _, day := range []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"} {
+		if t.get(row, day) == "1" {
+			c.Days = append(c.Days, day)
+		}
+	}
+
+	for _, d := range []struct {
+		column string
+		dst    *string
+	}{
+		{"start_date", &c.StartDate},
+		{"end_date", &c.EndDate},
+	} {
+		value := t.get(row, d.column)
+		if value == "" {
+			continue
+		}
+
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return c, fmt.Errorf("invalid %s %q", d.column, value)
+		}
+		*d.dst = date.Format(calendar.DateLayout)
+	}
+
+	return c, nil
+}
+
+// stopPattern identifies the stops a trip serves, in order
+func stopPattern(sts []importedStopTime) string {
+	ids := make([]string, len(sts))
+	for i, st := range sts {
+		ids[i] = st.stopId
+	}
+	return strings.Join(ids, "\x00")
+}
+
+func parseCoordinate(s string) *float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
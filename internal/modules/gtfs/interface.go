@@ -0,0 +1,16 @@
+package gtfs
+
+import (
+	"context"
+
+	gtfsrt "github.com/MobilityData/gtfs-realtime-bindings/golang/gtfs"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/timetable"
+)
+
+type Service interface {
+	GetStaticFeed(ctx context.Context) (*Archive, error)
+	ImportStaticFeed(ctx context.Context, archive []byte, opts timetable.Options) (*ImportResult, error)
+	GetAlertsFeed(ctx context.Context) (*gtfsrt.FeedMessage, error)
+	GetVehiclePositionsFeed(ctx context.Context) (*gtfsrt.FeedMessage, error)
+	GetTripUpdatesFeed(ctx context.Context) (*gtfsrt.FeedMessage, error)
+}
@@ -0,0 +1,201 @@
+package gtfs
+
+import (
+	"time"
+
+	gtfsrt "github.com/MobilityData/gtfs-realtime-bindings/golang/gtfs"
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/alert"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// realtimeVersion is the GTFS-Realtime specification version the feeds follow
+	realtimeVersion = "2.0"
+
+	// RealtimeContentType is the media type of the protobuf encoded feeds
+	RealtimeContentType = "application/x-protobuf"
+)
+
+// newFeedMessage wraps the entities in a full dataset feed generated at now
+func newFeedMessage(now time.Time, entities []*gtfsrt.FeedEntity) *gtfsrt.FeedMessage {
+	return &gtfsrt.FeedMessage{
+		Header: &gtfsrt.FeedHeader{
+			GtfsRealtimeVersion: proto.String(realtimeVersion),
+			Incrementality:      gtfsrt.FeedHeader_FULL_DATASET.Enum(),
+			Timestamp:           proto.Uint64(uint64(now.Unix())),
+		},
+		Entity: entities,
+	}
+}
+
+// newAlertsFeed converts the service alerts to GTFS-Realtime alerts. The route
+// and stop IDs are the ones of the static feed, so consumers can join both.
+func newAlertsFeed(alerts []dto.AlertResponse, now time.Time) *gtfsrt.FeedMessage {
+	entities := make([]*gtfsrt.FeedEntity, len(alerts))
+	for i, a := range alerts {
+		entities[i] = &gtfsrt.FeedEntity{
+			Id:    proto.String(a.ID),
+			Alert: newRealtimeAlert(a),
+		}
+	}
+
+	return newFeedMessage(now, entities)
+}
+
+func newRealtimeAlert(a dto.AlertResponse) *gtfsrt.Alert {
+	period := &gtfsrt.TimeRange{Start: proto.Uint64(uint64(a.StartsAt.Unix()))}
+	if a.EndsAt != nil {
+		period.End = proto.Uint64(uint64(a.EndsAt.Unix()))
+	}
+
+	informed := make([]*gtfsrt.EntitySelector, 0, len(a.RouteIDs)+len(a.StopIDs))
+	for _, id := range a.RouteIDs {
+		informed = append(informed, &gtfsrt.EntitySelector{RouteId: proto.String(id)})
+	}
+	for _, id := range a.StopIDs {
+		informed = append(informed, &gtfsrt.EntitySelector{StopId: proto.String(id)})
+	}
+	// Network-wide alerts affect the whole agency
+	if len(informed) == 0 {
+		informed = append(informed, &gtfsrt.EntitySelector{AgencyId: proto.String(AgencyID)})
+	}
+
+	res := &gtfsrt.Alert{
+		ActivePeriod:   []*gtfsrt.TimeRange{period},
+		InformedEntity: informed,
+		Cause:          gtfsrt.Alert_UNKNOWN_CAUSE.Enum(),
+		Effect:         realtimeEffect(a.Kind).Enum(),
+		SeverityLevel:  realtimeSeverity(a.Severity).Enum(),
+		HeaderText:     translatedString(a.Title),
+	}
+	if a.Description != "" {
+		res.DescriptionText = translatedString(a.Description)
+	}
+
+	return res
+}
+
+func realtimeEffect(kind string) gtfsrt.Alert_Effect {
+	switch kind {
+	case alert.KindSuspension, alert.KindStopClosed:
+		return gtfsrt.Alert_NO_SERVICE
+	case alert.KindDetour:
+		return gtfsrt.Alert_DETOUR
+	case alert.KindDelay:
+		return gtfsrt.Alert_SIGNIFICANT_DELAYS
+	default:
+		return gtfsrt.Alert_OTHER_EFFECT
+	}
+}
+
+func realtimeSeverity(severity string) gtfsrt.Alert_SeverityLevel {
+	switch severity {
+	case alert.SeverityInfo:
+		return gtfsrt.Alert_INFO
+	case alert.SeverityWarning:
+		return gtfsrt.Alert_WARNING
+	case alert.SeveritySevere:
+		return gtfsrt.Alert_SEVERE
+	default:
+		return gtfsrt.Alert_UNKNOWN_SEVERITY
+	}
+}
+
+// translatedString holds the text in the language of the feed, the only one alerts are written in
+func translatedString(text string) *gtfsrt.TranslatedString {
+	return &gtfsrt.TranslatedString{
+		Translation: []*gtfsrt.TranslatedString_Translation{{
+			Text:     proto.String(text),
+			Language: proto.String(agencyLang),
+		}},
+	}
+}
+
+// newVehiclePositionsFeed converts the live vehicles to GTFS-Realtime vehicle
+// positions. Vehicles matched to a departure reference it as their trip, the
+// others only their route.
+func newVehiclePositionsFeed(vehicles []dto.VehiclePositionResponse, departures map[string]string, serviceDate string, now time.Time) *gtfsrt.FeedMessage {
+	entities := make([]*gtfsrt.FeedEntity, len(vehicles))
+	for i, v := range vehicles {
+		position := &gtfsrt.VehiclePosition{
+			Trip:    newTripDescriptor(v.RouteID, departures[v.VehicleID], serviceDate),
+			Vehicle: &gtfsrt.VehicleDescriptor{Id: proto.String(v.VehicleID)},
+			Position: &gtfsrt.Position{
+				Latitude:  proto.Float32(float32(v.Latitude)),
+				Longitude: proto.Float32(float32(v.Longitude)),
+			},
+			Timestamp: proto.Uint64(uint64(v.RecordedAt.Unix())),
+		}
+		if v.Heading != nil {
+			position.Position.Bearing = proto.Float32(float32(*v.Heading))
+		}
+		// The vehicle heads to the stop ending the segment it is on
+		if v.Snap != nil {
+			position.CurrentStopSequence = proto.Uint32(uint32(v.Snap.ToStopOrder))
+			position.StopId = proto.String(v.Snap.ToStopID)
+			position.CurrentStatus = gtfsrt.VehiclePosition_IN_TRANSIT_TO.Enum()
+		}
+
+		entities[i] = &gtfsrt.FeedEntity{
+			Id:      proto.String(v.VehicleID),
+			Vehicle: position,
+		}
+	}
+
+	return newFeedMessage(now, entities)
+}
+
+// newTripUpdatesFeed converts the predictions of the live trips to GTFS-Realtime
+// trip updates. Only trips matched to a departure are included, as consumers
+// need the trip of the static feed to apply the predictions to.
+func newTripUpdatesFeed(routes []dto.RouteETAResponse, recordedAt map[string]time.Time, serviceDate string, now time.Time) *gtfsrt.FeedMessage {
+	entities := make([]*gtfsrt.FeedEntity, 0, len(routes))
+	for _, r := range routes {
+		for _, trip := range r.Trips {
+			if trip.DepartureID == nil || trip.VehicleID == nil {
+				continue
+			}
+
+			updates := make([]*gtfsrt.TripUpdate_StopTimeUpdate, len(trip.Stops))
+			for i, st := range trip.Stops {
+				updates[i] = &gtfsrt.TripUpdate_StopTimeUpdate{
+					StopSequence: proto.Uint32(uint32(st.StopOrder)),
+					StopId:       proto.String(st.LocationID),
+					Arrival:      &gtfsrt.TripUpdate_StopTimeEvent{Time: proto.Int64(st.ArrivesAt.Unix())},
+				}
+			}
+
+			update := &gtfsrt.TripUpdate{
+				Trip:           newTripDescriptor(r.RouteID, *trip.DepartureID, serviceDate),
+				Vehicle:        &gtfsrt.VehicleDescriptor{Id: proto.String(*trip.VehicleID)},
+				StopTimeUpdate: updates,
+			}
+			if at, ok := recordedAt[*trip.VehicleID]; ok {
+				update.Timestamp = proto.Uint64(uint64(at.Unix()))
+			}
+
+			entities = append(entities, &gtfsrt.FeedEntity{
+				Id:         proto.String(*trip.DepartureID),
+				TripUpdate: update,
+			})
+		}
+	}
+
+	return newFeedMessage(now, entities)
+}
+
+// newTripDescriptor references the departure, which is the trip_id of the static
+// feed, run on the service date. Without a departure only the route is known.
+func newTripDescriptor(routeId, departureId, serviceDate string) *gtfsrt.TripDescriptor {
+	if departureId == "" {
+		return &gtfsrt.TripDescriptor{RouteId: proto.String(routeId)}
+	}
+
+	return &gtfsrt.TripDescriptor{
+		TripId:               proto.String(departureId),
+		RouteId:              proto.String(routeId),
+		StartDate:            proto.String(serviceDate),
+		ScheduleRelationship: gtfsrt.TripDescriptor_SCHEDULED.Enum(),
+	}
+}
@@ -0,0 +1,378 @@
+package gtfs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	gtfsrt "github.com/MobilityData/gtfs-realtime-bindings/golang/gtfs"
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/alert"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/eta"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/timetable"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/tracking"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/cache"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	gtfsServiceJourney = "gtfs service"
+	// feedWindowDays is how many days of service, starting today, the static feed covers
+	feedWindowDays = 180
+	// staticFeedCacheKey holds the last generated static feed
+	staticFeedCacheKey = "gtfs:static"
+	// staticFeedTTL bounds how long timetable changes take to reach the feed
+	staticFeedTTL = time.Minute * 15
+	// maxReportedErrors caps how many validation errors are sent back on a rejected import
+	maxReportedErrors = 5
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// ImportResult is the outcome of a GTFS import
+type ImportResult struct {
+	*timetable.Result
+	// SkippedTrips counts the trips that do not fit the route tables, see ToTimetable
+	SkippedTrips int      `json:"skipped_trips"`
+	Warnings     []string `json:"warnings"`
+}
+
+type ServiceConfig struct {
+	RouteService    route.Service
+	CalendarService calendar.Service
+	AlertService    alert.Service
+	TrackingService tracking.Service
+	EtaService      eta.Service
+	Importer        *timetable.Importer
+	Cache           *cache.Cache
+}
+
+type service struct {
+	routeService    route.Service
+	calendarService calendar.Service
+	alertService    alert.Service
+	trackingService tracking.Service
+	etaService      eta.Service
+	importer        *timetable.Importer
+	cache           *cache.Cache
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		routeService:    c.RouteService,
+		calendarService: c.CalendarService,
+		alertService:    c.AlertService,
+		trackingService: c.TrackingService,
+		etaService:      c.EtaService,
+		importer:        c.Importer,
+		cache:           c.Cache,
+	}
+}
+
+func (s service) GetStaticFeed(ctx context.Context) (*Archive, error) {
+	cached, err := s.cache.GetString(ctx, staticFeedCacheKey)
+	if err == nil {
+		return newArchive([]byte(cached)), nil
+	} else if fault.GetTag(err) != fault.CACHE_MISS {
+		// The feed can still be generated, so a cache failure is not fatal
+		logging.Error("failed to retrieve static feed from cache", err,
+			zap.String("journey", gtfsServiceJourney))
+	}
+
+	now := route.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, route.Timezone())
+
+	feed, err := s.buildFeed(ctx, from)
+	if err != nil {
+		return nil, err // The error is already being handled in buildFeed
+	}
+
+	data, err := feed.WriteZip(from)
+	if err != nil {
+		logging.Error("failed to encode static feed", err,
+			zap.String("journey", gtfsServiceJourney))
+		return nil, fault.NewInternalServerError("failed to generate gtfs feed")
+	}
+
+	report, err := Validate(data)
+	if err != nil {
+		logging.Error("failed to validate static feed", err,
+			zap.String("journey", gtfsServiceJourney))
+		return nil, fault.NewInternalServerError("failed to generate gtfs feed")
+	} else if !report.Valid() {
+		logging.Error("generated static feed is invalid", nil,
+			zap.String("journey", gtfsServiceJourney),
+			zap.Strings("errors", report.Errors))
+		return nil, fault.NewInternalServerError("failed to generate gtfs feed")
+	}
+	if len(report.Warnings) > 0 {
+		logging.Info("generated static feed has warnings",
+			zap.String("journey", gtfsServiceJourney),
+			zap.Strings("warnings", report.Warnings))
+	}
+
+	err = s.cache.SetString(ctx, staticFeedCacheKey, string(data), staticFeedTTL)
+	if err != nil {
+		logging.Error("failed to cache static feed", err,
+			zap.String("journey", gtfsServiceJourney))
+	}
+
+	return newArchive(data), nil
+}
+
+func (s service) ImportStaticFeed(ctx context.Context, archive []byte, opts timetable.Options) (*ImportResult, error) {
+	report, err := Validate(archive)
+	if err != nil {
+		logging.Error("failed to read gtfs archive", err,
+			zap.String("journey", gtfsServiceJourney))
+		return nil, fault.NewBadRequest("failed to read gtfs archive")
+	} else if !report.Valid() {
+		logging.Info("invalid gtfs feed",
+			zap.String("journey", gtfsServiceJourney),
+			zap.Strings("errors", report.Errors))
+		shown := report.Errors[:min(len(report.Errors), maxReportedErrors)]
+		return nil, fault.NewUnprocessableEntity(fmt.Sprintf("invalid gtfs feed: %s", strings.Join(shown, "; ")))
+	}
+
+	tt, skipped, err := ToTimetable(archive)
+	if err != nil {
+		logging.Error("failed to map gtfs feed to timetable", err,
+			zap.String("journey", gtfsServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to map gtfs feed to timetable")
+	}
+
+	res, err := s.importer.Import(ctx, tt, opts)
+	if err != nil {
+		return nil, err // The error is already being handled in the importer
+	}
+
+	if !opts.DryRun {
+		// Imported stops may carry new coordinates
+		s.routeService.InvalidateStopIndex()
+
+		err = s.cache.Delete(ctx, staticFeedCacheKey)
+		if err != nil {
+			logging.Error("failed to invalidate static feed cache", err,
+				zap.String("journey", gtfsServiceJourney))
+		}
+	}
+
+	return &ImportResult{
+		Result:       res,
+		SkippedTrips: skipped,
+		Warnings:     report.Warnings,
+	}, nil
+}
+
+func (s service) GetAlertsFeed(ctx context.Context) (*gtfsrt.FeedMessage, error) {
+	// Upcoming alerts are included too, consumers show them from their active period
+	alerts, err := s.alertService.GetCurrentAlerts(ctx)
+	if err != nil {
+		return nil, err // The error is already being handled in the alert service
+	}
+
+	return newAlertsFeed(alerts, time.Now()), nil
+}
+
+func (s service) GetVehiclePositionsFeed(ctx context.Context) (*gtfsrt.FeedMessage, error) {
+	vehicles, err := s.trackingService.GetVehiclePositions(ctx, "")
+	if err != nil {
+		return nil, err // The error is already being handled in the tracking service
+	}
+
+	routes, err := s.etaService.GetLiveTrips(ctx)
+	if err != nil {
+		return nil, err // The error is already being handled in the eta service
+	}
+
+	departures := make(map[string]string)
+	for _, r := range routes {
+		for _, trip := range r.Trips {
+			if trip.VehicleID != nil && trip.DepartureID != nil {
+				departures[*trip.VehicleID] = *trip.DepartureID
+			}
+		}
+	}
+
+	return newVehiclePositionsFeed(vehicles, departures, route.Now().Format(dateLayout), time.Now()), nil
+}
+
+func (s service) GetTripUpdatesFeed(ctx context.Context) (*gtfsrt.FeedMessage, error) {
+	vehicles, err := s.trackingService.GetVehiclePositions(ctx, "")
+	if err != nil {
+		return nil, err // The error is already being handled in the tracking service
+	}
+
+	routes, err := s.etaService.GetLiveTrips(ctx)
+	if err != nil {
+		return nil, err // The error is already being handled in the eta service
+	}
+
+	recordedAt := make(map[string]time.Time, len(vehicles))
+	for _, v := range vehicles {
+		recordedAt[v.VehicleID] = v.RecordedAt
+	}
+
+	return newTripUpdatesFeed(routes, recordedAt, route.Now().Format(dateLayout), time.Now()), nil
+}
+
+// buildFeed assembles the feed of the network running in the window starting at from
+func (s service) buildFeed(ctx context.Context, from time.Time) (*Feed, error) {
+	routes, err := s.routeService.GetAllRoutes(ctx)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	calendars, err := s.calendarService.GetCalendars(ctx)
+	if err != nil {
+		return nil, err // The error is already being handled in the calendar service
+	}
+
+	days, err := s.calendarService.GetServiceDays(ctx, from, feedWindowDays)
+	if err != nil {
+		return nil, err // The error is already being handled in the calendar service
+	}
+
+	feed := &Feed{
+		Agencies: []Agency{{
+			ID:       AgencyID,
+			Name:     agencyName,
+			URL:      agencyURL,
+			Timezone: route.Timezone().String(),
+			Lang:     agencyLang,
+		}},
+	}
+
+	for _, c := range calendars {
+		calendar, dates := newCalendar(c, days, from)
+		feed.Calendars = append(feed.Calendars, calendar)
+		feed.CalendarDates = append(feed.CalendarDates, dates...)
+	}
+
+	seenStops := make(map[string]bool)
+	for _, r := range routes {
+		if len(r.Stops) < 2 {
+			logging.Info("route left out of the static feed for having less than two stops",
+				zap.String("journey", gtfsServiceJourney),
+				zap.String("routeID", r.ID))
+			continue
+		}
+		// GTFS requires the coordinates of every stop, a route reaching one
+		// without them would make the whole feed invalid
+		unlocated := slices.ContainsFunc(r.Stops, func(st dto.RouteStopResponse) bool {
+			return st.Location.Latitude == nil || st.Location.Longitude == nil
+		})
+		if unlocated {
+			logging.Info("route left out of the static feed for having stops without coordinates",
+				zap.String("journey", gtfsServiceJourney),
+				zap.String("routeID", r.ID))
+			continue
+		}
+
+		feed.Routes = append(feed.Routes, Route{
+			ID:        r.ID,
+			AgencyID:  AgencyID,
+			ShortName: r.ID,
+			LongName:  r.Name,
+			Type:      routeTypeBus,
+		})
+
+		for _, st := range r.Stops {
+			if seenStops[st.Location.ID] {
+				continue
+			}
+			seenStops[st.Location.ID] = true
+			feed.Stops = append(feed.Stops, Stop{
+				ID:        st.Location.ID,
+				Name:      st.Location.Name,
+				Latitude:  st.Location.Latitude,
+				Longitude: st.Location.Longitude,
+			})
+		}
+
+		headsign := ""
+		if r.ArrivalLocation != nil {
+			headsign = r.ArrivalLocation.Name
+		}
+
+		firstOrder, lastOrder := r.Stops[0].StopOrder, r.Stops[len(r.Stops)-1].StopOrder
+		for _, d := range r.Departures {
+			feed.Trips = append(feed.Trips, Trip{
+				ID:        d.ID,
+				RouteID:   r.ID,
+				ServiceID: d.CalendarID,
+				Headsign:  headsign,
+			})
+
+			departsAt := time.Duration(d.Time.Minutes()) * time.Minute
+			for _, st := range r.Stops {
+				feed.StopTimes = append(feed.StopTimes, StopTime{
+					TripID:       d.ID,
+					Time:         departsAt + route.StopOffset(r.TripLength, st.StopOrder, lastOrder),
+					StopID:       st.Location.ID,
+					Sequence:     st.StopOrder,
+					Approximated: st.StopOrder != firstOrder,
+				})
+			}
+		}
+	}
+
+	return feed, nil
+}
+
+// newCalendar converts a service calendar to its weekly pattern and lists, as
+// calendar dates, every day of the window where the resolved service days
+// (exceptions, holidays and academic terms) disagree with the pattern
+func newCalendar(c dto.CalendarResponse, days calendar.ServiceDays, from time.Time) (Calendar, []CalendarDate) {
+	res := Calendar{
+		ServiceID: c.ID,
+		StartDate: from,
+		EndDate:   from.AddDate(0, 0, feedWindowDays-1),
+	}
+	for _, day := range c.Days {
+		res.Weekdays[weekdays[day]] = true
+	}
+
+	if c.StartDate != nil {
+		start, err := time.ParseInLocation(calendar.DateLayout, *c.StartDate, from.Location())
+		if err == nil && start.After(res.StartDate) {
+			res.StartDate = start
+		}
+	}
+	if c.EndDate != nil {
+		end, err := time.ParseInLocation(calendar.DateLayout, *c.EndDate, from.Location())
+		if err == nil && end.Before(res.EndDate) {
+			res.EndDate = end
+		}
+	}
+	if res.EndDate.Before(res.StartDate) {
+		// The calendar does not run inside the window, only exceptions may add days
+		res = Calendar{ServiceID: c.ID, StartDate: from, EndDate: from}
+	}
+
+	dates := make([]CalendarDate, 0)
+	for i := range feedWindowDays {
+		day := from.AddDate(0, 0, i)
+		inPattern := !day.Before(res.StartDate) && !day.After(res.EndDate) && res.Weekdays[day.Weekday()]
+		runs := days.Runs(c.ID, day)
+		if runs != inPattern {
+			dates = append(dates, CalendarDate{ServiceID: c.ID, Date: day, Added: runs})
+		}
+	}
+
+	return res, dates
+}
@@ -0,0 +1,301 @@
+package gtfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requiredColumns lists the files every feed must have and the columns they require.
+// calendar.txt and calendar_dates.txt are checked apart since either may be missing.
+var requiredColumns = []struct {
+	file    string
+	columns []string
+}{
+	{"agency.txt", []string{"agency_name", "agency_url", "agency_timezone"}},
+	{"stops.txt", []string{"stop_id", "stop_name"}},
+	{"routes.txt", []string{"route_id", "route_type"}},
+	{"trips.txt", []string{"route_id", "service_id", "trip_id"}},
+	{"stop_times.txt", []string{"trip_id", "stop_id", "stop_sequence"}},
+}
+
+// Report is the outcome of a feed validation. Errors make the feed unusable,
+// warnings point to data consumers will handle poorly.
+type Report struct {
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// Valid reports whether the feed has no errors
+func (r *Report) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+func (r *Report) errorf(format string, args ...any) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+func (r *Report) warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// table is a parsed GTFS file
+type table struct {
+	columns map[string]int
+	rows    [][]string
+}
+
+// get returns the value of the column in the row, empty when the column is missing
+func (t *table) get(row []string, column string) string {
+	i, ok := t.columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func (t *table) has(column string) bool {
+	_, ok := t.columns[column]
+	return ok
+}
+
+// Validate checks a GTFS zip archive for missing files and columns, duplicated
+// identifiers and broken references between the files. An error is only
+// returned when the archive cannot be read at all.
+func Validate(archive []byte) (*Report, error) {
+	tables, err := readTables(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Errors: make([]string, 0), Warnings: make([]string, 0)}
+
+	for _, required := range requiredColumns {
+		t, ok := tables[required.file]
+		if !ok {
+			report.errorf("missing required file %s", required.file)
+			continue
+		}
+		for _, c := range required.columns {
+			if !t.has(c) {
+				report.errorf("%s: missing required column %s", required.file, c)
+			}
+		}
+	}
+	if !report.Valid() {
+		return report, nil
+	}
+
+	calendars, hasCalendars := tables["calendar.txt"]
+	calendarDates, hasCalendarDates := tables["calendar_dates.txt"]
+	if !hasCalendars && !hasCalendarDates {
+		report.errorf("missing required file calendar.txt or calendar_dates.txt")
+		return report, nil
+	}
+
+	agencies := tables["agency.txt"]
+	agencyIDs := uniqueIDs(report, "agency.txt", agencies, "agency_id")
+	if len(agencies.rows) == 0 {
+		report.errorf("agency.txt: no agency defined")
+	}
+
+	stops := tables["stops.txt"]
+	stopIDs := uniqueIDs(report, "stops.txt", stops, "stop_id")
+	for i, row := range stops.rows {
+		if stops.get(row, "stop_lat") == "" || stops.get(row, "stop_lon") == "" {
+			report.errorf("stops.txt line %d: stop %s has no coordinates", i+2, stops.get(row, "stop_id"))
+		}
+	}
+
+	routes := tables["routes.txt"]
+	routeIDs := uniqueIDs(report, "routes.txt", routes, "route_id")
+	for i, row := range routes.rows {
+		agencyId := routes.get(row, "agency_id")
+		switch {
+		case agencyId == "" && len(agencies.rows) > 1:
+			report.errorf("routes.txt line %d: agency_id is required when there are many agencies", i+2)
+		case agencyId != "" && !agencyIDs[agencyId]:
+			report.errorf("routes.txt line %d: unknown agency_id %s", i+2, agencyId)
+		}
+		if routes.get(row, "route_short_name") == "" && routes.get(row, "route_long_name") == "" {
+			report.errorf("routes.txt line %d: route %s has no name", i+2, routes.get(row, "route_id"))
+		}
+	}
+
+	serviceIDs := make(map[string]bool)
+	if hasCalendars {
+		for service := range uniqueIDs(report, "calendar.txt", calendars, "service_id") {
+			serviceIDs[service] = true
+		}
+		for i, row := range calendars.rows {
+			checkDate(report, "calendar.txt", i, calendars.get(row, "start_date"))
+			checkDate(report, "calendar.txt", i, calendars.get(row, "end_date"))
+		}
+	}
+	if hasCalendarDates {
+		for i, row := range calendarDates.rows {
+			serviceIDs[calendarDates.get(row, "service_id")] = true
+			checkDate(report, "calendar_dates.txt", i, calendarDates.get(row, "date"))
+		}
+	}
+
+	trips := tables["trips.txt"]
+	tripIDs := uniqueIDs(report, "trips.txt", trips, "trip_id")
+	routesWithTrips := make(map[string]bool)
+	for i, row := range trips.rows {
+		routeId := trips.get(row, "route_id")
+		if !routeIDs[routeId] {
+			report.errorf("trips.txt line %d: unknown route_id %s", i+2, routeId)
+		}
+		routesWithTrips[routeId] = true
+
+		serviceId := trips.get(row, "service_id")
+		if !serviceIDs[serviceId] {
+			report.errorf("trips.txt line %d: unknown service_id %s", i+2, serviceId)
+		}
+	}
+	for _, row := range routes.rows {
+		if routeId := routes.get(row, "route_id"); !routesWithTrips[routeId] {
+			report.warnf("routes.txt: route %s has no trips", routeId)
+		}
+	}
+
+	stopTimes := tables["stop_times.txt"]
+	lastSequence := make(map[string]int)
+	lastTime := make(map[string]time.Duration)
+	stopsPerTrip := make(map[string]int)
+	for i, row := range stopTimes.rows {
+		tripId := stopTimes.get(row, "trip_id")
+		if !tripIDs[tripId] {
+			report.errorf("stop_times.txt line %d: unknown trip_id %s", i+2, tripId)
+		}
+
+		stopId := stopTimes.get(row, "stop_id")
+		if !stopIDs[stopId] {
+			report.errorf("stop_times.txt line %d: unknown stop_id %s", i+2, stopId)
+		}
+
+		sequence, err := strconv.Atoi(stopTimes.get(row, "stop_sequence"))
+		if err != nil || sequence < 0 {
+			report.errorf("stop_times.txt line %d: invalid stop_sequence", i+2)
+		} else if last, ok := lastSequence[tripId]; ok && sequence <= last {
+			report.errorf("stop_times.txt line %d: stop_sequence of trip %s does not increase", i+2, tripId)
+		}
+		lastSequence[tripId] = sequence
+		stopsPerTrip[tripId]++
+
+		for _, column := range []string{"arrival_time", "departure_time"} {
+			value := stopTimes.get(row, column)
+			if value == "" {
+				continue
+			}
+
+			t, err := parseTime(value)
+			if err != nil {
+				report.errorf("stop_times.txt line %d: invalid %s %q", i+2, column, value)
+				continue
+			}
+			if t < lastTime[tripId] {
+				report.errorf("stop_times.txt line %d: %s of trip %s goes back in time", i+2, column, tripId)
+			}
+			lastTime[tripId] = t
+		}
+	}
+	for _, row := range trips.rows {
+		if tripId := trips.get(row, "trip_id"); stopsPerTrip[tripId] < 2 {
+			report.errorf("trips.txt: trip %s has less than two stop times", tripId)
+		}
+	}
+
+	return report, nil
+}
+
+// uniqueIDs collects the values of the column, reporting empty and duplicated ones
+func uniqueIDs(report *Report, file string, t *table, column string) map[string]bool {
+	ids := make(map[string]bool, len(t.rows))
+	for i, row := range t.rows {
+		id := t.get(row, column)
+		if id == "" {
+			if t.has(column) {
+				report.errorf("%s line %d: empty %s", file, i+2, column)
+			}
+			continue
+		}
+		if ids[id] {
+			report.errorf("%s line %d: duplicated %s %s", file, i+2, column, id)
+		}
+		ids[id] = true
+	}
+	return ids
+}
+
+func checkDate(report *Report, file string, i int, value string) {
+	_, err := time.Parse(dateLayout, value)
+	if err != nil {
+		report.errorf("%s line %d: invalid date %q", file, i+2, value)
+	}
+}
+
+// readTables parses every .txt file of a GTFS archive, keyed by file name
+func readTables(archive []byte) (map[string]*table, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gtfs archive: %w", err)
+	}
+
+	tables := make(map[string]*table)
+	for _, f := range zr.File {
+		name := path.Base(f.Name)
+		if f.FileInfo().IsDir() || path.Ext(name) != ".txt" {
+			continue
+		}
+
+		t, err := readTable(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		tables[name] = t
+	}
+
+	return tables, nil
+}
+
+func readTable(f *zip.File) (*table, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	// Feeds exported from spreadsheets often start with a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &table{columns: make(map[string]int)}
+	if len(records) == 0 {
+		return t, nil
+	}
+
+	for i, c := range records[0] {
+		t.columns[strings.TrimSpace(c)] = i
+	}
+	t.rows = records[1:]
+
+	return t, nil
+}
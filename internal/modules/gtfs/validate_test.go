@@ -0,0 +1,123 @@
+package gtfs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	lat, lon := -13.001, -38.508
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	return &Feed{
+		Agencies: []Agency{{ID: AgencyID, Name: agencyName, URL: agencyURL, Timezone: "America/Bahia", Lang: agencyLang}},
+		Stops: []Stop{
+			{ID: "pv", Name: "PAF V", Latitude: &lat, Longitude: &lon},
+			{ID: "ra", Name: "Reitoria", Latitude: &lat, Longitude: &lon},
+		},
+		Routes: []Route{{ID: "b1", AgencyID: AgencyID, ShortName: "B1", LongName: "PAF V - Reitoria", Type: routeTypeBus}},
+		Trips:  []Trip{{ID: "b1-1", RouteID: "b1", ServiceID: "weekdays", Headsign: "Reitoria"}},
+		StopTimes: []StopTime{
+			{TripID: "b1-1", Time: 7 * time.Hour, StopID: "pv", Sequence: 1},
+			{TripID: "b1-1", Time: 7*time.Hour + 15*time.Minute, StopID: "ra", Sequence: 2},
+		},
+		Calendars: []Calendar{{
+			ServiceID: "weekdays",
+			Weekdays:  [7]bool{false, true, true, true, true, true, false},
+			StartDate: start,
+			EndDate:   start.AddDate(1, 0, 0),
+		}},
+		CalendarDates: []CalendarDate{{ServiceID: "weekdays", Date: start, Added: false}},
+	}
+}
+
+func validateFeed(t *testing.T, f *Feed) *Report {
+	t.Helper()
+
+	archive, err := f.WriteZip(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("WriteZip: %v", err)
+	}
+
+	report, err := Validate(archive)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	return report
+}
+
+func TestValidateWrittenFeed(t *testing.T) {
+	report := validateFeed(t, testFeed())
+
+	if !report.Valid() {
+		t.Fatalf("expected a valid feed, got errors %v", report.Errors)
+	}
+	if len(report.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", report.Warnings)
+	}
+}
+
+func TestValidateBrokenReferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(f *Feed)
+		want   string
+	}{
+		{
+			name:   "stop time with unknown trip",
+			mutate: func(f *Feed) { f.StopTimes[1].TripID = "b2-1" },
+			want:   "stop_times.txt line 3: unknown trip_id b2-1",
+		},
+		{
+			name:   "stop time with unknown stop",
+			mutate: func(f *Feed) { f.StopTimes[1].StopID = "ondina" },
+			want:   "stop_times.txt line 3: unknown stop_id ondina",
+		},
+		{
+			name:   "trip with unknown service",
+			mutate: func(f *Feed) { f.Trips[0].ServiceID = "weekends" },
+			want:   "trips.txt line 2: unknown service_id weekends",
+		},
+		{
+			name:   "trip with unknown route",
+			mutate: func(f *Feed) { f.Trips[0].RouteID = "b2" },
+			want:   "trips.txt line 2: unknown route_id b2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testFeed()
+			tt.mutate(f)
+
+			report := validateFeed(t, f)
+			if report.Valid() {
+				t.Fatal("expected the feed to be invalid")
+			}
+			if !containsError(report, tt.want) {
+				t.Errorf("expected error %q, got %v", tt.want, report.Errors)
+			}
+		})
+	}
+}
+
+func containsError(report *Report, want string) bool {
+	for _, e := range report.Errors {
+		if strings.Contains(e, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateStopWithoutCoordinates(t *testing.T) {
+	f := testFeed()
+	f.Stops[1].Latitude, f.Stops[1].Longitude = nil, nil
+
+	report := validateFeed(t, f)
+	want := "stops.txt line 3: stop ra has no coordinates"
+	if !containsError(report, want) {
+		t.Errorf("expected error %q, got %v", want, report.Errors)
+	}
+}
@@ -0,0 +1,205 @@
+package lostfound
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/role"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/token"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	lostFoundHandlerJourney = "lost and found handler"
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	lostFoundService Service
+	secretKey        string
+	sessions         middleware.SessionValidator
+}
+
+func NewHandler(lostFoundService Service, secretKey string, sessions middleware.SessionValidator) *handler {
+	once.Do(func() {
+		instance = &handler{
+			lostFoundService: lostFoundService,
+			secretKey:        secretKey,
+			sessions:         sessions,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
+
+	r.Route("/api/v1/lost-found", func(r chi.Router) {
+		// Staff
+		r.With(m.WithAuth, m.WithRole(role.Admin, role.Driver)).Put("/{itemId}/hold", h.handleHoldItem)
+		r.With(m.WithAuth, m.WithRole(role.Admin, role.Driver)).Put("/{itemId}/claim", h.handleClaimItem)
+		// Private
+		r.With(m.WithAuth).Get("/", h.handleSearchItems)
+		r.With(m.WithAuth).Get("/{itemId}", h.handleGetItem)
+		r.With(m.WithAuth).Post("/", h.handleReportItem)
+		r.With(m.WithAuth).Delete("/{itemId}", h.handleDeleteItem)
+	})
+}
+
+// handleSearchItems lists the items matching route_id, kind, status and date (YYYY-MM-DD),
+// a page at a time with page and limit
+func (h handler) handleSearchItems(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qs := r.URL.Query()
+
+	query := dto.LostItemsQuery{
+		RouteID: qs.Get("route_id"),
+		Kind:    qs.Get("kind"),
+		Status:  qs.Get("status"),
+		Page:    httputil.ReadQueryInt(qs, "page", 1),
+		Limit:   httputil.ReadQueryInt(qs, "limit", DefaultPageSize),
+	}
+	if v := qs.Get("date"); v != "" {
+		date, err := calendar.ParseDate(v)
+		if err != nil {
+			fault.NewHTTPError(w, err)
+			return
+		}
+		query.Date = &date
+	}
+
+	res, err := h.lostFoundService.SearchItems(ctx, query)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	itemId := chi.URLParam(r, "itemId")
+
+	res, err := h.lostFoundService.GetItemByID(ctx, itemId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleReportItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	var body dto.CreateLostItem
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.lostFoundService.ReportItem(ctx, c.UserID, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, res)
+}
+
+func (h handler) handleHoldItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+	itemId := chi.URLParam(r, "itemId")
+
+	var body dto.HoldLostItem
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.lostFoundService.HoldItem(ctx, c.UserID, itemId, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleClaimItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+	itemId := chi.URLParam(r, "itemId")
+
+	res, err := h.lostFoundService.ClaimItem(ctx, c.UserID, itemId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleDeleteItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+	itemId := chi.URLParam(r, "itemId")
+
+	err := h.lostFoundService.DeleteItem(ctx, c.UserID, itemId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+// claimsFromContext reads the claims put by WithAuth, writing the error response when missing
+func claimsFromContext(w http.ResponseWriter, r *http.Request) (*token.Claims, bool) {
+	c, ok := r.Context().Value(middleware.AuthKey{}).(*token.Claims)
+	if !ok {
+		logging.Info("Unable to retrieve claims from token",
+			zap.String("journey", lostFoundHandlerJourney),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		fault.NewHTTPError(w, fault.NewUnauthorized("invalid access token"))
+	}
+	return c, ok
+}
+
+func logErrorInReadRequestBody(err error, r *http.Request) {
+	logging.Error("failed to read request body", err,
+		zap.String("journey", lostFoundHandlerJourney),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path))
+}
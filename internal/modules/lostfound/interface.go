@@ -0,0 +1,39 @@
+package lostfound
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+// SearchFilter narrows the items listed by Search, empty fields match every item
+type SearchFilter struct {
+	RouteID string
+	Kind    string
+	Status  string
+	// From and To bound occurred_at, To being exclusive
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
+
+type Repository interface {
+	Insert(ctx context.Context, item model.LostItem) error
+	Update(ctx context.Context, item model.LostItem) error
+	GetByID(ctx context.Context, itemId string) (*model.LostItem, error)
+	// Search returns a page of the matching items, most recent first, and how many match in total
+	Search(ctx context.Context, filter SearchFilter) ([]model.LostItem, int, error)
+	Delete(ctx context.Context, itemId string) error
+}
+
+type Service interface {
+	SearchItems(ctx context.Context, query dto.LostItemsQuery) (*dto.LostItemsPageResponse, error)
+	GetItemByID(ctx context.Context, itemId string) (*dto.LostItemResponse, error)
+	ReportItem(ctx context.Context, userId string, input dto.CreateLostItem) (*dto.LostItemResponse, error)
+	HoldItem(ctx context.Context, staffId, itemId string, input dto.HoldLostItem) (*dto.LostItemResponse, error)
+	ClaimItem(ctx context.Context, staffId, itemId string) (*dto.LostItemResponse, error)
+	DeleteItem(ctx context.Context, userId, itemId string) error
+}
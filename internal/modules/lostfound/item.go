@@ -0,0 +1,177 @@
+package lostfound
+
+import (
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+)
+
+const (
+	KindLost  = "lost"
+	KindFound = "found"
+
+	// StatusOpen is an item reported and not handed to staff yet
+	StatusOpen = "open"
+	// StatusHeld is an item kept by staff at a location, waiting for its owner
+	StatusHeld = "held"
+	// StatusClaimed is an item given back to its owner
+	StatusClaimed = "claimed"
+
+	maxDescriptionLength = 1000
+	// maxClockSkew tolerates reports slightly ahead of the server clock
+	maxClockSkew = 5 * time.Minute
+)
+
+var (
+	kinds    = []string{KindLost, KindFound}
+	statuses = []string{StatusOpen, StatusHeld, StatusClaimed}
+)
+
+type item struct {
+	id           string
+	userId       string
+	kind         string
+	description  string
+	routeId      *string
+	occurredAt   time.Time
+	photoUrl     *string
+	status       string
+	heldLocation *string
+	handledBy    *string
+	claimedAt    *time.Time
+	createdAt    time.Time
+	updatedAt    time.Time
+}
+
+func NewItem(userId, kind, description, routeId string, occurredAt time.Time, photoUrl *string) (*item, error) {
+	now := time.Now()
+	i := item{
+		id:          uid.New("item"),
+		userId:      userId,
+		kind:        kind,
+		description: description,
+		routeId:     &routeId,
+		occurredAt:  occurredAt,
+		photoUrl:    photoUrl,
+		status:      StatusOpen,
+		createdAt:   now,
+		updatedAt:   now,
+	}
+
+	if err := i.validate(); err != nil {
+		return nil, fault.New(
+			"failed to create item entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return &i, nil
+}
+
+func NewItemFromModel(m model.LostItem) *item {
+	return &item{
+		id:           m.ID,
+		userId:       m.UserID,
+		kind:         m.Kind,
+		description:  m.Description,
+		routeId:      m.RouteID,
+		occurredAt:   m.OccurredAt,
+		photoUrl:     m.PhotoURL,
+		status:       m.Status,
+		heldLocation: m.HeldLocation,
+		handledBy:    m.HandledBy,
+		claimedAt:    m.ClaimedAt,
+		createdAt:    m.CreatedAt,
+		updatedAt:    m.UpdatedAt,
+	}
+}
+
+// Hold records the item as kept at the location by a staff member.
+// Held items may be moved to another location until they are claimed.
+func (i *item) Hold(location, staffId string) error {
+	i.status = StatusHeld
+	i.heldLocation = &location
+	i.handledBy = &staffId
+	i.updatedAt = time.Now()
+
+	if err := i.validate(); err != nil {
+		return fault.New(
+			"failed to update item entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return nil
+}
+
+// Claim records the item as given back to its owner by a staff member
+func (i *item) Claim(staffId string) {
+	now := time.Now()
+	i.status = StatusClaimed
+	i.handledBy = &staffId
+	i.claimedAt = &now
+	i.updatedAt = now
+}
+
+func (i *item) Claimed() bool {
+	return i.status == StatusClaimed
+}
+
+func (i *item) validate() error {
+	if !slices.Contains(kinds, i.kind) {
+		return fault.New("kind must be either lost or found")
+	}
+	if i.description == "" {
+		return fault.New("description is required")
+	}
+	if len(i.description) > maxDescriptionLength {
+		return fault.New("description is too long")
+	}
+	if i.routeId == nil || *i.routeId == "" {
+		return fault.New("route is required")
+	}
+	if i.occurredAt.IsZero() {
+		return fault.New("occurrence time is required")
+	}
+	if i.occurredAt.After(time.Now().Add(maxClockSkew)) {
+		return fault.New("occurrence time must not be in the future")
+	}
+	if i.photoUrl != nil {
+		u, err := url.Parse(*i.photoUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fault.New("photo must be an http or https url")
+		}
+	}
+	if !slices.Contains(statuses, i.status) {
+		return fault.New("invalid status")
+	}
+	if i.status == StatusHeld && (i.heldLocation == nil || *i.heldLocation == "") {
+		return fault.New("location is required")
+	}
+
+	return nil
+}
+
+func (i *item) Model() model.LostItem {
+	return model.LostItem{
+		ID:           i.id,
+		UserID:       i.userId,
+		Kind:         i.kind,
+		Description:  i.description,
+		RouteID:      i.routeId,
+		OccurredAt:   i.occurredAt,
+		PhotoURL:     i.photoUrl,
+		Status:       i.status,
+		HeldLocation: i.heldLocation,
+		HandledBy:    i.handledBy,
+		ClaimedAt:    i.claimedAt,
+		CreatedAt:    i.createdAt,
+		UpdatedAt:    i.updatedAt,
+	}
+}
@@ -0,0 +1,150 @@
+package lostfound
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+)
+
+// searchConditions is shared by the page and count queries of Search
+const searchConditions = `
+	WHERE ($1 = '' OR route_id = $1)
+		AND ($2 = '' OR kind = $2)
+		AND ($3 = '' OR status = $3)
+		AND ($4::TIMESTAMPTZ IS NULL OR occurred_at >= $4)
+		AND ($5::TIMESTAMPTZ IS NULL OR occurred_at < $5)
+`
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) Insert(ctx context.Context, item model.LostItem) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO lost_items (
+			id,
+			user_id,
+			kind,
+			description,
+			route_id,
+			occurred_at,
+			photo_url,
+			status,
+			held_location,
+			handled_by,
+			claimed_at,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:user_id,
+			:kind,
+			:description,
+			:route_id,
+			:occurred_at,
+			:photo_url,
+			:status,
+			:held_location,
+			:handled_by,
+			:claimed_at,
+			:created_at,
+			:updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, item)
+	if err != nil {
+		return fault.New("failed to insert lost item", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) Update(ctx context.Context, item model.LostItem) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE lost_items
+		SET
+			status = :status,
+			held_location = :held_location,
+			handled_by = :handled_by,
+			claimed_at = :claimed_at,
+			updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, item)
+	if err != nil {
+		return fault.New("failed to update lost item", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) GetByID(ctx context.Context, itemId string) (*model.LostItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var item model.LostItem
+	err := r.db.GetContext(ctx, &item, "SELECT * FROM lost_items WHERE id = $1 LIMIT 1", itemId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve lost item", fault.WithError(err))
+	}
+
+	return &item, nil
+}
+
+func (r repo) Search(ctx context.Context, filter SearchFilter) ([]model.LostItem, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	args := []any{filter.RouteID, filter.Kind, filter.Status, filter.From, filter.To}
+
+	var total int
+	err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM lost_items"+searchConditions, args...)
+	if err != nil {
+		return nil, 0, fault.New("failed to count lost items", fault.WithError(err))
+	}
+
+	var items = make([]model.LostItem, 0)
+	err = r.db.SelectContext(
+		ctx,
+		&items,
+		"SELECT * FROM lost_items"+searchConditions+"ORDER BY occurred_at DESC, id LIMIT $6 OFFSET $7",
+		append(args, filter.Limit, filter.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, fault.New("failed to retrieve lost items", fault.WithError(err))
+	}
+
+	return items, total, nil
+}
+
+func (r repo) Delete(ctx context.Context, itemId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM lost_items WHERE id = $1", itemId)
+	if err != nil {
+		return fault.New("failed to delete lost item", fault.WithError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package lostfound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+const (
+	lostFoundServiceJourney = "lost and found service"
+	// DefaultPageSize is the number of items listed per page when not given
+	DefaultPageSize = 20
+	// maxPageSize caps the number of items listed per page
+	maxPageSize = 50
+)
+
+type ServiceConfig struct {
+	LostItemRepo Repository
+}
+
+type service struct {
+	lostItemRepo Repository
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		lostItemRepo: c.LostItemRepo,
+	}
+}
+
+func (s service) SearchItems(ctx context.Context, query dto.LostItemsQuery) (*dto.LostItemsPageResponse, error) {
+	if query.Kind != "" && !slices.Contains(kinds, query.Kind) {
+		return nil, fault.NewBadRequest("kind must be either lost or found")
+	}
+	if query.Status != "" && !slices.Contains(statuses, query.Status) {
+		return nil, fault.NewBadRequest("status must be one of open, held or claimed")
+	}
+	if query.Page < 1 {
+		return nil, fault.NewBadRequest("page must be a positive number")
+	}
+	if query.Limit < 1 || query.Limit > maxPageSize {
+		return nil, fault.NewBadRequest(fmt.Sprintf("limit must be between 1 and %d", maxPageSize))
+	}
+
+	filter := SearchFilter{
+		RouteID: query.RouteID,
+		Kind:    query.Kind,
+		Status:  query.Status,
+		Limit:   query.Limit,
+		Offset:  (query.Page - 1) * query.Limit,
+	}
+	if query.Date != nil {
+		// The day is the one riders see, in the timezone of the service
+		y, m, d := query.Date.Date()
+		from := time.Date(y, m, d, 0, 0, 0, 0, route.Timezone())
+		to := from.AddDate(0, 0, 1)
+		filter.From, filter.To = &from, &to
+	}
+
+	records, total, err := s.lostItemRepo.Search(ctx, filter)
+	if err != nil {
+		logging.Error("failed to search lost items", err,
+			zap.String("journey", lostFoundServiceJourney))
+		return nil, fault.NewBadRequest("failed to search items")
+	}
+
+	res := &dto.LostItemsPageResponse{
+		Items: make([]dto.LostItemResponse, len(records)),
+		Page:  query.Page,
+		Limit: query.Limit,
+		Total: total,
+	}
+	for i, r := range records {
+		res.Items[i] = newLostItemResponse(r)
+	}
+
+	return res, nil
+}
+
+func (s service) GetItemByID(ctx context.Context, itemId string) (*dto.LostItemResponse, error) {
+	record, err := s.getItem(ctx, itemId)
+	if err != nil {
+		return nil, err // The error is already being handled in getItem
+	}
+
+	res := newLostItemResponse(*record)
+	return &res, nil
+}
+
+func (s service) ReportItem(ctx context.Context, userId string, input dto.CreateLostItem) (*dto.LostItemResponse, error) {
+	if input.OccurredAt == nil {
+		return nil, fault.NewBadRequest("occurred_at is required")
+	}
+
+	item, err := NewItem(userId, input.Kind, input.Description, input.RouteID, *input.OccurredAt, input.PhotoURL)
+	if err != nil {
+		logging.Error("failed to create lost item", err,
+			zap.String("journey", lostFoundServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to create item entity")
+	}
+	model := item.Model()
+
+	err = s.lostItemRepo.Insert(ctx, model)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" { // 23503 is the code for foreign key violation
+			return nil, fault.NewUnprocessableEntity("unknown route")
+		}
+		logging.Error("failed to insert lost item", err,
+			zap.String("journey", lostFoundServiceJourney))
+		return nil, fault.NewBadRequest("failed to report item")
+	}
+
+	res := newLostItemResponse(model)
+	return &res, nil
+}
+
+func (s service) HoldItem(ctx context.Context, staffId, itemId string, input dto.HoldLostItem) (*dto.LostItemResponse, error) {
+	record, err := s.getItem(ctx, itemId)
+	if err != nil {
+		return nil, err // The error is already being handled in getItem
+	}
+
+	item := NewItemFromModel(*record)
+	if item.Claimed() {
+		return nil, fault.NewConflict("item already claimed")
+	}
+
+	err = item.Hold(input.Location, staffId)
+	if err != nil {
+		logging.Error("failed to hold lost item", err,
+			zap.String("journey", lostFoundServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to update item entity")
+	}
+
+	return s.update(ctx, item)
+}
+
+func (s service) ClaimItem(ctx context.Context, staffId, itemId string) (*dto.LostItemResponse, error) {
+	record, err := s.getItem(ctx, itemId)
+	if err != nil {
+		return nil, err // The error is already being handled in getItem
+	}
+
+	item := NewItemFromModel(*record)
+	if item.Claimed() {
+		return nil, fault.NewConflict("item already claimed")
+	}
+	item.Claim(staffId)
+
+	return s.update(ctx, item)
+}
+
+func (s service) DeleteItem(ctx context.Context, userId, itemId string) error {
+	record, err := s.getItem(ctx, itemId)
+	if err != nil {
+		return err // The error is already being handled in getItem
+	}
+	// Only the reporter may withdraw a report, staff close them by claiming
+	if record.UserID != userId {
+		return fault.NewNotFound("item not found")
+	}
+
+	err = s.lostItemRepo.Delete(ctx, itemId)
+	if err != nil {
+		logging.Error("failed to delete lost item", err,
+			zap.String("journey", lostFoundServiceJourney))
+		return fault.NewBadRequest("failed to delete item")
+	}
+
+	return nil
+}
+
+func (s service) getItem(ctx context.Context, itemId string) (*model.LostItem, error) {
+	record, err := s.lostItemRepo.GetByID(ctx, itemId)
+	if err != nil {
+		logging.Error("failed to retrieve lost item", err,
+			zap.String("journey", lostFoundServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve item")
+	} else if record == nil {
+		logging.Info("lost item not found",
+			zap.String("journey", lostFoundServiceJourney),
+			zap.String("itemID", itemId))
+		return nil, fault.NewNotFound("item not found")
+	}
+
+	return record, nil
+}
+
+func (s service) update(ctx context.Context, item *item) (*dto.LostItemResponse, error) {
+	model := item.Model()
+
+	err := s.lostItemRepo.Update(ctx, model)
+	if err != nil {
+		logging.Error("failed to update lost item", err,
+			zap.String("journey", lostFoundServiceJourney))
+		return nil, fault.NewBadRequest("failed to update item")
+	}
+
+	res := newLostItemResponse(model)
+	return &res, nil
+}
+
+func newLostItemResponse(i model.LostItem) dto.LostItemResponse {
+	return dto.LostItemResponse{
+		ID:           i.ID,
+		Kind:         i.Kind,
+		Description:  i.Description,
+		RouteID:      i.RouteID,
+		OccurredAt:   i.OccurredAt,
+		PhotoURL:     i.PhotoURL,
+		Status:       i.Status,
+		HeldLocation: i.HeldLocation,
+		ClaimedAt:    i.ClaimedAt,
+		CreatedAt:    i.CreatedAt,
+		UpdatedAt:    i.UpdatedAt,
+	}
+}
@@ -0,0 +1,109 @@
+package occupancy
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/token"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	occupancyHandlerJourney = "occupancy handler"
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	occupancyService Service
+	secretKey        string
+	sessions         middleware.SessionValidator
+}
+
+func NewHandler(occupancyService Service, secretKey string, sessions middleware.SessionValidator) *handler {
+	once.Do(func() {
+		instance = &handler{
+			occupancyService: occupancyService,
+			secretKey:        secretKey,
+			sessions:         sessions,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
+
+	// Private
+	r.With(m.WithAuth).Post("/api/v1/occupancy", h.handleReportOccupancy)
+	// Public
+	r.Get("/api/v1/routes/{routeId}/occupancy/heatmap", h.handleGetHeatmap)
+}
+
+func (h handler) handleReportOccupancy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	var body dto.ReportOccupancy
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.occupancyService.ReportOccupancy(ctx, c.UserID, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, res)
+}
+
+// handleGetHeatmap returns the occupancy of the route by weekday and hour over the last ?weeks=
+func (h handler) handleGetHeatmap(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routeId := chi.URLParam(r, "routeId")
+	weeks := httputil.ReadQueryInt(r.URL.Query(), "weeks", DefaultHeatmapWeeks)
+
+	res, err := h.occupancyService.GetHeatmap(ctx, routeId, weeks)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+// claimsFromContext reads the claims put by WithAuth, writing the error response when missing
+func claimsFromContext(w http.ResponseWriter, r *http.Request) (*token.Claims, bool) {
+	c, ok := r.Context().Value(middleware.AuthKey{}).(*token.Claims)
+	if !ok {
+		logging.Info("Unable to retrieve claims from token",
+			zap.String("journey", occupancyHandlerJourney),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		fault.NewHTTPError(w, fault.NewUnauthorized("invalid access token"))
+	}
+	return c, ok
+}
+
+func logErrorInReadRequestBody(err error, r *http.Request) {
+	logging.Error("failed to read request body", err,
+		zap.String("journey", occupancyHandlerJourney),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path))
+}
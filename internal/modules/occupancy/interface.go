@@ -0,0 +1,28 @@
+package occupancy
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+type Repository interface {
+	// Insert stores the report when its stop is served by its route, reporting whether it did
+	Insert(ctx context.Context, report model.OccupancyReport) (bool, error)
+	RouteExists(ctx context.Context, routeId string) (bool, error)
+	// GetRecentAverages averages the reports of each route made since the given time
+	GetRecentAverages(ctx context.Context, routeIds []string, since time.Time) ([]model.OccupancyAggregate, error)
+	// GetHourlyAverages averages the reports of each route since the given time by hour of the day in the timezone
+	GetHourlyAverages(ctx context.Context, routeIds []string, since time.Time, timezone string) ([]model.OccupancyAggregate, error)
+	// GetHeatmap averages the reports of the route between the given times by weekday and hour in the timezone
+	GetHeatmap(ctx context.Context, routeId string, from, to time.Time, timezone string) ([]model.OccupancyAggregate, error)
+}
+
+type Service interface {
+	ReportOccupancy(ctx context.Context, userId string, input dto.ReportOccupancy) (*dto.OccupancyReportResponse, error)
+	// GetOccupancyEstimates returns the estimates of the routes by route ID, see route.OccupancyProvider
+	GetOccupancyEstimates(ctx context.Context, routeIds []string) (map[string]dto.RouteOccupancyEstimate, error)
+	GetHeatmap(ctx context.Context, routeId string, weeks int) (*dto.OccupancyHeatmapResponse, error)
+}
@@ -0,0 +1,78 @@
+package occupancy
+
+import (
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+)
+
+const (
+	LevelEmpty    = "empty"
+	LevelSeated   = "seated"
+	LevelStanding = "standing"
+	LevelFull     = "full"
+)
+
+// levels are sorted by how full the bus is, a level's score being its index
+var levels = []string{LevelEmpty, LevelSeated, LevelStanding, LevelFull}
+
+type report struct {
+	id         string
+	userId     string
+	routeId    string
+	locationId string
+	level      string
+	reportedAt time.Time
+}
+
+func NewReport(userId, routeId, locationId, level string) (*report, error) {
+	r := report{
+		id:         uid.New("occupancy"),
+		userId:     userId,
+		routeId:    routeId,
+		locationId: locationId,
+		level:      level,
+		reportedAt: time.Now(),
+	}
+
+	if err := r.validate(); err != nil {
+		return nil, fault.New(
+			"failed to create occupancy report entity",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return &r, nil
+}
+
+func (r *report) validate() error {
+	if r.routeId == "" || r.locationId == "" {
+		return fault.New("route and stop are required")
+	}
+	if !slices.Contains(levels, r.level) {
+		return fault.New("level must be one of empty, seated, standing or full")
+	}
+
+	return nil
+}
+
+func (r *report) Model() model.OccupancyReport {
+	return model.OccupancyReport{
+		ID:         r.id,
+		UserID:     r.userId,
+		RouteID:    r.routeId,
+		LocationID: r.locationId,
+		Level:      r.level,
+		ReportedAt: r.reportedAt,
+	}
+}
+
+// levelOf turns an average score back into the closest level
+func levelOf(score float64) string {
+	i := int(score + 0.5)
+	return levels[max(0, min(i, len(levels)-1))]
+}
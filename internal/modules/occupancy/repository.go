@@ -0,0 +1,154 @@
+package occupancy
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// scoreExpr turns a report's level into its score, matching the order of levels
+const scoreExpr = `
+	CASE level
+		WHEN 'empty' THEN 0
+		WHEN 'seated' THEN 1
+		WHEN 'standing' THEN 2
+		ELSE 3
+	END
+`
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) Insert(ctx context.Context, report model.OccupancyReport) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO occupancy_reports (
+			id,
+			user_id,
+			route_id,
+			location_id,
+			level,
+			reported_at
+		)
+		SELECT $1, $2, $3, $4, $5, $6::TIMESTAMPTZ
+		WHERE EXISTS (
+			SELECT 1 FROM route_stops WHERE route_id = $3 AND location_id = $4
+		)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		report.ID, report.UserID, report.RouteID, report.LocationID, report.Level, report.ReportedAt,
+	)
+	if err != nil {
+		return false, fault.New("failed to insert occupancy report", fault.WithError(err))
+	}
+
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return false, fault.New("failed to insert occupancy report", fault.WithError(err))
+	}
+
+	return inserted > 0, nil
+}
+
+func (r repo) RouteExists(ctx context.Context, routeId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, "SELECT EXISTS (SELECT 1 FROM routes WHERE id = $1)", routeId)
+	if err != nil {
+		return false, fault.New("failed to check route", fault.WithError(err))
+	}
+
+	return exists, nil
+}
+
+func (r repo) GetRecentAverages(ctx context.Context, routeIds []string, since time.Time) ([]model.OccupancyAggregate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		SELECT
+			route_id,
+			0 AS weekday,
+			0 AS hour,
+			AVG(` + scoreExpr + `) AS score,
+			COUNT(*) AS reports
+		FROM occupancy_reports
+		WHERE route_id = ANY($1) AND reported_at >= $2
+		GROUP BY route_id
+	`
+
+	var aggregates = make([]model.OccupancyAggregate, 0)
+	err := r.db.SelectContext(ctx, &aggregates, query, pq.Array(routeIds), since)
+	if err != nil {
+		return nil, fault.New("failed to retrieve recent occupancy", fault.WithError(err))
+	}
+
+	return aggregates, nil
+}
+
+func (r repo) GetHourlyAverages(ctx context.Context, routeIds []string, since time.Time, timezone string) ([]model.OccupancyAggregate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var query = `
+		SELECT
+			route_id,
+			0 AS weekday,
+			EXTRACT(HOUR FROM reported_at AT TIME ZONE $3)::int AS hour,
+			AVG(` + scoreExpr + `) AS score,
+			COUNT(*) AS reports
+		FROM occupancy_reports
+		WHERE route_id = ANY($1) AND reported_at >= $2
+		GROUP BY route_id, hour
+	`
+
+	var aggregates = make([]model.OccupancyAggregate, 0)
+	err := r.db.SelectContext(ctx, &aggregates, query, pq.Array(routeIds), since, timezone)
+	if err != nil {
+		return nil, fault.New("failed to retrieve hourly occupancy", fault.WithError(err))
+	}
+
+	return aggregates, nil
+}
+
+func (r repo) GetHeatmap(ctx context.Context, routeId string, from, to time.Time, timezone string) ([]model.OccupancyAggregate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var query = `
+		SELECT
+			route_id,
+			EXTRACT(DOW FROM reported_at AT TIME ZONE $4)::int AS weekday,
+			EXTRACT(HOUR FROM reported_at AT TIME ZONE $4)::int AS hour,
+			AVG(` + scoreExpr + `) AS score,
+			COUNT(*) AS reports
+		FROM occupancy_reports
+		WHERE route_id = $1 AND reported_at >= $2 AND reported_at < $3
+		GROUP BY route_id, weekday, hour
+		ORDER BY weekday, hour
+	`
+
+	var aggregates = make([]model.OccupancyAggregate, 0)
+	err := r.db.SelectContext(ctx, &aggregates, query, routeId, from, to, timezone)
+	if err != nil {
+		return nil, fault.New("failed to retrieve occupancy heatmap", fault.WithError(err))
+	}
+
+	return aggregates, nil
+}
@@ -0,0 +1,236 @@
+package occupancy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/cache"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	occupancyServiceJourney = "occupancy service"
+	// reportCooldown is how long a user waits before reporting the same route again
+	reportCooldown = time.Minute * 5
+	// recentWindow is how far back reports describe the buses running right now
+	recentWindow = time.Minute * 30
+	// historyWindow is how far back reports are averaged by hour of the day
+	historyWindow = time.Hour * 24 * 28
+	// minHistoryReports keeps a single rider from setting the estimate of an hour
+	minHistoryReports = 3
+	// estimateTTL is how long an estimate is reused, short as recent reports change it
+	estimateTTL = time.Minute
+	// DefaultHeatmapWeeks is how many weeks the heatmap covers when not given
+	DefaultHeatmapWeeks = 4
+	maxHeatmapWeeks     = 26
+
+	SourceRecent  = "recent"
+	SourceHistory = "history"
+)
+
+type ServiceConfig struct {
+	OccupancyRepo Repository
+	Cache         *cache.Cache
+}
+
+type service struct {
+	occupancyRepo Repository
+	cache         *cache.Cache
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		occupancyRepo: c.OccupancyRepo,
+		cache:         c.Cache,
+	}
+}
+
+func (s service) ReportOccupancy(ctx context.Context, userId string, input dto.ReportOccupancy) (*dto.OccupancyReportResponse, error) {
+	report, err := NewReport(userId, input.RouteID, input.StopID, input.Level)
+	if err != nil {
+		logging.Error("failed to create occupancy report", err,
+			zap.String("journey", occupancyServiceJourney))
+		return nil, fault.NewUnprocessableEntity("failed to create occupancy report entity")
+	}
+	model := report.Model()
+
+	// The cooldown is taken before inserting, so concurrent reports of a user cannot both get in
+	ok, err := s.cache.SetNX(ctx, cooldownKey(userId, model.RouteID), model.ID, reportCooldown)
+	if err != nil {
+		logging.Error("failed to set occupancy report cooldown", err,
+			zap.String("journey", occupancyServiceJourney))
+		return nil, fault.NewBadRequest("failed to report occupancy")
+	}
+	if !ok {
+		return nil, fault.NewTooManyRequests(fmt.Sprintf("occupancy of a route may be reported once every %d minutes", int(reportCooldown.Minutes())))
+	}
+
+	inserted, err := s.occupancyRepo.Insert(ctx, model)
+	if err != nil || !inserted {
+		// The report did not count, so it does not hold the user back either
+		s.releaseCooldown(ctx, userId, model.RouteID)
+	}
+	if err != nil {
+		logging.Error("failed to insert occupancy report", err,
+			zap.String("journey", occupancyServiceJourney))
+		return nil, fault.NewBadRequest("failed to report occupancy")
+	}
+	if !inserted {
+		return nil, fault.NewUnprocessableEntity("stop is not served by the route")
+	}
+
+	return &dto.OccupancyReportResponse{
+		ID:         model.ID,
+		RouteID:    model.RouteID,
+		StopID:     model.LocationID,
+		Level:      model.Level,
+		ReportedAt: model.ReportedAt,
+	}, nil
+}
+
+func (s service) GetOccupancyEstimates(ctx context.Context, routeIds []string) (map[string]dto.RouteOccupancyEstimate, error) {
+	res := make(map[string]dto.RouteOccupancyEstimate, len(routeIds))
+
+	missing := make([]string, 0, len(routeIds))
+	for _, id := range routeIds {
+		var estimate dto.RouteOccupancyEstimate
+		err := s.cache.GetStruct(ctx, estimateKey(id), &estimate)
+		if err == nil {
+			res[id] = estimate
+			continue
+		}
+		if fault.GetTag(err) != fault.CACHE_MISS {
+			logging.Error("failed to query occupancy estimate from cache", err,
+				zap.String("journey", occupancyServiceJourney))
+		}
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return res, nil
+	}
+
+	now := time.Now()
+	recent, err := s.occupancyRepo.GetRecentAverages(ctx, missing, now.Add(-recentWindow))
+	if err != nil {
+		logging.Error("failed to retrieve recent occupancy", err,
+			zap.String("journey", occupancyServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve occupancy")
+	}
+
+	hourly, err := s.occupancyRepo.GetHourlyAverages(ctx, missing, now.Add(-historyWindow), route.Timezone().String())
+	if err != nil {
+		logging.Error("failed to retrieve hourly occupancy", err,
+			zap.String("journey", occupancyServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve occupancy")
+	}
+
+	estimates := make(map[string]*dto.RouteOccupancyEstimate, len(missing))
+	for _, id := range missing {
+		estimates[id] = &dto.RouteOccupancyEstimate{Hours: make(map[int]dto.OccupancyResponse)}
+	}
+	for _, a := range recent {
+		o := newOccupancyResponse(a, SourceRecent)
+		estimates[a.RouteID].Recent = &o
+	}
+	for _, a := range hourly {
+		if a.Reports < minHistoryReports {
+			continue
+		}
+		estimates[a.RouteID].Hours[a.Hour] = newOccupancyResponse(a, SourceHistory)
+	}
+
+	for id, estimate := range estimates {
+		res[id] = *estimate
+
+		err := s.cache.SetStruct(ctx, estimateKey(id), estimate, estimateTTL)
+		if err != nil {
+			logging.Error("failed to cache occupancy estimate", err,
+				zap.String("journey", occupancyServiceJourney))
+		}
+	}
+
+	return res, nil
+}
+
+func (s service) GetHeatmap(ctx context.Context, routeId string, weeks int) (*dto.OccupancyHeatmapResponse, error) {
+	if weeks < 1 || weeks > maxHeatmapWeeks {
+		return nil, fault.NewBadRequest(fmt.Sprintf("weeks must be between 1 and %d", maxHeatmapWeeks))
+	}
+
+	exists, err := s.occupancyRepo.RouteExists(ctx, routeId)
+	if err != nil {
+		logging.Error("failed to check route", err,
+			zap.String("journey", occupancyServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve route")
+	} else if !exists {
+		logging.Info("route not found",
+			zap.String("journey", occupancyServiceJourney),
+			zap.String("routeID", routeId))
+		return nil, fault.NewNotFound("route not found")
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -7*weeks)
+
+	aggregates, err := s.occupancyRepo.GetHeatmap(ctx, routeId, from, to, route.Timezone().String())
+	if err != nil {
+		logging.Error("failed to retrieve occupancy heatmap", err,
+			zap.String("journey", occupancyServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve occupancy heatmap")
+	}
+
+	res := &dto.OccupancyHeatmapResponse{
+		RouteID: routeId,
+		From:    from,
+		To:      to,
+		Cells:   make([]dto.OccupancyHeatmapCell, 0, len(aggregates)),
+	}
+	for _, a := range aggregates {
+		if a.Reports < minHistoryReports {
+			continue
+		}
+		o := newOccupancyResponse(a, SourceHistory)
+		res.Cells = append(res.Cells, dto.OccupancyHeatmapCell{
+			Weekday: a.Weekday,
+			Hour:    a.Hour,
+			Level:   o.Level,
+			Score:   o.Score,
+			Reports: o.Reports,
+		})
+	}
+
+	return res, nil
+}
+
+func (s service) releaseCooldown(ctx context.Context, userId, routeId string) {
+	err := s.cache.Delete(ctx, cooldownKey(userId, routeId))
+	if err != nil {
+		logging.Error("failed to release occupancy report cooldown", err,
+			zap.String("journey", occupancyServiceJourney))
+	}
+}
+
+func newOccupancyResponse(a model.OccupancyAggregate, source string) dto.OccupancyResponse {
+	return dto.OccupancyResponse{
+		Level:   levelOf(a.Score),
+		Score:   math.Round(a.Score*10) / 10,
+		Reports: a.Reports,
+		Source:  source,
+	}
+}
+
+// cooldownKey is the cache key that keeps the user from reporting the route again too soon
+func cooldownKey(userId, routeId string) string {
+	return fmt.Sprintf("occupancy:cooldown:%s:%s", userId, routeId)
+}
+
+func estimateKey(routeId string) string {
+	return fmt.Sprintf("occupancy:estimate:%s", routeId)
+}
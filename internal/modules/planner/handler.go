@@ -0,0 +1,79 @@
+package planner
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/timeofday"
+	"github.com/go-chi/chi/v5"
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	plannerService Service
+}
+
+func NewHandler(plannerService Service) *handler {
+	once.Do(func() {
+		instance = &handler{
+			plannerService: plannerService,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	// Public
+	r.Get("/api/v1/plan", h.handlePlan)
+}
+
+// handlePlan answers GET /api/v1/plan?from=&to=&depart_at=, where from and to are stop IDs
+// and depart_at is either a RFC 3339 timestamp or a time of day today, defaulting to now
+func (h handler) handlePlan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qs := r.URL.Query()
+
+	departAt, err := parseDepartAt(qs.Get("depart_at"))
+	if err != nil {
+		fault.NewHTTPError(w, fault.NewBadRequest("depart_at must be a RFC 3339 timestamp or a time of day"))
+		return
+	}
+
+	res, err := h.plannerService.Plan(ctx, dto.PlanQuery{
+		From:     qs.Get("from"),
+		To:       qs.Get("to"),
+		DepartAt: departAt,
+	})
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func parseDepartAt(v string) (time.Time, error) {
+	now := route.Now()
+	if v == "" {
+		return now, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+
+	t, err := timeofday.Parse(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.On(now), nil
+}
@@ -0,0 +1,11 @@
+package planner
+
+import (
+	"context"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+)
+
+type Service interface {
+	Plan(ctx context.Context, query dto.PlanQuery) (*dto.PlanResponse, error)
+}
@@ -0,0 +1,149 @@
+package planner
+
+import (
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geo"
+)
+
+// patternStop is a stop served by a pattern, in the route stop order.
+// The route stop departure and arrival flags only tell the timetable listing
+// a stop comes from, so every stop allows boarding and alighting.
+type patternStop struct {
+	locationId string
+}
+
+// trip is a departure of a route bound to a service day, with the
+// estimated time it reaches every stop of the pattern
+type trip struct {
+	departureId string
+	times       []time.Time
+}
+
+// pattern is a route as seen by the search, its trips sorted by departure
+type pattern struct {
+	routeId   string
+	routeName string
+	stops     []patternStop
+	trips     []trip
+}
+
+// patternRef locates a stop inside a pattern
+type patternRef struct {
+	pattern int
+	index   int
+}
+
+// footpath is a walk between two stops close to each other
+type footpath struct {
+	to       string
+	distance float64
+	duration time.Duration
+}
+
+// network is the timetable of a few service days laid out for the search
+type network struct {
+	patterns   []pattern
+	patternsAt map[string][]patternRef
+	footpaths  map[string][]footpath
+	locations  map[string]dto.LocationResponse
+}
+
+// newNetwork builds the network of the routes running on the service days starting at the day of from
+func newNetwork(routes []dto.RouteResponse, days calendar.ServiceDays, from time.Time, serviceDays int) *network {
+	n := &network{
+		patternsAt: make(map[string][]patternRef),
+		footpaths:  make(map[string][]footpath),
+		locations:  make(map[string]dto.LocationResponse),
+	}
+
+	midnight := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for _, r := range routes {
+		if len(r.Stops) < 2 {
+			continue
+		}
+
+		p := pattern{routeId: r.ID, routeName: r.Name}
+		lastOrder := r.Stops[len(r.Stops)-1].StopOrder
+		offsets := make([]time.Duration, len(r.Stops))
+		for i, st := range r.Stops {
+			p.stops = append(p.stops, patternStop{locationId: st.Location.ID})
+			offsets[i] = route.StopOffset(r.TripLength, st.StopOrder, lastOrder)
+			n.locations[st.Location.ID] = st.Location
+		}
+
+		for i := range serviceDays {
+			day := midnight.AddDate(0, 0, i)
+			for _, d := range r.Departures {
+				if !days.Runs(d.CalendarID, day) {
+					continue
+				}
+
+				departsAt := d.Time.On(day)
+				times := make([]time.Time, len(offsets))
+				for j, offset := range offsets {
+					times[j] = departsAt.Add(offset)
+				}
+				p.trips = append(p.trips, trip{departureId: d.ID, times: times})
+			}
+		}
+		if len(p.trips) == 0 {
+			continue
+		}
+
+		slices.SortFunc(p.trips, func(a, b trip) int {
+			return a.times[0].Compare(b.times[0])
+		})
+
+		for i, st := range p.stops {
+			n.patternsAt[st.locationId] = append(n.patternsAt[st.locationId], patternRef{
+				pattern: len(n.patterns),
+				index:   i,
+			})
+		}
+		n.patterns = append(n.patterns, p)
+	}
+
+	n.buildFootpaths()
+
+	return n
+}
+
+// buildFootpaths links every located stop to the other stops within walking distance
+func (n *network) buildFootpaths() {
+	entries := make([]geo.Entry, 0, len(n.locations))
+	for id, l := range n.locations {
+		if l.Latitude != nil && l.Longitude != nil {
+			entries = append(entries, geo.Entry{ID: id, Point: geo.Point{Lat: *l.Latitude, Lon: *l.Longitude}})
+		}
+	}
+
+	index := geo.NewIndex(entries)
+	for _, e := range entries {
+		for _, m := range index.Nearby(e.Point, maxTransferWalk) {
+			if m.ID == e.ID {
+				continue
+			}
+			n.footpaths[e.ID] = append(n.footpaths[e.ID], footpath{
+				to:       m.ID,
+				distance: geo.WalkingDistance(m.Distance),
+				duration: geo.WalkingDuration(m.Distance),
+			})
+		}
+	}
+}
+
+// earliestTrip returns the first trip of the pattern leaving the stop at index at or after t, -1 if none
+func (p *pattern) earliestTrip(index int, t time.Time) int {
+	i, _ := slices.BinarySearchFunc(p.trips, t, func(tr trip, t time.Time) int {
+		return tr.times[index].Compare(t)
+	})
+	if i == len(p.trips) {
+		return -1
+	}
+	return i
+}
@@ -0,0 +1,222 @@
+package planner
+
+import (
+	"time"
+)
+
+type labelKind int
+
+const (
+	labelOrigin labelKind = iota
+	labelRide
+	labelWalk
+)
+
+// label is how a stop was reached in a round of the search
+type label struct {
+	kind    labelKind
+	arrival time.Time
+
+	// Set on rides
+	pattern    int
+	trip       int
+	boardIndex int
+	alighting  int
+	boardRound int
+
+	// Set on walks, via is the ride the walk starts from
+	walk *footpath
+	from string
+	via  *label
+}
+
+// journey is a path found by the search, its legs in travel order
+type journey struct {
+	legs []journeyLeg
+}
+
+type journeyLeg struct {
+	kind labelKind
+	from string
+	to   string
+	// readyAt is when the traveller reaches the start of the leg
+	readyAt time.Time
+
+	// Set on rides
+	pattern    int
+	trip       int
+	boardIndex int
+	alighting  int
+
+	// Set on walks
+	walk *footpath
+}
+
+// search runs RAPTOR (round-based public transit routing) from the stop at
+// departAt to the target, allowing up to maxRounds rides. It returns the Pareto
+// optimal journeys: each one arrives strictly earlier than those with fewer rides.
+func (n *network) search(from, to string, departAt time.Time, maxRounds int) []journey {
+	labels := make([]map[string]*label, 1, maxRounds+1)
+	labels[0] = map[string]*label{from: {kind: labelOrigin, arrival: departAt}}
+	best := map[string]time.Time{from: departAt}
+
+	improves := func(t time.Time, stop string) bool {
+		b, ok := best[stop]
+		if ok && !t.Before(b) {
+			return false
+		}
+		// Target pruning, nothing arriving after the best known arrival is useful
+		target, ok := best[to]
+		return !ok || t.Before(target)
+	}
+
+	marked := []string{from}
+	for _, fp := range n.footpaths[from] {
+		arrival := departAt.Add(fp.duration)
+		if improves(arrival, fp.to) {
+			labels[0][fp.to] = &label{kind: labelWalk, arrival: arrival, walk: &fp, from: from, via: labels[0][from]}
+			best[fp.to] = arrival
+			marked = append(marked, fp.to)
+		}
+	}
+
+	for k := 1; k <= maxRounds && len(marked) > 0; k++ {
+		labels = append(labels, make(map[string]*label))
+
+		// Every pattern serving a marked stop is scanned from the first of them
+		queue := make([]int, len(n.patterns))
+		for p := range queue {
+			queue[p] = -1
+		}
+		for _, stop := range marked {
+			for _, ref := range n.patternsAt[stop] {
+				if start := queue[ref.pattern]; start < 0 || ref.index < start {
+					queue[ref.pattern] = ref.index
+				}
+			}
+		}
+
+		rides := make([]string, 0)
+		for p, start := range queue {
+			if start < 0 {
+				continue
+			}
+			pat := &n.patterns[p]
+			current, boardIndex, boardRound := -1, 0, 0
+
+			for i := start; i < len(pat.stops); i++ {
+				st := pat.stops[i]
+
+				if current >= 0 {
+					arrival := pat.trips[current].times[i]
+					if improves(arrival, st.locationId) {
+						labels[k][st.locationId] = &label{
+							kind:       labelRide,
+							arrival:    arrival,
+							pattern:    p,
+							trip:       current,
+							boardIndex: boardIndex,
+							alighting:  i,
+							boardRound: boardRound,
+						}
+						best[st.locationId] = arrival
+						rides = append(rides, st.locationId)
+					}
+				}
+
+				round, prev := latestLabel(labels[:k], st.locationId)
+				if prev == nil {
+					continue
+				}
+
+				ready := prev.arrival
+				if prev.kind == labelRide {
+					ready = ready.Add(minTransferTime)
+				}
+				if current >= 0 && pat.trips[current].times[i].Before(ready) {
+					continue
+				}
+				if t := pat.earliestTrip(i, ready); t >= 0 && (current < 0 || t < current) {
+					current, boardIndex, boardRound = t, i, round
+				}
+			}
+		}
+
+		// Walks only start from stops reached by a ride in this round, never chaining
+		marked = make([]string, 0, len(rides))
+		for _, stop := range rides {
+			ride := labels[k][stop]
+			if ride.kind != labelRide {
+				continue
+			}
+			marked = append(marked, stop)
+
+			for _, fp := range n.footpaths[stop] {
+				arrival := ride.arrival.Add(fp.duration)
+				if improves(arrival, fp.to) {
+					labels[k][fp.to] = &label{kind: labelWalk, arrival: arrival, walk: &fp, from: stop, via: ride}
+					best[fp.to] = arrival
+					marked = append(marked, fp.to)
+				}
+			}
+		}
+	}
+
+	res := make([]journey, 0)
+	for k := range labels {
+		if l, ok := labels[k][to]; ok && l.kind != labelOrigin {
+			res = append(res, n.reconstruct(labels, to, l))
+		}
+	}
+
+	return res
+}
+
+// reconstruct walks the labels back from the target
+func (n *network) reconstruct(labels []map[string]*label, stop string, l *label) journey {
+	legs := make([]journeyLeg, 0)
+	for l != nil && l.kind != labelOrigin {
+		switch l.kind {
+		case labelWalk:
+			legs = append(legs, journeyLeg{
+				kind:    labelWalk,
+				from:    l.from,
+				to:      stop,
+				readyAt: l.via.arrival,
+				walk:    l.walk,
+			})
+			stop, l = l.from, l.via
+		case labelRide:
+			pat := n.patterns[l.pattern]
+			boardStop := pat.stops[l.boardIndex].locationId
+			prev := labels[l.boardRound][boardStop]
+			legs = append(legs, journeyLeg{
+				kind:       labelRide,
+				from:       boardStop,
+				to:         stop,
+				readyAt:    prev.arrival,
+				pattern:    l.pattern,
+				trip:       l.trip,
+				boardIndex: l.boardIndex,
+				alighting:  l.alighting,
+			})
+			stop, l = boardStop, prev
+		}
+	}
+	for i, j := 0, len(legs)-1; i < j; i, j = i+1, j-1 {
+		legs[i], legs[j] = legs[j], legs[i]
+	}
+
+	return journey{legs: legs}
+}
+
+// latestLabel returns the label of the stop from the latest round that reached it,
+// which is also its earliest arrival
+func latestLabel(labels []map[string]*label, stop string) (int, *label) {
+	for k := len(labels) - 1; k >= 0; k-- {
+		if l, ok := labels[k][stop]; ok {
+			return k, l
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,257 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/timetable"
+)
+
+// monday is a regular weekday, so every departure of the timetable runs on it
+var monday = time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+// testNetwork builds the network of the official timetable for a single
+// monday. Locations are identified by name and have no coordinates, so the
+// search never walks.
+func testNetwork(t *testing.T) *network {
+	t.Helper()
+
+	tt, err := timetable.Load("../../../data/timetable.yaml")
+	if err != nil {
+		t.Fatalf("failed to load timetable: %v", err)
+	}
+
+	routes := make([]dto.RouteResponse, 0, len(tt.Routes))
+	for _, r := range tt.Routes {
+		res := dto.RouteResponse{ID: r.ID, Name: r.Name, TripLength: r.TripLength}
+		for order, st := range r.Stops {
+			res.Stops = append(res.Stops, dto.RouteStopResponse{
+				ID:        r.ID + "-" + st.Name,
+				Location:  dto.LocationResponse{ID: st.Name, Name: st.Name},
+				StopOrder: order,
+			})
+		}
+		for _, d := range r.DepartureTimes() {
+			res.Departures = append(res.Departures, dto.DepartureTimeResponse{
+				ID:         r.ID + "-" + d.Time.String(),
+				CalendarID: d.CalendarID,
+				Time:       d.Time,
+			})
+		}
+		routes = append(routes, res)
+	}
+
+	days := calendar.ServiceDays{
+		monday.Format(calendar.DateLayout): {timetable.DefaultCalendar: true},
+	}
+
+	return newNetwork(routes, days, monday, 1)
+}
+
+func at(hour, minute int) time.Time {
+	return monday.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+// rides returns the ride legs of the journey
+func rides(j journey) []journeyLeg {
+	res := make([]journeyLeg, 0, len(j.legs))
+	for _, l := range j.legs {
+		if l.kind == labelRide {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+func (n *network) boardsAt(l journeyLeg) time.Time {
+	return n.patterns[l.pattern].trips[l.trip].times[l.boardIndex]
+}
+
+func (n *network) alightsAt(l journeyLeg) time.Time {
+	return n.patterns[l.pattern].trips[l.trip].times[l.alighting]
+}
+
+func TestNewNetwork(t *testing.T) {
+	n := testNetwork(t)
+
+	if len(n.patterns) != 6 {
+		t.Fatalf("expected 6 patterns, got %d", len(n.patterns))
+	}
+	for _, p := range n.patterns {
+		for i := 1; i < len(p.trips); i++ {
+			if p.trips[i].times[0].Before(p.trips[i-1].times[0]) {
+				t.Errorf("trips of %s are not sorted by departure", p.routeId)
+			}
+		}
+	}
+	if len(n.footpaths) != 0 {
+		t.Errorf("expected no footpaths between stops without coordinates, got %d", len(n.footpaths))
+	}
+
+	// B1 runs São Lázaro to Creche – Canela in 11 minutes over 13 stops
+	refs := n.patternsAt["Reitoria"]
+	var b1 *pattern
+	var reitoria int
+	for _, ref := range refs {
+		if n.patterns[ref.pattern].routeId == "B1" {
+			b1, reitoria = &n.patterns[ref.pattern], ref.index
+		}
+	}
+	if b1 == nil {
+		t.Fatal("expected B1 to serve Reitoria")
+	}
+	first := b1.trips[0]
+	if !first.times[0].Equal(at(6, 10)) {
+		t.Errorf("expected the first B1 trip at 06:10, got %s", first.times[0].Format(time.TimeOnly))
+	}
+	if !first.times[reitoria].Equal(at(6, 20)) {
+		t.Errorf("expected the first B1 trip at Reitoria at 06:20, got %s", first.times[reitoria].Format(time.TimeOnly))
+	}
+}
+
+func TestSearchDirectRide(t *testing.T) {
+	n := testNetwork(t)
+
+	journeys := n.search("São Lázaro", "Reitoria", at(6, 0), maxTransfers+1)
+	if len(journeys) != 1 {
+		t.Fatalf("expected a single journey, got %d", len(journeys))
+	}
+
+	legs := rides(journeys[0])
+	if len(legs) != 1 {
+		t.Fatalf("expected a direct ride, got %d rides", len(legs))
+	}
+	if route := n.patterns[legs[0].pattern].routeId; route != "B1" {
+		t.Errorf("expected a ride on B1, got %s", route)
+	}
+	if got := n.boardsAt(legs[0]); !got.Equal(at(6, 10)) {
+		t.Errorf("expected to board at 06:10, got %s", got.Format(time.TimeOnly))
+	}
+	if got := n.alightsAt(legs[0]); !got.Equal(at(6, 20)) {
+		t.Errorf("expected to arrive at 06:20, got %s", got.Format(time.TimeOnly))
+	}
+}
+
+func TestSearchTransfer(t *testing.T) {
+	n := testNetwork(t)
+
+	// The EXPRESSO reaches Politécnica at 06:40, changing from B1 to B4 at
+	// Reitoria gets there at 06:31
+	journeys := n.search("Estacionamento PAF I - Matemática", "Politécnica", at(6, 0), maxTransfers+1)
+	if len(journeys) != 2 {
+		t.Fatalf("expected a direct and a transfer journey, got %d", len(journeys))
+	}
+
+	direct := rides(journeys[0])
+	if len(direct) != 1 || n.patterns[direct[0].pattern].routeId != "EXPRESSO" {
+		t.Fatalf("expected a direct ride on the EXPRESSO first, got %d rides", len(direct))
+	}
+	if got := n.alightsAt(direct[0]); !got.Equal(at(6, 40)) {
+		t.Errorf("expected the direct ride to arrive at 06:40, got %s", got.Format(time.TimeOnly))
+	}
+
+	legs := rides(journeys[1])
+	if len(legs) != 2 {
+		t.Fatalf("expected two rides, got %d", len(legs))
+	}
+	first, second := legs[0], legs[1]
+	if n.patterns[first.pattern].routeId != "B1" || n.patterns[second.pattern].routeId != "B4" {
+		t.Errorf("expected B1 then B4, got %s then %s",
+			n.patterns[first.pattern].routeId, n.patterns[second.pattern].routeId)
+	}
+	if first.to != "Reitoria" || second.from != "Reitoria" {
+		t.Errorf("expected the transfer at Reitoria, got %s and %s", first.to, second.from)
+	}
+	if !second.readyAt.Equal(at(6, 20)) {
+		t.Errorf("expected to reach Reitoria at 06:20, got %s", second.readyAt.Format(time.TimeOnly))
+	}
+	if got := n.boardsAt(second); !got.Equal(at(6, 23)) {
+		t.Errorf("expected to board B4 at 06:23, got %s", got.Format(time.TimeOnly))
+	}
+	if got := n.alightsAt(second); !got.Equal(at(6, 31)) {
+		t.Errorf("expected to arrive at 06:31, got %s", got.Format(time.TimeOnly))
+	}
+}
+
+func TestSearchMinTransferTime(t *testing.T) {
+	n := testNetwork(t)
+
+	// B2 reaches Instituto de Geociências at 06:13 and B1 leaves it at 06:15,
+	// exactly the time given to change buses
+	journeys := n.search("Reitoria", "Belas Artes", at(6, 0), maxTransfers+1)
+	if len(journeys) == 0 {
+		t.Fatal("expected a journey")
+	}
+
+	legs := rides(journeys[len(journeys)-1])
+	if len(legs) != 2 {
+		t.Fatalf("expected two rides, got %d", len(legs))
+	}
+	if wait := n.boardsAt(legs[1]).Sub(n.alightsAt(legs[0])); wait != minTransferTime {
+		t.Errorf("expected a transfer wait of %s, got %s", minTransferTime, wait)
+	}
+}
+
+func TestSearchRounds(t *testing.T) {
+	n := testNetwork(t)
+
+	// A single round only finds the direct ride, even if a transfer arrives earlier
+	journeys := n.search("Estacionamento PAF I - Matemática", "Politécnica", at(6, 0), 1)
+	if len(journeys) != 1 {
+		t.Fatalf("expected a single journey, got %d", len(journeys))
+	}
+	if legs := rides(journeys[0]); len(legs) != 1 || !n.alightsAt(legs[0]).Equal(at(6, 40)) {
+		t.Errorf("expected the direct ride arriving at 06:40")
+	}
+
+	// Nothing leaving Direito reaches São Lázaro without changing buses
+	journeys = n.search("Direito", "São Lázaro", at(6, 0), 1)
+	if len(journeys) != 0 {
+		t.Errorf("expected no journey with a single ride, got %d", len(journeys))
+	}
+	journeys = n.search("Direito", "São Lázaro", at(6, 0), 2)
+	if len(journeys) != 1 || len(rides(journeys[0])) != 2 {
+		t.Errorf("expected a journey with two rides")
+	}
+}
+
+func TestSearchNoRoute(t *testing.T) {
+	n := testNetwork(t)
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		departAt time.Time
+	}{
+		{
+			// Direito is only served as the first stop of B3
+			name:     "unreachable stop",
+			from:     "Reitoria",
+			to:       "Direito",
+			departAt: at(6, 0),
+		},
+		{
+			name:     "after the last departure",
+			from:     "São Lázaro",
+			to:       "Reitoria",
+			departAt: at(23, 30),
+		},
+		{
+			name:     "unknown stop",
+			from:     "São Lázaro",
+			to:       "Centro de Esportes",
+			departAt: at(6, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if journeys := n.search(tt.from, tt.to, tt.departAt, maxTransfers+1); len(journeys) != 0 {
+				t.Errorf("expected no journey, got %d", len(journeys))
+			}
+		})
+	}
+}
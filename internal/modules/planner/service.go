@@ -0,0 +1,213 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	plannerServiceJourney = "planner service"
+	// maxTransfers caps how many times a journey may change buses
+	maxTransfers = 3
+	// minTransferTime is the time given to get off a bus and on another at the same stop
+	minTransferTime = time.Minute * 2
+	// maxTransferWalk is the straight distance, in meters, walked between two stops on a transfer
+	maxTransferWalk = 400
+	// searchDays is how many service days are searched, so late queries find the first buses of the next day
+	searchDays = 2
+	// maxItineraries is the number of itineraries returned
+	maxItineraries = 3
+)
+
+type ServiceConfig struct {
+	RouteService    route.Service
+	CalendarService calendar.Service
+}
+
+type service struct {
+	routeService    route.Service
+	calendarService calendar.Service
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		routeService:    c.RouteService,
+		calendarService: c.CalendarService,
+	}
+}
+
+func (s service) Plan(ctx context.Context, query dto.PlanQuery) (*dto.PlanResponse, error) {
+	if query.From == "" || query.To == "" {
+		return nil, fault.NewBadRequest("origin and destination stops are required")
+	}
+	if query.From == query.To {
+		return nil, fault.NewBadRequest("origin and destination must be different stops")
+	}
+
+	routes, err := s.routeService.GetAllRoutes(ctx)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	departAt := query.DepartAt.In(route.Timezone())
+	day := time.Date(departAt.Year(), departAt.Month(), departAt.Day(), 0, 0, 0, 0, route.Timezone())
+	days, err := s.calendarService.GetServiceDays(ctx, day, searchDays)
+	if err != nil {
+		return nil, err // The error is already being handled in the calendar service
+	}
+
+	n := newNetwork(routes, days, day, searchDays)
+
+	from, ok := n.locations[query.From]
+	if !ok {
+		logging.Info("origin stop not found",
+			zap.String("journey", plannerServiceJourney),
+			zap.String("locationID", query.From))
+		return nil, fault.NewNotFound("origin stop not found")
+	}
+	to, ok := n.locations[query.To]
+	if !ok {
+		logging.Info("destination stop not found",
+			zap.String("journey", plannerServiceJourney),
+			zap.String("locationID", query.To))
+		return nil, fault.NewNotFound("destination stop not found")
+	}
+
+	res := &dto.PlanResponse{
+		From:        from,
+		To:          to,
+		DepartAt:    departAt,
+		Itineraries: make([]dto.ItineraryResponse, 0, maxItineraries),
+	}
+
+	// Each search returns the fastest journeys for a departure time, searching
+	// again right after the earliest of them finds the next alternatives
+	seen := make(map[string]bool)
+	t := departAt
+	for range maxItineraries {
+		journeys := n.search(query.From, query.To, t, maxTransfers+1)
+		if len(journeys) == 0 {
+			break
+		}
+
+		var next time.Time
+		for _, j := range journeys {
+			it := n.itinerary(j, t)
+			if key := n.journeyKey(j); !seen[key] {
+				seen[key] = true
+				res.Itineraries = append(res.Itineraries, it)
+			}
+			if next.IsZero() || it.DepartsAt.Before(next) {
+				next = it.DepartsAt
+			}
+		}
+		t = next.Add(time.Minute)
+	}
+
+	slices.SortFunc(res.Itineraries, func(a, b dto.ItineraryResponse) int {
+		if c := a.ArrivesAt.Compare(b.ArrivesAt); c != 0 {
+			return c
+		}
+		return a.Transfers - b.Transfers
+	})
+	if len(res.Itineraries) > maxItineraries {
+		res.Itineraries = res.Itineraries[:maxItineraries]
+	}
+
+	return res, nil
+}
+
+// itinerary describes a journey found by a search started at departAt.
+// The traveller leaves just in time to catch the first bus, so waits only happen on transfers.
+func (n *network) itinerary(j journey, departAt time.Time) dto.ItineraryResponse {
+	legs := make([]dto.PlanLegResponse, len(j.legs))
+	rides := 0
+	for i, l := range j.legs {
+		leg := dto.PlanLegResponse{
+			From: n.locations[l.from],
+			To:   n.locations[l.to],
+		}
+
+		switch l.kind {
+		case labelRide:
+			pat := n.patterns[l.pattern]
+			tr := pat.trips[l.trip]
+			leg.Mode = "bus"
+			leg.RouteID = pat.routeId
+			leg.RouteName = pat.routeName
+			leg.DepartureID = tr.departureId
+			leg.DepartsAt = tr.times[l.boardIndex]
+			leg.ArrivesAt = tr.times[l.alighting]
+			leg.Stops = l.alighting - l.boardIndex
+			rides++
+		case labelWalk:
+			leg.Mode = "walk"
+			leg.DepartsAt = l.readyAt
+			leg.ArrivesAt = l.readyAt.Add(l.walk.duration)
+			leg.DistanceMeters = int(l.walk.distance)
+		}
+		legs[i] = leg
+	}
+
+	// Delay the legs before the first bus so nobody waits at the first stop
+	for i, leg := range legs {
+		if leg.Mode != "bus" {
+			continue
+		}
+		shift := leg.DepartsAt.Sub(departAt)
+		for j := range i {
+			shift -= legs[j].ArrivesAt.Sub(legs[j].DepartsAt)
+		}
+		for j := range i {
+			legs[j].DepartsAt = legs[j].DepartsAt.Add(shift)
+			legs[j].ArrivesAt = legs[j].ArrivesAt.Add(shift)
+		}
+		break
+	}
+
+	wait := time.Duration(0)
+	for i := 1; i < len(legs); i++ {
+		if legs[i].Mode != "bus" {
+			continue
+		}
+		w := legs[i].DepartsAt.Sub(legs[i-1].ArrivesAt)
+		legs[i].WaitMinutes = int(w.Minutes())
+		wait += w
+	}
+
+	res := dto.ItineraryResponse{
+		Legs:        legs,
+		WaitMinutes: int(wait.Minutes()),
+		Transfers:   max(rides-1, 0),
+	}
+	if len(legs) > 0 {
+		res.DepartsAt = legs[0].DepartsAt
+		res.ArrivesAt = legs[len(legs)-1].ArrivesAt
+		res.DurationMinutes = int(res.ArrivesAt.Sub(res.DepartsAt).Minutes())
+	}
+
+	return res
+}
+
+// journeyKey identifies a journey by the trips and walks it takes
+func (n *network) journeyKey(j journey) string {
+	parts := make([]string, len(j.legs))
+	for i, l := range j.legs {
+		if l.kind == labelRide {
+			parts[i] = fmt.Sprintf("%s@%d-%d", n.patterns[l.pattern].trips[l.trip].departureId, l.boardIndex, l.alighting)
+			continue
+		}
+		parts[i] = l.from + ">" + l.to
+	}
+	return strings.Join(parts, "|")
+}
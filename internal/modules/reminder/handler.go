@@ -0,0 +1,129 @@
+package reminder
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/token"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	reminderHandlerJourney = "reminder handler"
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	reminderService Service
+	secretKey       string
+	sessions        middleware.SessionValidator
+}
+
+func NewHandler(reminderService Service, secretKey string, sessions middleware.SessionValidator) *handler {
+	once.Do(func() {
+		instance = &handler{
+			reminderService: reminderService,
+			secretKey:       secretKey,
+			sessions:        sessions,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
+
+	r.Route("/api/v1/me/reminders", func(r chi.Router) {
+		// Private
+		r.With(m.WithAuth).Get("/", h.handleGetReminders)
+		r.With(m.WithAuth).Post("/", h.handleCreateReminder)
+		r.With(m.WithAuth).Delete("/{reminderId}", h.handleDeleteReminder)
+	})
+}
+
+func (h handler) handleGetReminders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.reminderService.GetReminders(ctx, c.UserID)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleCreateReminder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	var body dto.CreateReminder
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.reminderService.CreateReminder(ctx, c.UserID, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, res)
+}
+
+func (h handler) handleDeleteReminder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+	reminderId := chi.URLParam(r, "reminderId")
+
+	err := h.reminderService.DeleteReminder(ctx, c.UserID, reminderId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+// claimsFromContext reads the claims put by WithAuth, writing the error response when missing
+func claimsFromContext(w http.ResponseWriter, r *http.Request) (*token.Claims, bool) {
+	c, ok := r.Context().Value(middleware.AuthKey{}).(*token.Claims)
+	if !ok {
+		logging.Info("Unable to retrieve claims from token",
+			zap.String("journey", reminderHandlerJourney),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		fault.NewHTTPError(w, fault.NewUnauthorized("invalid access token"))
+	}
+	return c, ok
+}
+
+func logErrorInReadRequestBody(err error, r *http.Request) {
+	logging.Error("failed to read request body", err,
+		zap.String("journey", reminderHandlerJourney),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path))
+}
@@ -0,0 +1,27 @@
+package reminder
+
+import (
+	"context"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+type Repository interface {
+	Insert(ctx context.Context, reminder model.Reminder) error
+	GetByID(ctx context.Context, reminderId string) (*model.Reminder, error)
+	GetAllByUserID(ctx context.Context, userId string) ([]model.Reminder, error)
+	GetAllActive(ctx context.Context) ([]model.Reminder, error)
+	Delete(ctx context.Context, reminderId string) error
+}
+
+type Service interface {
+	GetReminders(ctx context.Context, userId string) ([]dto.ReminderResponse, error)
+	CreateReminder(ctx context.Context, userId string, input dto.CreateReminder) (*dto.ReminderResponse, error)
+	DeleteReminder(ctx context.Context, userId, reminderId string) error
+}
+
+// Notifier delivers reminders through a channel, e.g. email
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
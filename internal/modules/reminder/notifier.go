@@ -0,0 +1,44 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/mail"
+)
+
+// Notification is a reminder due to be delivered to a user
+type Notification struct {
+	Email     string
+	Name      string
+	RouteID   string
+	RouteName string
+	StopName  string
+	DepartsAt time.Time
+	// ArrivesAt is when the bus is estimated to reach the stop
+	ArrivesAt   time.Time
+	LeadMinutes int
+}
+
+type emailNotifier struct {
+	mailer *mail.Mail
+}
+
+// NewEmailNotifier delivers reminders by email
+func NewEmailNotifier(mailer *mail.Mail) Notifier {
+	return &emailNotifier{mailer: mailer}
+}
+
+func (n emailNotifier) Notify(ctx context.Context, notification Notification) error {
+	return n.mailer.Send(mail.SendParams{
+		From: mail.NotificationSender,
+		To:   notification.Email,
+		Subject: fmt.Sprintf("%s passa em %s às %s",
+			notification.RouteName,
+			notification.StopName,
+			notification.ArrivesAt.Format("15:04")),
+		File: "departure_reminder.html",
+		Data: notification,
+	})
+}
@@ -0,0 +1,121 @@
+package reminder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+)
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) Insert(ctx context.Context, reminder model.Reminder) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO reminders (
+			id,
+			user_id,
+			route_id,
+			location_id,
+			weekdays,
+			window_start,
+			window_end,
+			lead_minutes,
+			channel,
+			active,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:user_id,
+			:route_id,
+			:location_id,
+			:weekdays,
+			:window_start,
+			:window_end,
+			:lead_minutes,
+			:channel,
+			:active,
+			:created_at,
+			:updated_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, reminder)
+	if err != nil {
+		return fault.New("failed to insert reminder", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) GetByID(ctx context.Context, reminderId string) (*model.Reminder, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var reminder model.Reminder
+	err := r.db.GetContext(ctx, &reminder, "SELECT * FROM reminders WHERE id = $1 LIMIT 1", reminderId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve reminder", fault.WithError(err))
+	}
+
+	return &reminder, nil
+}
+
+func (r repo) GetAllByUserID(ctx context.Context, userId string) ([]model.Reminder, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var reminders = make([]model.Reminder, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&reminders,
+		"SELECT * FROM reminders WHERE user_id = $1 ORDER BY created_at",
+		userId,
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve reminders", fault.WithError(err))
+	}
+
+	return reminders, nil
+}
+
+func (r repo) GetAllActive(ctx context.Context) ([]model.Reminder, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var reminders = make([]model.Reminder, 0)
+	err := r.db.SelectContext(ctx, &reminders, "SELECT * FROM reminders WHERE active = true ORDER BY location_id")
+	if err != nil {
+		return nil, fault.New("failed to retrieve active reminders", fault.WithError(err))
+	}
+
+	return reminders, nil
+}
+
+func (r repo) Delete(ctx context.Context, reminderId string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM reminders WHERE id = $1", reminderId)
+	if err != nil {
+		return fault.New("failed to delete reminder", fault.WithError(err))
+	}
+
+	return nil
+}
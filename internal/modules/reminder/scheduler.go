@@ -0,0 +1,206 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/user"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/cache"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/timeofday"
+	"go.uber.org/zap"
+)
+
+const (
+	schedulerJourney = "reminder scheduler"
+	// tickInterval is how often the due reminders are looked for
+	tickInterval = time.Minute
+	// stopDepartures is how many upcoming departures of a stop are checked on each tick
+	stopDepartures = 50
+	// sentTTL keeps the idempotency keys well past the departures they guard
+	sentTTL = time.Hour * 48
+)
+
+type SchedulerConfig struct {
+	ReminderRepo Repository
+	UserRepo     user.Repository
+	RouteService route.Service
+	Cache        *cache.Cache
+	// Notifiers maps each channel to the notifier delivering through it
+	Notifiers map[string]Notifier
+}
+
+// Scheduler fires the reminders of the departures about to reach their stops.
+// Every reminder sent is claimed in the cache first, so restarts and other
+// instances of the API never send the same reminder twice.
+type Scheduler struct {
+	reminderRepo Repository
+	userRepo     user.Repository
+	routeService route.Service
+	cache        *cache.Cache
+	notifiers    map[string]Notifier
+}
+
+func NewScheduler(c SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		reminderRepo: c.ReminderRepo,
+		userRepo:     c.UserRepo,
+		routeService: c.RouteService,
+		cache:        c.Cache,
+		notifiers:    c.Notifiers,
+	}
+}
+
+// Run checks for due reminders every minute until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	logging.Info("reminder scheduler started", zap.String("journey", schedulerJourney))
+
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			logging.Info("reminder scheduler stopped", zap.String("journey", schedulerJourney))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	reminders, err := s.reminderRepo.GetAllActive(ctx)
+	if err != nil {
+		logging.Error("failed to retrieve active reminders", err,
+			zap.String("journey", schedulerJourney))
+		return
+	}
+
+	remindersAt := make(map[string][]model.Reminder)
+	for _, r := range reminders {
+		remindersAt[r.LocationID] = append(remindersAt[r.LocationID], r)
+	}
+
+	now := route.Now()
+	users := make(map[string]*model.User)
+	for locationId, rs := range remindersAt {
+		departures, err := s.routeService.GetStopNextDepartures(ctx, locationId, stopDepartures)
+		if err != nil {
+			continue // The error is already being handled in the route service
+		}
+
+		for _, r := range rs {
+			for _, d := range departures.Departures {
+				if d.RouteID != r.RouteID || !matches(r, d.EstimatedArrival) {
+					continue
+				}
+				// Departures come sorted by arrival, the later ones are not due either
+				if now.Before(d.EstimatedArrival.Add(-time.Duration(r.LeadMinutes) * time.Minute)) {
+					break
+				}
+				s.fire(ctx, r, departures.Stop, d, users)
+			}
+		}
+	}
+}
+
+// fire sends the reminder of a departure unless it was already sent
+func (s *Scheduler) fire(
+	ctx context.Context,
+	r model.Reminder,
+	stop dto.LocationResponse,
+	d dto.StopNextDepartureResponse,
+	users map[string]*model.User,
+) {
+	key := fmt.Sprintf("reminder:%s:%s:%s", r.ID, d.DepartureID, d.DepartsAt.Format(calendar.DateLayout))
+	claimed, err := s.cache.SetNX(ctx, key, time.Now().Format(time.RFC3339), sentTTL)
+	if err != nil {
+		// Sending without the claim could send twice, the next tick tries again
+		logging.Error("failed to claim reminder", err,
+			zap.String("journey", schedulerJourney),
+			zap.String("reminderID", r.ID))
+		return
+	} else if !claimed {
+		return
+	}
+
+	notifier, ok := s.notifiers[r.Channel]
+	if !ok {
+		logging.Info("no notifier for reminder channel",
+			zap.String("journey", schedulerJourney),
+			zap.String("reminderID", r.ID),
+			zap.String("channel", r.Channel))
+		return
+	}
+
+	u, ok := users[r.UserID]
+	if !ok {
+		u, err = s.userRepo.GetByID(ctx, r.UserID)
+		if err != nil {
+			logging.Error("failed to retrieve user", err,
+				zap.String("journey", schedulerJourney),
+				zap.String("reminderID", r.ID))
+			s.release(ctx, key)
+			return
+		}
+		users[r.UserID] = u
+	}
+	// Only verified addresses get reminders
+	if u == nil || !u.Activated {
+		return
+	}
+
+	err = notifier.Notify(ctx, Notification{
+		Email:       u.Email,
+		Name:        u.Name,
+		RouteID:     d.RouteID,
+		RouteName:   d.RouteName,
+		StopName:    stop.Name,
+		DepartsAt:   d.DepartsAt,
+		ArrivesAt:   d.EstimatedArrival,
+		LeadMinutes: r.LeadMinutes,
+	})
+	if err != nil {
+		logging.Error("failed to send reminder", err,
+			zap.String("journey", schedulerJourney),
+			zap.String("reminderID", r.ID),
+			zap.String("channel", r.Channel))
+		s.release(ctx, key)
+		return
+	}
+
+	logging.Info("reminder sent",
+		zap.String("journey", schedulerJourney),
+		zap.String("reminderID", r.ID),
+		zap.String("departureID", d.DepartureID))
+}
+
+// release drops the claim of a reminder that could not be sent, so the next tick retries it
+func (s *Scheduler) release(ctx context.Context, key string) {
+	err := s.cache.Delete(ctx, key)
+	if err != nil {
+		logging.Error("failed to release reminder claim", err,
+			zap.String("journey", schedulerJourney))
+	}
+}
+
+// matches reports whether a bus reaching the stop at arrival falls in the reminder window
+func matches(r model.Reminder, arrival time.Time) bool {
+	arrival = arrival.In(route.Timezone())
+
+	t := timeofday.FromTime(arrival)
+	if t < r.WindowStart || t > r.WindowEnd {
+		return false
+	}
+
+	return len(r.Weekdays) == 0 || slices.Contains(r.Weekdays, strings.ToLower(arrival.Weekday().String()))
+}
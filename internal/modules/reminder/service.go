@@ -0,0 +1,197 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+	"go.uber.org/zap"
+)
+
+const (
+	reminderServiceJourney = "reminder service"
+	// maxReminders caps how many reminders a user may keep
+	maxReminders = 10
+	// defaultLeadMinutes is how long before the bus reaches the stop reminders fire when not given
+	defaultLeadMinutes = 10
+	// maxLeadMinutes caps how early a reminder may fire
+	maxLeadMinutes = 60
+
+	ChannelEmail = "email"
+)
+
+// channels are the notification channels reminders may be delivered through
+var channels = []string{ChannelEmail}
+
+// weekdays are the valid day names, as time.Weekday prints them in lower case
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+type ServiceConfig struct {
+	ReminderRepo Repository
+	RouteService route.Service
+}
+
+type service struct {
+	reminderRepo Repository
+	routeService route.Service
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		reminderRepo: c.ReminderRepo,
+		routeService: c.RouteService,
+	}
+}
+
+func (s service) GetReminders(ctx context.Context, userId string) ([]dto.ReminderResponse, error) {
+	records, err := s.reminderRepo.GetAllByUserID(ctx, userId)
+	if err != nil {
+		logging.Error("failed to retrieve reminders", err,
+			zap.String("journey", reminderServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve reminders")
+	}
+
+	res := make([]dto.ReminderResponse, len(records))
+	for i, r := range records {
+		res[i] = newReminderResponse(r)
+	}
+
+	return res, nil
+}
+
+func (s service) CreateReminder(ctx context.Context, userId string, input dto.CreateReminder) (*dto.ReminderResponse, error) {
+	if input.RouteID == "" || input.StopID == "" {
+		return nil, fault.NewBadRequest("route_id and stop_id are required")
+	}
+	if input.WindowStart == nil || input.WindowEnd == nil {
+		return nil, fault.NewBadRequest("window_start and window_end are required")
+	}
+	if *input.WindowEnd < *input.WindowStart {
+		return nil, fault.NewUnprocessableEntity("window_end must not be before window_start")
+	}
+
+	leadMinutes := defaultLeadMinutes
+	if input.LeadMinutes != nil {
+		leadMinutes = *input.LeadMinutes
+	}
+	if leadMinutes < 0 || leadMinutes > maxLeadMinutes {
+		return nil, fault.NewUnprocessableEntity(fmt.Sprintf("lead_minutes must be between 0 and %d", maxLeadMinutes))
+	}
+
+	channel := input.Channel
+	if channel == "" {
+		channel = ChannelEmail
+	}
+	if !slices.Contains(channels, channel) {
+		return nil, fault.NewUnprocessableEntity(fmt.Sprintf("unsupported channel %q", channel))
+	}
+
+	days := make([]string, 0, len(input.Days))
+	for _, d := range input.Days {
+		d = strings.ToLower(d)
+		if !slices.Contains(weekdays, d) {
+			return nil, fault.NewUnprocessableEntity(fmt.Sprintf("invalid day %q", d))
+		}
+		if !slices.Contains(days, d) {
+			days = append(days, d)
+		}
+	}
+
+	r, err := s.routeService.GetRouteByID(ctx, input.RouteID)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+	served := slices.ContainsFunc(r.Stops, func(st dto.RouteStopResponse) bool {
+		return st.Location.ID == input.StopID
+	})
+	if !served {
+		return nil, fault.NewUnprocessableEntity("the route does not serve the stop")
+	}
+
+	records, err := s.reminderRepo.GetAllByUserID(ctx, userId)
+	if err != nil {
+		logging.Error("failed to retrieve reminders", err,
+			zap.String("journey", reminderServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve reminders")
+	}
+	if len(records) >= maxReminders {
+		return nil, fault.NewUnprocessableEntity(fmt.Sprintf("a user may keep at most %d reminders", maxReminders))
+	}
+
+	now := time.Now()
+	reminder := model.Reminder{
+		ID:          uid.New("rem"),
+		UserID:      userId,
+		RouteID:     input.RouteID,
+		LocationID:  input.StopID,
+		Weekdays:    days,
+		WindowStart: *input.WindowStart,
+		WindowEnd:   *input.WindowEnd,
+		LeadMinutes: leadMinutes,
+		Channel:     channel,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	err = s.reminderRepo.Insert(ctx, reminder)
+	if err != nil {
+		logging.Error("failed to insert reminder", err,
+			zap.String("journey", reminderServiceJourney))
+		return nil, fault.NewBadRequest("failed to create reminder")
+	}
+
+	res := newReminderResponse(reminder)
+
+	return &res, nil
+}
+
+func (s service) DeleteReminder(ctx context.Context, userId, reminderId string) error {
+	record, err := s.reminderRepo.GetByID(ctx, reminderId)
+	if err != nil {
+		logging.Error("failed to retrieve reminder", err,
+			zap.String("journey", reminderServiceJourney))
+		return fault.NewBadRequest("failed to retrieve reminder")
+	} else if record == nil || record.UserID != userId {
+		logging.Info("reminder not found",
+			zap.String("journey", reminderServiceJourney),
+			zap.String("reminderID", reminderId))
+		return fault.NewNotFound("reminder not found")
+	}
+
+	err = s.reminderRepo.Delete(ctx, reminderId)
+	if err != nil {
+		logging.Error("failed to delete reminder", err,
+			zap.String("journey", reminderServiceJourney))
+		return fault.NewBadRequest("failed to delete reminder")
+	}
+
+	return nil
+}
+
+func newReminderResponse(r model.Reminder) dto.ReminderResponse {
+	days := r.Weekdays
+	if days == nil {
+		days = make([]string, 0)
+	}
+
+	return dto.ReminderResponse{
+		ID:          r.ID,
+		RouteID:     r.RouteID,
+		StopID:      r.LocationID,
+		Days:        days,
+		WindowStart: r.WindowStart,
+		WindowEnd:   r.WindowEnd,
+		LeadMinutes: r.LeadMinutes,
+		Channel:     r.Channel,
+		CreatedAt:   r.CreatedAt,
+	}
+}
@@ -0,0 +1,47 @@
+package route
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+)
+
+// AlertProvider supplies the service alerts shown alongside routes and departures.
+// It is an interface so the route module does not depend on where alerts come from.
+type AlertProvider interface {
+	GetActiveAlerts(ctx context.Context, at time.Time) ([]dto.AlertResponse, error)
+}
+
+// activeAlerts returns the alerts in effect now. Alerts only complement the
+// timetable, so failing to load them leaves them out instead of failing the query.
+func (s service) activeAlerts(ctx context.Context) []dto.AlertResponse {
+	if s.alertProvider == nil {
+		return nil
+	}
+
+	alerts, err := s.alertProvider.GetActiveAlerts(ctx, time.Now())
+	if err != nil {
+		return nil // The error is already being handled in the alert provider
+	}
+
+	return alerts
+}
+
+// alertsFor returns the alerts affecting any of the routes or stops, including network-wide ones
+func alertsFor(alerts []dto.AlertResponse, routeIds, stopIds []string) []dto.AlertResponse {
+	res := make([]dto.AlertResponse, 0)
+	for _, a := range alerts {
+		networkWide := len(a.RouteIDs) == 0 && len(a.StopIDs) == 0
+		affected := slices.ContainsFunc(a.RouteIDs, func(id string) bool {
+			return slices.Contains(routeIds, id)
+		}) || slices.ContainsFunc(a.StopIDs, func(id string) bool {
+			return slices.Contains(stopIds, id)
+		})
+		if networkWide || affected {
+			res = append(res, a)
+		}
+	}
+	return res
+}
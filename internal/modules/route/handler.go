@@ -0,0 +1,288 @@
+package route
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/role"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geojson"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	routeHandlerJourney = "route handler"
+	// defaultNextDepartures is the number of departures returned when no limit is given
+	defaultNextDepartures = 5
+	// defaultNearbyRadius is the nearby stops search radius, in meters, when none is given
+	defaultNearbyRadius = 500
+	// defaultNearbyStops is the number of nearby stops returned when no limit is given
+	defaultNearbyStops = 10
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	routeService Service
+	secretKey    string
+	sessions     middleware.SessionValidator
+}
+
+func NewHandler(routeService Service, secretKey string, sessions middleware.SessionValidator) *handler {
+	once.Do(func() {
+		instance = &handler{
+			routeService: routeService,
+			secretKey:    secretKey,
+			sessions:     sessions,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
+
+	r.Route("/api/v1/routes", func(r chi.Router) {
+		// Admin
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Put("/{routeId}/shape", h.handleSetRouteShape)
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Delete("/{routeId}/shape", h.handleDeleteRouteShape)
+		// Public
+		r.Get("/", h.handleGetRoutes)
+		r.Get("/names", h.handleGetRouteNames)
+		// Paths of the Java API, kept for its clients
+		r.Get("/all", h.handleGetRoutes)
+		r.Get("/all/names", h.handleGetRouteNames)
+		r.Get("/geojson", h.handleGetNetworkGeoJSON)
+		r.Get("/{routeId}", h.handleGetRoute)
+		r.Get("/{routeId}/geojson", h.handleGetRouteGeoJSON)
+		r.Get("/{routeId}/timetable", h.handleGetRouteTimetable)
+		r.Get("/{routeId}/next-departures", h.handleGetRouteNextDepartures)
+	})
+
+	r.Route("/api/v1/stops", func(r chi.Router) {
+		// Admin
+		r.With(m.WithAuth, m.WithRole(role.Admin)).Put("/{stopId}/coordinates", h.handleSetStopCoordinates)
+		// Public
+		r.Get("/nearby", h.handleGetNearbyStops)
+		r.Get("/{stopId}/next-departures", h.handleGetStopNextDepartures)
+	})
+}
+
+func (h handler) handleGetRoutes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routes, err := h.routeService.GetAllRoutes(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, routes)
+}
+
+func (h handler) handleGetRouteNames(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	names, err := h.routeService.GetRouteNames(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, names)
+}
+
+func (h handler) handleGetRoute(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routeId := chi.URLParam(r, "routeId")
+
+	route, err := h.routeService.GetRouteByID(ctx, routeId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, route)
+}
+
+func (h handler) handleGetRouteTimetable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routeId := chi.URLParam(r, "routeId")
+
+	today := Now().Format(calendar.DateLayout)
+	date, err := calendar.ParseDate(httputil.ReadQueryString(r.URL.Query(), "date", today))
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	res, err := h.routeService.GetRouteTimetable(ctx, routeId, date)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetRouteNextDepartures(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routeId := chi.URLParam(r, "routeId")
+	limit := httputil.ReadQueryInt(r.URL.Query(), "limit", defaultNextDepartures)
+
+	res, err := h.routeService.GetRouteNextDepartures(ctx, routeId, limit)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetStopNextDepartures(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	stopId := chi.URLParam(r, "stopId")
+	limit := httputil.ReadQueryInt(r.URL.Query(), "limit", defaultNextDepartures)
+
+	res, err := h.routeService.GetStopNextDepartures(ctx, stopId, limit)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetNearbyStops(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	qs := r.URL.Query()
+
+	lat, latErr := strconv.ParseFloat(qs.Get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(qs.Get("lon"), 64)
+	if latErr != nil || lonErr != nil {
+		fault.NewHTTPError(w, fault.NewBadRequest("lat and lon must be valid numbers"))
+		return
+	}
+
+	radius, err := strconv.ParseFloat(httputil.ReadQueryString(qs, "radius", strconv.Itoa(defaultNearbyRadius)), 64)
+	if err != nil {
+		fault.NewHTTPError(w, fault.NewBadRequest("radius must be a valid number"))
+		return
+	}
+
+	res, err := h.routeService.GetNearbyStops(ctx, dto.NearbyStopsQuery{
+		Latitude:  lat,
+		Longitude: lon,
+		Radius:    radius,
+		Limit:     httputil.ReadQueryInt(qs, "limit", defaultNearbyStops),
+	})
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleSetStopCoordinates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	stopId := chi.URLParam(r, "stopId")
+
+	var body dto.SetStopCoordinates
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.routeService.SetStopCoordinates(ctx, stopId, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetNetworkGeoJSON(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res, err := h.routeService.GetNetworkGeoJSON(ctx)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	writeGeoJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetRouteGeoJSON(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routeId := chi.URLParam(r, "routeId")
+
+	res, err := h.routeService.GetRouteGeoJSON(ctx, routeId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	writeGeoJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleSetRouteShape(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routeId := chi.URLParam(r, "routeId")
+
+	// The body is any GeoJSON object holding a LineString, parsed by the service
+	var body json.RawMessage
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.routeService.SetRouteShape(ctx, routeId, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	writeGeoJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleDeleteRouteShape(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routeId := chi.URLParam(r, "routeId")
+
+	err := h.routeService.DeleteRouteShape(ctx, routeId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+// writeGeoJSON writes a JSON response with the GeoJSON media type
+func writeGeoJSON(w http.ResponseWriter, code int, dst any) {
+	w.Header().Add("Content-Type", geojson.ContentType)
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(dst)
+}
+
+func logErrorInReadRequestBody(err error, r *http.Request) {
+	logging.Error("failed to read request body", err,
+		zap.String("journey", routeHandlerJourney),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path))
+}
@@ -0,0 +1,51 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geojson"
+)
+
+type Repository interface {
+	GetAll(ctx context.Context) ([]model.Route, error)
+	GetByID(ctx context.Context, routeId string) (*model.Route, error)
+	GetByIDs(ctx context.Context, routeIds []string) ([]model.Route, error)
+	GetNames(ctx context.Context) ([]model.RouteName, error)
+	GetStopsByRouteIDs(ctx context.Context, routeIds []string) ([]model.RouteStop, error)
+	GetDeparturesByRouteIDs(ctx context.Context, routeIds []string) ([]model.DepartureTime, error)
+	GetStopsByLocationID(ctx context.Context, locationId string) ([]model.RouteStop, error)
+	GetLocationByID(ctx context.Context, locationId string) (*model.Location, error)
+	GetLocationsByIDs(ctx context.Context, locationIds []string) ([]model.Location, error)
+	GetLocationsWithCoordinates(ctx context.Context) ([]model.Location, error)
+	UpdateLocationCoordinates(ctx context.Context, locationId string, latitude, longitude float64) error
+	UpdateShape(ctx context.Context, routeId string, shape *string) error
+}
+
+// Writer persists the route network inside a transaction, see dbutil.ExecTx
+type Writer interface {
+	UpsertLocation(ctx context.Context, location model.Location) (string, error)
+	UpsertRoute(ctx context.Context, route model.Route) error
+	ReplaceStops(ctx context.Context, routeId string, stops []model.RouteStop) error
+	ReplaceDepartures(ctx context.Context, routeId string, departures []model.DepartureTime) error
+	DeleteRoutesExcept(ctx context.Context, routeIds []string) (int64, error)
+}
+
+type Service interface {
+	GetAllRoutes(ctx context.Context) ([]dto.RouteResponse, error)
+	GetRouteNames(ctx context.Context) ([]dto.RouteNameResponse, error)
+	GetRouteByID(ctx context.Context, routeId string) (*dto.RouteResponse, error)
+	GetRouteTimetable(ctx context.Context, routeId string, date time.Time) (*dto.RouteTimetableResponse, error)
+	GetRouteNextDepartures(ctx context.Context, routeId string, limit int) (*dto.RouteNextDeparturesResponse, error)
+	GetStopNextDepartures(ctx context.Context, locationId string, limit int) (*dto.StopNextDeparturesResponse, error)
+	GetNearbyStops(ctx context.Context, input dto.NearbyStopsQuery) ([]dto.NearbyStopResponse, error)
+	SetStopCoordinates(ctx context.Context, locationId string, input dto.SetStopCoordinates) (*dto.LocationResponse, error)
+	GetRouteGeoJSON(ctx context.Context, routeId string) (*geojson.FeatureCollection, error)
+	GetNetworkGeoJSON(ctx context.Context) (*geojson.FeatureCollection, error)
+	SetRouteShape(ctx context.Context, routeId string, shape []byte) (*geojson.Feature, error)
+	DeleteRouteShape(ctx context.Context, routeId string) error
+	// InvalidateStopIndex makes the next nearby search reload the stop coordinates
+	InvalidateStopIndex()
+}
@@ -0,0 +1,47 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+)
+
+// recentOccupancyHorizon is how soon a departure must leave for the latest
+// reports to describe it better than the history of its hour
+const recentOccupancyHorizon = time.Hour
+
+// OccupancyProvider supplies the crowd-sourced occupancy of the routes, shown
+// alongside their departures
+type OccupancyProvider interface {
+	GetOccupancyEstimates(ctx context.Context, routeIds []string) (map[string]dto.RouteOccupancyEstimate, error)
+}
+
+// occupancyEstimates returns the estimates of the routes. Like alerts, occupancy
+// only complements the timetable, so failing to load it leaves it out.
+func (s service) occupancyEstimates(ctx context.Context, routeIds []string) map[string]dto.RouteOccupancyEstimate {
+	if s.occupancyProvider == nil {
+		return nil
+	}
+
+	estimates, err := s.occupancyProvider.GetOccupancyEstimates(ctx, routeIds)
+	if err != nil {
+		return nil // The error is already being handled in the occupancy provider
+	}
+
+	return estimates
+}
+
+// occupancyAt picks the estimate for a bus passing at the given time: the recent
+// reports when it is about to pass, otherwise the history of that hour
+func occupancyAt(estimate dto.RouteOccupancyEstimate, at, now time.Time) *dto.OccupancyResponse {
+	if estimate.Recent != nil && at.Sub(now) <= recentOccupancyHorizon {
+		return estimate.Recent
+	}
+
+	o, ok := estimate.Hours[at.In(Timezone()).Hour()]
+	if !ok {
+		return nil
+	}
+	return &o
+}
@@ -0,0 +1,227 @@
+package route
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) GetAll(ctx context.Context) ([]model.Route, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var routes = make([]model.Route, 0)
+	err := r.db.SelectContext(ctx, &routes, "SELECT * FROM routes ORDER BY id")
+	if err != nil {
+		return nil, fault.New("failed to retrieve routes", fault.WithError(err))
+	}
+
+	return routes, nil
+}
+
+func (r repo) GetByID(ctx context.Context, routeId string) (*model.Route, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var route model.Route
+	err := r.db.GetContext(ctx, &route, "SELECT * FROM routes WHERE id = $1 LIMIT 1", routeId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve route", fault.WithError(err))
+	}
+
+	return &route, nil
+}
+
+func (r repo) GetByIDs(ctx context.Context, routeIds []string) ([]model.Route, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var routes = make([]model.Route, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&routes,
+		"SELECT * FROM routes WHERE id = ANY($1) ORDER BY id",
+		pq.Array(routeIds),
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve routes", fault.WithError(err))
+	}
+
+	return routes, nil
+}
+
+func (r repo) GetNames(ctx context.Context) ([]model.RouteName, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var names = make([]model.RouteName, 0)
+	err := r.db.SelectContext(ctx, &names, "SELECT id, name FROM routes ORDER BY id")
+	if err != nil {
+		return nil, fault.New("failed to retrieve route names", fault.WithError(err))
+	}
+
+	return names, nil
+}
+
+func (r repo) GetStopsByRouteIDs(ctx context.Context, routeIds []string) ([]model.RouteStop, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var stops = make([]model.RouteStop, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&stops,
+		"SELECT * FROM route_stops WHERE route_id = ANY($1) ORDER BY route_id, stop_order",
+		pq.Array(routeIds),
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve route stops", fault.WithError(err))
+	}
+
+	return stops, nil
+}
+
+func (r repo) GetDeparturesByRouteIDs(ctx context.Context, routeIds []string) ([]model.DepartureTime, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var departures = make([]model.DepartureTime, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&departures,
+		"SELECT * FROM departure_times WHERE route_id = ANY($1) ORDER BY route_id, departs_at",
+		pq.Array(routeIds),
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve departure times", fault.WithError(err))
+	}
+
+	return departures, nil
+}
+
+func (r repo) GetStopsByLocationID(ctx context.Context, locationId string) ([]model.RouteStop, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var stops = make([]model.RouteStop, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&stops,
+		"SELECT * FROM route_stops WHERE location_id = $1 ORDER BY route_id, stop_order",
+		locationId,
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve route stops by location", fault.WithError(err))
+	}
+
+	return stops, nil
+}
+
+func (r repo) GetLocationByID(ctx context.Context, locationId string) (*model.Location, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var location model.Location
+	err := r.db.GetContext(ctx, &location, "SELECT * FROM locations WHERE id = $1 LIMIT 1", locationId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fault.New("failed to retrieve location", fault.WithError(err))
+	}
+
+	return &location, nil
+}
+
+func (r repo) GetLocationsByIDs(ctx context.Context, locationIds []string) ([]model.Location, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var locations = make([]model.Location, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&locations,
+		"SELECT * FROM locations WHERE id = ANY($1)",
+		pq.Array(locationIds),
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve locations", fault.WithError(err))
+	}
+
+	return locations, nil
+}
+
+func (r repo) GetLocationsWithCoordinates(ctx context.Context) ([]model.Location, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var locations = make([]model.Location, 0)
+	err := r.db.SelectContext(
+		ctx,
+		&locations,
+		"SELECT * FROM locations WHERE latitude IS NOT NULL AND longitude IS NOT NULL",
+	)
+	if err != nil {
+		return nil, fault.New("failed to retrieve located locations", fault.WithError(err))
+	}
+
+	return locations, nil
+}
+
+func (r repo) UpdateLocationCoordinates(ctx context.Context, locationId string, latitude, longitude float64) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE locations
+		SET
+			latitude = $2,
+			longitude = $3,
+			updated_at = $4
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, locationId, latitude, longitude, time.Now())
+	if err != nil {
+		return fault.New("failed to update location coordinates", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) UpdateShape(ctx context.Context, routeId string, shape *string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE routes
+		SET
+			shape = $2,
+			updated_at = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, routeId, shape, time.Now())
+	if err != nil {
+		return fault.New("failed to update route shape", fault.WithError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package route
+
+import (
+	"time"
+	_ "time/tzdata" // The production image is built from scratch and ships without zoneinfo
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+)
+
+const (
+	// timezone is the timezone the official timetable is written in
+	timezone = "America/Sao_Paulo"
+	// lookaheadDays is how many days are scanned when looking for upcoming departures
+	lookaheadDays = 7
+)
+
+var tz = mustLoadTimezone()
+
+// Timezone returns the timezone of the BUZUFBA timetable
+func Timezone() *time.Location {
+	return tz
+}
+
+// Now returns the current time in the timetable timezone
+func Now() time.Time {
+	return time.Now().In(tz)
+}
+
+// scheduledDeparture is a departure bound to an actual day
+type scheduledDeparture struct {
+	departure model.DepartureTime
+	departsAt time.Time
+}
+
+// upcomingDepartures walks the days starting at the day of `from` and returns,
+// in chronological order, up to limit departures whose calendar runs on the day
+// and for which keep returns true. The departures must be sorted by time of day.
+func upcomingDepartures(
+	from time.Time,
+	departures []model.DepartureTime,
+	days calendar.ServiceDays,
+	limit int,
+	keep func(departsAt time.Time) bool,
+) []scheduledDeparture {
+	res := make([]scheduledDeparture, 0, limit)
+	if len(departures) == 0 {
+		return res
+	}
+
+	day := from.In(tz)
+	for i := 0; i < lookaheadDays && len(res) < limit; i++ {
+		for _, d := range departures {
+			if !days.Runs(d.CalendarID, day) {
+				continue
+			}
+
+			departsAt := d.DepartsAt.On(day)
+			if !keep(departsAt) {
+				continue
+			}
+
+			res = append(res, scheduledDeparture{departure: d, departsAt: departsAt})
+			if len(res) == limit {
+				break
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return res
+}
+
+// StopOffset estimates how long after the departure the bus reaches a stop,
+// spreading the trip length evenly along the stop order
+func StopOffset(tripLength float64, stopOrder, lastOrder int) time.Duration {
+	if lastOrder <= 0 {
+		return 0
+	}
+
+	minutes := tripLength * float64(stopOrder) / float64(lastOrder)
+	return time.Duration(minutes * float64(time.Minute)).Round(time.Minute)
+}
+
+func lastStopOrder(stops []model.RouteStop) int {
+	last := 0
+	for _, st := range stops {
+		if st.StopOrder > last {
+			last = st.StopOrder
+		}
+	}
+	return last
+}
+
+func mustLoadTimezone() *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
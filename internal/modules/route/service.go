@@ -0,0 +1,559 @@
+package route
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geo"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	routeServiceJourney = "route service"
+	// maxNextDepartures caps how many departures a single query may return
+	maxNextDepartures = 50
+	// maxNearbyRadius caps the radius of a nearby stops search, in meters
+	maxNearbyRadius = 5000
+)
+
+type ServiceConfig struct {
+	RouteRepo       Repository
+	CalendarService calendar.Service
+	// AlertProvider is optional, routes and departures come without alerts when nil
+	AlertProvider AlertProvider
+	// OccupancyProvider is optional, departures come without occupancy when nil
+	OccupancyProvider OccupancyProvider
+}
+
+type service struct {
+	routeRepo         Repository
+	calendarService   calendar.Service
+	alertProvider     AlertProvider
+	occupancyProvider OccupancyProvider
+	stops             *stopIndex
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		routeRepo:         c.RouteRepo,
+		calendarService:   c.CalendarService,
+		alertProvider:     c.AlertProvider,
+		occupancyProvider: c.OccupancyProvider,
+		stops:             &stopIndex{},
+	}
+}
+
+func (s service) GetAllRoutes(ctx context.Context) ([]dto.RouteResponse, error) {
+	records, err := s.routeRepo.GetAll(ctx)
+	if err != nil {
+		logging.Error("failed to retrieve routes", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve routes")
+	}
+
+	if len(records) == 0 {
+		return make([]dto.RouteResponse, 0), nil
+	}
+
+	return s.buildRoutes(ctx, records)
+}
+
+func (s service) GetRouteNames(ctx context.Context) ([]dto.RouteNameResponse, error) {
+	records, err := s.routeRepo.GetNames(ctx)
+	if err != nil {
+		logging.Error("failed to retrieve route names", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve route names")
+	}
+
+	names := make([]dto.RouteNameResponse, len(records))
+	for i, r := range records {
+		names[i] = dto.RouteNameResponse{
+			ID:   r.ID,
+			Name: r.Name,
+		}
+	}
+
+	return names, nil
+}
+
+func (s service) GetRouteByID(ctx context.Context, routeId string) (*dto.RouteResponse, error) {
+	record, err := s.routeRepo.GetByID(ctx, routeId)
+	if err != nil {
+		logging.Error("failed to retrieve route", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve route")
+	} else if record == nil {
+		logging.Info("route not found",
+			zap.String("journey", routeServiceJourney),
+			zap.String("routeID", routeId))
+		return nil, fault.NewNotFound("route not found")
+	}
+
+	routes, err := s.buildRoutes(ctx, []model.Route{*record})
+	if err != nil {
+		return nil, err // The error is already being handled in buildRoutes
+	}
+
+	return &routes[0], nil
+}
+
+func (s service) GetRouteTimetable(ctx context.Context, routeId string, date time.Time) (*dto.RouteTimetableResponse, error) {
+	record, err := s.routeRepo.GetByID(ctx, routeId)
+	if err != nil {
+		logging.Error("failed to retrieve route", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve route")
+	} else if record == nil {
+		logging.Info("route not found",
+			zap.String("journey", routeServiceJourney),
+			zap.String("routeID", routeId))
+		return nil, fault.NewNotFound("route not found")
+	}
+
+	departures, err := s.routeRepo.GetDeparturesByRouteIDs(ctx, []string{routeId})
+	if err != nil {
+		logging.Error("failed to retrieve departure times", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve departure times")
+	}
+
+	days, err := s.calendarService.GetServiceDays(ctx, date, 1)
+	if err != nil {
+		return nil, err // The error is already being handled in the calendar service
+	}
+
+	res := &dto.RouteTimetableResponse{
+		RouteID:    record.ID,
+		RouteName:  record.Name,
+		Date:       date.Format(calendar.DateLayout),
+		Departures: make([]dto.DepartureTimeResponse, 0, len(departures)),
+	}
+	for _, d := range departures {
+		if days.Runs(d.CalendarID, date) {
+			res.Departures = append(res.Departures, newDepartureResponse(d))
+		}
+	}
+
+	return res, nil
+}
+
+func (s service) GetRouteNextDepartures(ctx context.Context, routeId string, limit int) (*dto.RouteNextDeparturesResponse, error) {
+	limit = clampLimit(limit)
+
+	record, err := s.routeRepo.GetByID(ctx, routeId)
+	if err != nil {
+		logging.Error("failed to retrieve route", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve route")
+	} else if record == nil {
+		logging.Info("route not found",
+			zap.String("journey", routeServiceJourney),
+			zap.String("routeID", routeId))
+		return nil, fault.NewNotFound("route not found")
+	}
+
+	stops, err := s.routeRepo.GetStopsByRouteIDs(ctx, []string{routeId})
+	if err != nil {
+		logging.Error("failed to retrieve route stops", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve route stops")
+	}
+
+	departures, err := s.routeRepo.GetDeparturesByRouteIDs(ctx, []string{routeId})
+	if err != nil {
+		logging.Error("failed to retrieve departure times", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve departure times")
+	}
+
+	locationIds := make([]string, len(stops))
+	for i, st := range stops {
+		locationIds[i] = st.LocationID
+	}
+
+	locations, err := s.routeRepo.GetLocationsByIDs(ctx, locationIds)
+	if err != nil {
+		logging.Error("failed to retrieve locations", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve locations")
+	}
+
+	names := make(map[string]string, len(locations))
+	for _, l := range locations {
+		names[l.ID] = l.Name
+	}
+
+	now := Now()
+	days, err := s.calendarService.GetServiceDays(ctx, now, lookaheadDays)
+	if err != nil {
+		return nil, err // The error is already being handled in the calendar service
+	}
+
+	lastOrder := lastStopOrder(stops)
+	upcoming := upcomingDepartures(now, departures, days, limit, func(departsAt time.Time) bool {
+		return !departsAt.Before(now)
+	})
+
+	res := &dto.RouteNextDeparturesResponse{
+		RouteID:    record.ID,
+		RouteName:  record.Name,
+		Departures: make([]dto.RouteNextDepartureResponse, len(upcoming)),
+		Alerts:     alertsFor(s.activeAlerts(ctx), []string{record.ID}, locationIds),
+	}
+	occupancy := s.occupancyEstimates(ctx, []string{record.ID})
+	for i, u := range upcoming {
+		arrivals := make([]dto.StopArrivalResponse, len(stops))
+		for j, st := range stops {
+			arrivals[j] = dto.StopArrivalResponse{
+				LocationID:       st.LocationID,
+				Name:             names[st.LocationID],
+				StopOrder:        st.StopOrder,
+				EstimatedArrival: u.departsAt.Add(StopOffset(record.TripLength, st.StopOrder, lastOrder)),
+			}
+		}
+
+		res.Departures[i] = dto.RouteNextDepartureResponse{
+			DepartureID: u.departure.ID,
+			DepartsAt:   u.departsAt,
+			Stops:       arrivals,
+			Occupancy:   occupancyAt(occupancy[record.ID], u.departsAt, now),
+		}
+	}
+
+	return res, nil
+}
+
+func (s service) GetStopNextDepartures(ctx context.Context, locationId string, limit int) (*dto.StopNextDeparturesResponse, error) {
+	limit = clampLimit(limit)
+
+	location, err := s.routeRepo.GetLocationByID(ctx, locationId)
+	if err != nil {
+		logging.Error("failed to retrieve location", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve stop")
+	} else if location == nil {
+		logging.Info("stop not found",
+			zap.String("journey", routeServiceJourney),
+			zap.String("locationID", locationId))
+		return nil, fault.NewNotFound("stop not found")
+	}
+
+	stopsAtLocation, err := s.routeRepo.GetStopsByLocationID(ctx, locationId)
+	if err != nil {
+		logging.Error("failed to retrieve route stops", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve route stops")
+	}
+
+	routeIds := make([]string, 0, len(stopsAtLocation))
+	for _, st := range stopsAtLocation {
+		if !slices.Contains(routeIds, st.RouteID) {
+			routeIds = append(routeIds, st.RouteID)
+		}
+	}
+
+	res := &dto.StopNextDeparturesResponse{
+		Stop:       newLocationResponse(*location),
+		Departures: make([]dto.StopNextDepartureResponse, 0, limit),
+		Alerts:     alertsFor(s.activeAlerts(ctx), routeIds, []string{locationId}),
+	}
+	if len(stopsAtLocation) == 0 {
+		return res, nil
+	}
+
+	routeRecords, err := s.routeRepo.GetByIDs(ctx, routeIds)
+	if err != nil {
+		logging.Error("failed to retrieve routes", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve routes")
+	}
+
+	routes := make(map[string]model.Route, len(routeRecords))
+	for _, r := range routeRecords {
+		routes[r.ID] = r
+	}
+
+	allStops, err := s.routeRepo.GetStopsByRouteIDs(ctx, routeIds)
+	if err != nil {
+		logging.Error("failed to retrieve route stops", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve route stops")
+	}
+
+	lastOrders := make(map[string]int, len(routeIds))
+	for _, st := range allStops {
+		if st.StopOrder > lastOrders[st.RouteID] {
+			lastOrders[st.RouteID] = st.StopOrder
+		}
+	}
+
+	departures, err := s.routeRepo.GetDeparturesByRouteIDs(ctx, routeIds)
+	if err != nil {
+		logging.Error("failed to retrieve departure times", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve departure times")
+	}
+
+	departuresByRoute := make(map[string][]model.DepartureTime, len(routeIds))
+	for _, d := range departures {
+		departuresByRoute[d.RouteID] = append(departuresByRoute[d.RouteID], d)
+	}
+
+	now := Now()
+	days, err := s.calendarService.GetServiceDays(ctx, now, lookaheadDays)
+	if err != nil {
+		return nil, err // The error is already being handled in the calendar service
+	}
+
+	occupancy := s.occupancyEstimates(ctx, routeIds)
+	for _, st := range stopsAtLocation {
+		route, ok := routes[st.RouteID]
+		if !ok {
+			continue
+		}
+
+		offset := StopOffset(route.TripLength, st.StopOrder, lastOrders[st.RouteID])
+		upcoming := upcomingDepartures(now, departuresByRoute[st.RouteID], days, limit, func(departsAt time.Time) bool {
+			return !departsAt.Add(offset).Before(now)
+		})
+
+		for _, u := range upcoming {
+			res.Departures = append(res.Departures, dto.StopNextDepartureResponse{
+				RouteID:          route.ID,
+				RouteName:        route.Name,
+				DepartureID:      u.departure.ID,
+				StopOrder:        st.StopOrder,
+				DepartsAt:        u.departsAt,
+				EstimatedArrival: u.departsAt.Add(offset),
+				Occupancy:        occupancyAt(occupancy[route.ID], u.departsAt.Add(offset), now),
+			})
+		}
+	}
+
+	slices.SortFunc(res.Departures, func(a, b dto.StopNextDepartureResponse) int {
+		return a.EstimatedArrival.Compare(b.EstimatedArrival)
+	})
+	if len(res.Departures) > limit {
+		res.Departures = res.Departures[:limit]
+	}
+
+	return res, nil
+}
+
+// buildRoutes loads the stops, departures and locations of the given routes
+// and assembles them into responses, keeping the order of the records
+func (s service) buildRoutes(ctx context.Context, records []model.Route) ([]dto.RouteResponse, error) {
+	routeIds := make([]string, len(records))
+	for i, r := range records {
+		routeIds[i] = r.ID
+	}
+
+	stops, err := s.routeRepo.GetStopsByRouteIDs(ctx, routeIds)
+	if err != nil {
+		logging.Error("failed to retrieve route stops", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve route stops")
+	}
+
+	departures, err := s.routeRepo.GetDeparturesByRouteIDs(ctx, routeIds)
+	if err != nil {
+		logging.Error("failed to retrieve departure times", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve departure times")
+	}
+
+	locationIds := make([]string, 0, len(stops)+len(records)*2)
+	for _, st := range stops {
+		locationIds = append(locationIds, st.LocationID)
+	}
+	for _, r := range records {
+		if r.DepartureLocationID != nil {
+			locationIds = append(locationIds, *r.DepartureLocationID)
+		}
+		if r.ArrivalLocationID != nil {
+			locationIds = append(locationIds, *r.ArrivalLocationID)
+		}
+	}
+
+	locations, err := s.routeRepo.GetLocationsByIDs(ctx, locationIds)
+	if err != nil {
+		logging.Error("failed to retrieve locations", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve locations")
+	}
+
+	locationsByID := make(map[string]dto.LocationResponse, len(locations))
+	for _, l := range locations {
+		locationsByID[l.ID] = newLocationResponse(l)
+	}
+
+	stopsByRoute := make(map[string][]dto.RouteStopResponse, len(records))
+	for _, st := range stops {
+		stopsByRoute[st.RouteID] = append(stopsByRoute[st.RouteID], dto.RouteStopResponse{
+			ID:          st.ID,
+			Location:    locationsByID[st.LocationID],
+			StopOrder:   st.StopOrder,
+			IsDeparture: st.IsDeparture,
+			IsArrival:   st.IsArrival,
+		})
+	}
+
+	departuresByRoute := make(map[string][]dto.DepartureTimeResponse, len(records))
+	for _, d := range departures {
+		departuresByRoute[d.RouteID] = append(departuresByRoute[d.RouteID], newDepartureResponse(d))
+	}
+
+	alerts := s.activeAlerts(ctx)
+	routes := make([]dto.RouteResponse, len(records))
+	for i, r := range records {
+		stopIds := make([]string, len(stopsByRoute[r.ID]))
+		for j, st := range stopsByRoute[r.ID] {
+			stopIds[j] = st.Location.ID
+		}
+
+		routes[i] = dto.RouteResponse{
+			ID:                r.ID,
+			Name:              r.Name,
+			TripLength:        r.TripLength,
+			DepartureLocation: lookupLocation(locationsByID, r.DepartureLocationID),
+			ArrivalLocation:   lookupLocation(locationsByID, r.ArrivalLocationID),
+			Notes:             r.Notes,
+			Shape:             r.Shape,
+			Stops:             stopsByRoute[r.ID],
+			Departures:        departuresByRoute[r.ID],
+			Alerts:            alertsFor(alerts, []string{r.ID}, stopIds),
+		}
+
+		// Always return arrays, even for routes without children
+		if routes[i].Notes == nil {
+			routes[i].Notes = make([]string, 0)
+		}
+		if routes[i].Stops == nil {
+			routes[i].Stops = make([]dto.RouteStopResponse, 0)
+		}
+		if routes[i].Departures == nil {
+			routes[i].Departures = make([]dto.DepartureTimeResponse, 0)
+		}
+	}
+
+	return routes, nil
+}
+
+func newLocationResponse(l model.Location) dto.LocationResponse {
+	return dto.LocationResponse{
+		ID:        l.ID,
+		Name:      l.Name,
+		Latitude:  l.Latitude,
+		Longitude: l.Longitude,
+	}
+}
+
+func newDepartureResponse(d model.DepartureTime) dto.DepartureTimeResponse {
+	return dto.DepartureTimeResponse{
+		ID:         d.ID,
+		CalendarID: d.CalendarID,
+		Time:       d.DepartsAt,
+	}
+}
+
+func lookupLocation(locations map[string]dto.LocationResponse, id *string) *dto.LocationResponse {
+	if id == nil {
+		return nil
+	}
+
+	l, ok := locations[*id]
+	if !ok {
+		return nil
+	}
+
+	return &l
+}
+
+func (s service) GetNearbyStops(ctx context.Context, input dto.NearbyStopsQuery) ([]dto.NearbyStopResponse, error) {
+	point := geo.Point{Lat: input.Latitude, Lon: input.Longitude}
+	if !point.Valid() {
+		return nil, fault.NewBadRequest("invalid coordinates")
+	}
+	if input.Radius <= 0 {
+		return nil, fault.NewBadRequest("radius must be positive")
+	}
+	radius := min(input.Radius, maxNearbyRadius)
+	limit := clampLimit(input.Limit)
+
+	index, locations, err := s.stops.get(ctx, s.routeRepo)
+	if err != nil {
+		logging.Error("failed to load stop coordinates", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve nearby stops")
+	}
+
+	matches := index.Nearby(point, radius)
+	res := make([]dto.NearbyStopResponse, 0, min(len(matches), limit))
+	for _, m := range matches[:min(len(matches), limit)] {
+		res = append(res, dto.NearbyStopResponse{
+			Stop:                  newLocationResponse(locations[m.ID]),
+			DistanceMeters:        int(math.Round(m.Distance)),
+			WalkingDistanceMeters: int(math.Round(geo.WalkingDistance(m.Distance))),
+			WalkingMinutes:        int(geo.WalkingDuration(m.Distance) / time.Minute),
+		})
+	}
+
+	return res, nil
+}
+
+func (s service) SetStopCoordinates(ctx context.Context, locationId string, input dto.SetStopCoordinates) (*dto.LocationResponse, error) {
+	if input.Latitude == nil || input.Longitude == nil {
+		return nil, fault.NewBadRequest("latitude and longitude are required")
+	}
+	if !(geo.Point{Lat: *input.Latitude, Lon: *input.Longitude}).Valid() {
+		return nil, fault.NewBadRequest("invalid coordinates")
+	}
+
+	location, err := s.routeRepo.GetLocationByID(ctx, locationId)
+	if err != nil {
+		logging.Error("failed to retrieve location", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve stop")
+	} else if location == nil {
+		logging.Info("stop not found",
+			zap.String("journey", routeServiceJourney),
+			zap.String("locationID", locationId))
+		return nil, fault.NewNotFound("stop not found")
+	}
+
+	err = s.routeRepo.UpdateLocationCoordinates(ctx, locationId, *input.Latitude, *input.Longitude)
+	if err != nil {
+		logging.Error("failed to update stop coordinates", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to update stop coordinates")
+	}
+	s.stops.invalidate()
+
+	location.Latitude = input.Latitude
+	location.Longitude = input.Longitude
+	res := newLocationResponse(*location)
+
+	return &res, nil
+}
+
+func (s service) InvalidateStopIndex() {
+	s.stops.invalidate()
+}
+
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return 1
+	}
+	if limit > maxNextDepartures {
+		return maxNextDepartures
+	}
+	return limit
+}
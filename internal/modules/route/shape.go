@@ -0,0 +1,188 @@
+package route
+
+import (
+	"context"
+	"slices"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geo"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geojson"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/polyline"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxShapePoints caps the size of an uploaded route shape
+	maxShapePoints = 10_000
+
+	// Where the line of a route comes from
+	lineSourceShape = "shape"
+	lineSourceStops = "stops"
+)
+
+func (s service) GetRouteGeoJSON(ctx context.Context, routeId string) (*geojson.FeatureCollection, error) {
+	r, err := s.GetRouteByID(ctx, routeId)
+	if err != nil {
+		return nil, err // The error is already being handled in GetRouteByID
+	}
+
+	features := make([]geojson.Feature, 0, len(r.Stops)+1)
+	if line, ok := routeLine(*r); ok {
+		features = append(features, line)
+	}
+	for _, st := range r.Stops {
+		if f, ok := stopFeature(st.Location, []string{r.ID}); ok {
+			f.Properties["stop_order"] = st.StopOrder
+			features = append(features, f)
+		}
+	}
+
+	return geojson.NewFeatureCollection(features), nil
+}
+
+func (s service) GetNetworkGeoJSON(ctx context.Context) (*geojson.FeatureCollection, error) {
+	routes, err := s.GetAllRoutes(ctx)
+	if err != nil {
+		return nil, err // The error is already being handled in GetAllRoutes
+	}
+
+	features := make([]geojson.Feature, 0, len(routes))
+	locations := make(map[string]dto.LocationResponse)
+	routesAt := make(map[string][]string)
+	for _, r := range routes {
+		if line, ok := routeLine(r); ok {
+			features = append(features, line)
+		}
+		for _, st := range r.Stops {
+			locations[st.Location.ID] = st.Location
+			if !slices.Contains(routesAt[st.Location.ID], r.ID) {
+				routesAt[st.Location.ID] = append(routesAt[st.Location.ID], r.ID)
+			}
+		}
+	}
+
+	// Stops go after the lines, sorted so the document is stable
+	locationIds := make([]string, 0, len(locations))
+	for id := range locations {
+		locationIds = append(locationIds, id)
+	}
+	slices.Sort(locationIds)
+	for _, id := range locationIds {
+		if f, ok := stopFeature(locations[id], routesAt[id]); ok {
+			features = append(features, f)
+		}
+	}
+
+	return geojson.NewFeatureCollection(features), nil
+}
+
+func (s service) SetRouteShape(ctx context.Context, routeId string, shape []byte) (*geojson.Feature, error) {
+	points, err := geojson.ParseLineString(shape)
+	if err != nil {
+		return nil, fault.NewBadRequest(err.Error())
+	}
+	if len(points) < 2 {
+		return nil, fault.NewUnprocessableEntity("shape must have at least two points")
+	}
+	if len(points) > maxShapePoints {
+		return nil, fault.NewUnprocessableEntity("shape has too many points")
+	}
+
+	r, err := s.GetRouteByID(ctx, routeId)
+	if err != nil {
+		return nil, err // The error is already being handled in GetRouteByID
+	}
+
+	encoded := polyline.Encode(points)
+	err = s.routeRepo.UpdateShape(ctx, routeId, &encoded)
+	if err != nil {
+		logging.Error("failed to update route shape", err,
+			zap.String("journey", routeServiceJourney))
+		return nil, fault.NewBadRequest("failed to update route shape")
+	}
+
+	r.Shape = &encoded
+	line, _ := routeLine(*r)
+
+	return &line, nil
+}
+
+func (s service) DeleteRouteShape(ctx context.Context, routeId string) error {
+	record, err := s.routeRepo.GetByID(ctx, routeId)
+	if err != nil {
+		logging.Error("failed to retrieve route", err,
+			zap.String("journey", routeServiceJourney))
+		return fault.NewBadRequest("failed to retrieve route")
+	} else if record == nil {
+		logging.Info("route not found",
+			zap.String("journey", routeServiceJourney),
+			zap.String("routeID", routeId))
+		return fault.NewNotFound("route not found")
+	}
+
+	err = s.routeRepo.UpdateShape(ctx, routeId, nil)
+	if err != nil {
+		logging.Error("failed to delete route shape", err,
+			zap.String("journey", routeServiceJourney))
+		return fault.NewBadRequest("failed to delete route shape")
+	}
+
+	return nil
+}
+
+// routeLine returns the LineString of the route, drawn from its shape or,
+// when it has none, through its located stops. It is false when neither has two points.
+func routeLine(r dto.RouteResponse) (geojson.Feature, bool) {
+	points, source := shapePoints(r), lineSourceShape
+	if points == nil {
+		points, source = make([]geo.Point, 0, len(r.Stops)), lineSourceStops
+		for _, st := range r.Stops {
+			if st.Location.Latitude != nil && st.Location.Longitude != nil {
+				points = append(points, geo.Point{Lat: *st.Location.Latitude, Lon: *st.Location.Longitude})
+			}
+		}
+	}
+	if len(points) < 2 {
+		return geojson.Feature{}, false
+	}
+
+	return geojson.NewFeature("route:"+r.ID, geojson.NewLineString(points), map[string]any{
+		"kind":       "route",
+		"route_id":   r.ID,
+		"route_name": r.Name,
+		"source":     source,
+	}), true
+}
+
+// shapePoints decodes the shape of the route, nil when it has none or it is corrupted
+func shapePoints(r dto.RouteResponse) []geo.Point {
+	if r.Shape == nil {
+		return nil
+	}
+
+	points, err := polyline.Decode(*r.Shape)
+	if err != nil {
+		logging.Error("failed to decode route shape", err,
+			zap.String("journey", routeServiceJourney),
+			zap.String("routeID", r.ID))
+		return nil
+	}
+
+	return points
+}
+
+// stopFeature returns the Point of the stop, false when it has no coordinates
+func stopFeature(l dto.LocationResponse, routeIds []string) (geojson.Feature, bool) {
+	if l.Latitude == nil || l.Longitude == nil {
+		return geojson.Feature{}, false
+	}
+
+	return geojson.NewFeature("stop:"+l.ID, geojson.NewPoint(geo.Point{Lat: *l.Latitude, Lon: *l.Longitude}), map[string]any{
+		"kind":      "stop",
+		"stop_id":   l.ID,
+		"name":      l.Name,
+		"route_ids": routeIds,
+	}), true
+}
@@ -0,0 +1,68 @@
+package route
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geo"
+)
+
+// stopIndexMaxAge bounds how long coordinates written by other processes,
+// e.g. the seed command, take to reach the nearby search
+const stopIndexMaxAge = time.Minute * 10
+
+// stopIndex keeps the located stops in memory for nearby searches.
+// It is rebuilt lazily after being invalidated or once it gets too old.
+type stopIndex struct {
+	mu        sync.RWMutex
+	index     *geo.Index
+	locations map[string]model.Location
+	builtAt   time.Time
+}
+
+func (si *stopIndex) get(ctx context.Context, repo Repository) (*geo.Index, map[string]model.Location, error) {
+	si.mu.RLock()
+	if si.fresh() {
+		defer si.mu.RUnlock()
+		return si.index, si.locations, nil
+	}
+	si.mu.RUnlock()
+
+	si.mu.Lock()
+	defer si.mu.Unlock()
+
+	// Another request may have rebuilt the index while waiting for the lock
+	if si.fresh() {
+		return si.index, si.locations, nil
+	}
+
+	records, err := repo.GetLocationsWithCoordinates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]geo.Entry, len(records))
+	locations := make(map[string]model.Location, len(records))
+	for i, l := range records {
+		entries[i] = geo.Entry{ID: l.ID, Point: geo.Point{Lat: *l.Latitude, Lon: *l.Longitude}}
+		locations[l.ID] = l
+	}
+
+	si.index = geo.NewIndex(entries)
+	si.locations = locations
+	si.builtAt = time.Now()
+
+	return si.index, si.locations, nil
+}
+
+func (si *stopIndex) invalidate() {
+	si.mu.Lock()
+	defer si.mu.Unlock()
+	si.index = nil
+}
+
+func (si *stopIndex) fresh() bool {
+	return si.index != nil && time.Since(si.builtAt) < stopIndexMaxAge
+}
@@ -0,0 +1,231 @@
+package route
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type writer struct {
+	tx *sqlx.Tx
+}
+
+func NewWriter(tx *sqlx.Tx) Writer {
+	return &writer{tx: tx}
+}
+
+// UpsertLocation inserts the location or refreshes the existing one with the same name,
+// returning the ID of the stored row. Coordinates are only overwritten when provided.
+func (w writer) UpsertLocation(ctx context.Context, location model.Location) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO locations (
+			id,
+			name,
+			latitude,
+			longitude,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:name,
+			:latitude,
+			:longitude,
+			:created_at,
+			:updated_at
+		)
+		ON CONFLICT (name) DO UPDATE
+		SET
+			latitude = COALESCE(EXCLUDED.latitude, locations.latitude),
+			longitude = COALESCE(EXCLUDED.longitude, locations.longitude),
+			updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+
+	query, args, err := w.tx.BindNamed(query, location)
+	if err != nil {
+		return "", fault.New("failed to bind location", fault.WithError(err))
+	}
+
+	var id string
+	err = w.tx.GetContext(ctx, &id, query, args...)
+	if err != nil {
+		return "", fault.New("failed to upsert location", fault.WithError(err))
+	}
+
+	return id, nil
+}
+
+func (w writer) UpsertRoute(ctx context.Context, route model.Route) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO routes (
+			id,
+			name,
+			trip_length,
+			departure_location_id,
+			arrival_location_id,
+			notes,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:name,
+			:trip_length,
+			:departure_location_id,
+			:arrival_location_id,
+			:notes,
+			:created_at,
+			:updated_at
+		)
+		ON CONFLICT (id) DO UPDATE
+		SET
+			name = EXCLUDED.name,
+			trip_length = EXCLUDED.trip_length,
+			departure_location_id = EXCLUDED.departure_location_id,
+			arrival_location_id = EXCLUDED.arrival_location_id,
+			notes = EXCLUDED.notes,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := w.tx.NamedExecContext(ctx, query, route)
+	if err != nil {
+		return fault.New("failed to upsert route", fault.WithError(err))
+	}
+
+	return nil
+}
+
+// ReplaceStops upserts the stops of a route by stop order and removes
+// the stops that are no longer part of it
+func (w writer) ReplaceStops(ctx context.Context, routeId string, stops []model.RouteStop) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO route_stops (
+			id,
+			route_id,
+			location_id,
+			stop_order,
+			is_departure,
+			is_arrival,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:route_id,
+			:location_id,
+			:stop_order,
+			:is_departure,
+			:is_arrival,
+			:created_at,
+			:updated_at
+		)
+		ON CONFLICT (route_id, stop_order) DO UPDATE
+		SET
+			location_id = EXCLUDED.location_id,
+			is_departure = EXCLUDED.is_departure,
+			is_arrival = EXCLUDED.is_arrival,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	for _, st := range stops {
+		_, err := w.tx.NamedExecContext(ctx, query, st)
+		if err != nil {
+			return fault.New("failed to upsert route stop", fault.WithError(err))
+		}
+	}
+
+	_, err := w.tx.ExecContext(
+		ctx,
+		"DELETE FROM route_stops WHERE route_id = $1 AND stop_order >= $2",
+		routeId,
+		len(stops),
+	)
+	if err != nil {
+		return fault.New("failed to delete stale route stops", fault.WithError(err))
+	}
+
+	return nil
+}
+
+// ReplaceDepartures inserts the missing departures of a route and removes
+// the ones that are no longer part of it. Existing departures keep their IDs.
+func (w writer) ReplaceDepartures(ctx context.Context, routeId string, departures []model.DepartureTime) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO departure_times (
+			id,
+			route_id,
+			calendar_id,
+			departs_at,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:route_id,
+			:calendar_id,
+			:departs_at,
+			:created_at,
+			:updated_at
+		)
+		ON CONFLICT (route_id, calendar_id, departs_at) DO NOTHING
+	`
+
+	calendars := make([]string, len(departures))
+	times := make([]string, len(departures))
+	for i, d := range departures {
+		_, err := w.tx.NamedExecContext(ctx, query, d)
+		if err != nil {
+			return fault.New("failed to insert departure time", fault.WithError(err))
+		}
+		calendars[i] = d.CalendarID
+		times[i] = d.DepartsAt.String()
+	}
+
+	var deleteQuery = `
+		DELETE FROM departure_times d
+		WHERE d.route_id = $1
+		AND NOT EXISTS (
+			SELECT 1 FROM unnest($2::text[], $3::time[]) AS k(calendar_id, departs_at)
+			WHERE k.calendar_id = d.calendar_id AND k.departs_at = d.departs_at
+		)
+	`
+
+	_, err := w.tx.ExecContext(ctx, deleteQuery, routeId, pq.Array(calendars), pq.Array(times))
+	if err != nil {
+		return fault.New("failed to delete stale departure times", fault.WithError(err))
+	}
+
+	return nil
+}
+
+// DeleteRoutesExcept removes every route whose ID is not in routeIds, along with
+// their stops and departures, and returns how many routes were removed
+func (w writer) DeleteRoutesExcept(ctx context.Context, routeIds []string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	res, err := w.tx.ExecContext(ctx, "DELETE FROM routes WHERE id <> ALL($1)", pq.Array(routeIds))
+	if err != nil {
+		return 0, fault.New("failed to delete stale routes", fault.WithError(err))
+	}
+
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return 0, fault.New("failed to count deleted routes", fault.WithError(err))
+	}
+
+	return removed, nil
+}
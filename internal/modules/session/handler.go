@@ -38,12 +38,14 @@ func NewHandler(sessionService Service, secretKey string) *handler {
 }
 
 func (h handler) Register(r *chi.Mux) {
-	m := middleware.NewWithAuth(h.secretKey)
+	m := middleware.NewWithAuth(h.secretKey, h.sessionService)
 
 	r.Route("/api/v1/sessions", func(r chi.Router) {
 		// Private
 		r.With(m.WithAuth).Get("/", h.handleGetSessions)
 		r.With(m.WithAuth).Get("/me", h.handleGetSignedSession)
+		r.With(m.WithAuth).Delete("/", h.handleRevokeSessions)
+		r.With(m.WithAuth).Delete("/{sessionId}", h.handleRevokeSession)
 		// Public
 		r.Post("/refresh", h.handleRenewToken)
 	})
@@ -62,7 +64,7 @@ func (h handler) handleGetSignedSession(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	res, err := h.sessionService.GetSessionByUserID(ctx, c.UserID)
+	res, err := h.sessionService.GetSessionByID(ctx, c.SessionID)
 	if err != nil {
 		fault.NewHTTPError(w, err)
 		return
@@ -97,6 +99,51 @@ func (h handler) handleRenewToken(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(w, http.StatusOK, res)
 }
 
+func (h handler) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+	sessionId := chi.URLParam(r, "sessionId")
+
+	err := h.sessionService.RevokeSession(ctx, c.UserID, sessionId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
+// handleRevokeSessions ends every session of the user, but the one making the
+// request with ?except=current
+func (h handler) handleRevokeSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	c, ok := claimsFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	var except string
+	switch r.URL.Query().Get("except") {
+	case "":
+	case "current":
+		except = c.SessionID
+	default:
+		fault.NewHTTPError(w, fault.NewBadRequest("except must be current"))
+		return
+	}
+
+	err := h.sessionService.RevokeSessions(ctx, c.UserID, except)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteSuccess(w, http.StatusOK)
+}
+
 func (h handler) handleGetSessions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	sessions, err := h.sessionService.GetAllSessions(ctx)
@@ -107,3 +154,16 @@ func (h handler) handleGetSessions(w http.ResponseWriter, r *http.Request) {
 
 	httputil.WriteJSON(w, http.StatusOK, sessions)
 }
+
+// claimsFromContext reads the claims put by WithAuth, writing the error response when missing
+func claimsFromContext(w http.ResponseWriter, r *http.Request) (*token.Claims, bool) {
+	c, ok := r.Context().Value(middleware.AuthKey{}).(*token.Claims)
+	if !ok {
+		logging.Info("Unable to retrieve claims from token",
+			zap.String("journey", sessionHandlerJourney),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path))
+		fault.NewHTTPError(w, fault.NewUnauthorized("invalid access token"))
+	}
+	return c, ok
+}
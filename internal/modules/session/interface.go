@@ -13,14 +13,24 @@ type Repository interface {
 	GetByID(ctx context.Context, sessionId string) (*model.Session, error)
 	GetAllByUserID(ctx context.Context, userId string) ([]model.Session, error)
 	GetByRefreshToken(ctx context.Context, refreshToken string) (*model.Session, error)
-	GetActiveByUserID(ctx context.Context, userId string) (*model.Session, error)
-	DeactivateAll(ctx context.Context, userId string) error
+	// DeactivateAll deactivates every active session of the user, returning their IDs
+	DeactivateAll(ctx context.Context, userId string) ([]string, error)
+	// DeactivateOthers deactivates every active session of the user but the given one, returning their IDs
+	DeactivateOthers(ctx context.Context, userId, sessionId string) ([]string, error)
+	// DeactivateOldest deactivates the user's active sessions but the newest keep ones, returning their IDs
+	DeactivateOldest(ctx context.Context, userId string, keep int) ([]string, error)
 	Delete(ctx context.Context, sessionId string) error
 }
 
 type Service interface {
 	CreateSession(ctx context.Context, input dto.CreateSession) (*dto.SessionResponse, error)
 	GetAllSessions(ctx context.Context) ([]dto.SessionResponse, error)
-	GetSessionByUserID(ctx context.Context, userID string) (*dto.SessionResponse, error)
+	GetSessionByID(ctx context.Context, sessionID string) (*dto.SessionResponse, error)
+	// IsSessionActive implements middleware.SessionValidator
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	// RevokeSession ends a session of the user
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	// RevokeSessions ends every session of the user but except, every one when except is empty
+	RevokeSessions(ctx context.Context, userID, except string) error
 	RenewAccessToken(ctx context.Context, refreshToken string) (*dto.RenewAccessToken, error)
 }
@@ -37,16 +37,69 @@ func (r repo) GetByRefreshToken(ctx context.Context, refreshToken string) (*mode
 	return &session, nil
 }
 
-func (r repo) DeactivateAll(ctx context.Context, userId string) error {
+func (r repo) DeactivateAll(ctx context.Context, userId string) ([]string, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET active = false WHERE user_id = $1", userId)
+	var query = `
+		UPDATE sessions
+		SET active = false, updated_at = now()
+		WHERE user_id = $1 AND active = true
+		RETURNING id
+	`
+
+	var ids = make([]string, 0)
+	err := r.db.SelectContext(ctx, &ids, query, userId)
 	if err != nil {
-		return fault.New("failed to update session", fault.WithError(err))
+		return nil, fault.New("failed to update session", fault.WithError(err))
 	}
 
-	return nil
+	return ids, nil
+}
+
+func (r repo) DeactivateOthers(ctx context.Context, userId, sessionId string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE sessions
+		SET active = false, updated_at = now()
+		WHERE user_id = $1 AND id <> $2 AND active = true
+		RETURNING id
+	`
+
+	var ids = make([]string, 0)
+	err := r.db.SelectContext(ctx, &ids, query, userId, sessionId)
+	if err != nil {
+		return nil, fault.New("failed to update session", fault.WithError(err))
+	}
+
+	return ids, nil
+}
+
+func (r repo) DeactivateOldest(ctx context.Context, userId string, keep int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		UPDATE sessions
+		SET active = false, updated_at = now()
+		WHERE id IN (
+			SELECT id FROM sessions
+			WHERE user_id = $1 AND active = true
+			ORDER BY created_at DESC
+			OFFSET $2
+		)
+		RETURNING id
+	`
+
+	var ids = make([]string, 0)
+	err := r.db.SelectContext(ctx, &ids, query, userId, keep)
+	if err != nil {
+		return nil, fault.New("failed to update session", fault.WithError(err))
+	}
+
+	return ids, nil
 }
 
 func (r repo) Update(ctx context.Context, session model.Session) error {
@@ -58,7 +111,7 @@ func (r repo) Update(ctx context.Context, session model.Session) error {
 		SET
 			active = :active,
 			refresh_token = :refresh_token,
-			updated = :updated
+			updated_at = :updated_at
 		WHERE id = :id
 	`
 
@@ -78,7 +131,7 @@ func (r repo) GetAllByUserID(ctx context.Context, userId string) ([]model.Sessio
 	err := r.db.SelectContext(
 		ctx,
 		&sessions,
-		"SELECT * FROM sessions WHERE user_id = $1 ORDER BY created DESC",
+		"SELECT * FROM sessions WHERE user_id = $1 ORDER BY created_at DESC",
 		userId,
 	)
 	if err != nil {
@@ -88,30 +141,6 @@ func (r repo) GetAllByUserID(ctx context.Context, userId string) ([]model.Sessio
 	return sessions, nil
 }
 
-func (r repo) GetActiveByUserID(ctx context.Context, userId string) (*model.Session, error) {
-	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
-	defer cancel()
-
-	var session model.Session
-	err := r.db.GetContext(
-		ctx,
-		&session,
-		"SELECT * FROM sessions WHERE user_id = $1 AND active = true LIMIT 1",
-		userId,
-	)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, fault.New(
-			"failed to retrieve active session",
-			fault.WithError(err),
-		)
-	}
-
-	return &session, nil
-}
-
 func (r repo) Delete(ctx context.Context, sessionId string) error {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
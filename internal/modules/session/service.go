@@ -18,7 +18,16 @@ import (
 	"github.com/medama-io/go-useragent"
 )
 
-const sessionServiceJourney = "session service"
+const (
+	sessionServiceJourney = "session service"
+	// defaultMaxSessions is how many devices a user may be logged in at once when not configured
+	defaultMaxSessions = 5
+	// sessionCacheDuration is how long a session is served from the cache
+	sessionCacheDuration = time.Minute * 30
+	// revokedSessionDuration outlives any cache entry written by a request that
+	// read the session just before it was revoked
+	revokedSessionDuration = sessionCacheDuration + time.Minute
+)
 
 type ServiceConfig struct {
 	SessionRepo Repository
@@ -27,6 +36,9 @@ type ServiceConfig struct {
 	Cache *cache.Cache
 
 	SecretKey string
+	// MaxSessions is how many active sessions a user may have, logging in past
+	// it ends the oldest one. Zero means defaultMaxSessions.
+	MaxSessions int
 }
 
 type service struct {
@@ -34,15 +46,22 @@ type service struct {
 	userRepo    user.Repository
 	cache       *cache.Cache
 
-	secretKey string
+	secretKey   string
+	maxSessions int
 }
 
 func NewService(c ServiceConfig) Service {
+	maxSessions := c.MaxSessions
+	if maxSessions <= 0 {
+		maxSessions = defaultMaxSessions
+	}
+
 	return &service{
 		sessionRepo: c.SessionRepo,
 		userRepo:    c.UserRepo,
 		cache:       c.Cache,
 		secretKey:   c.SecretKey,
+		maxSessions: maxSessions,
 	}
 }
 
@@ -59,9 +78,21 @@ func (s service) RenewAccessToken(ctx context.Context, refreshToken string) (*dt
 		logging.Error("failed to retrieve session", err,
 			zap.String("journey", sessionServiceJourney))
 		return nil, fault.NewBadRequest("failed to retrieve session")
+	} else if sessRecord == nil {
+		logging.Info("session not found for refresh token",
+			zap.String("journey", sessionServiceJourney),
+			zap.String("userID", claims.UserID))
+		return nil, fault.NewUnauthorized("invalid refresh token")
 	}
 	session := NewFromModel(*sessRecord)
 
+	if !session.Active() {
+		logging.Info("session is no longer active",
+			zap.String("journey", sessionServiceJourney),
+			zap.String("sessionID", session.ID()))
+		return nil, fault.NewUnauthorized("session has ended")
+	}
+
 	if session.IsExpired() {
 		logging.Error("session has expired", err,
 			zap.String("journey", sessionServiceJourney))
@@ -76,7 +107,20 @@ func (s service) RenewAccessToken(ctx context.Context, refreshToken string) (*dt
 		return nil, fault.NewUnauthorized("unauthorized user")
 	}
 
-	newAccessToken, _, err := token.Gen(s.secretKey, claims.UserID, time.Minute*15)
+	// The role is read again so promotions and demotions apply on the next renewal
+	userRecord, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		logging.Error("failed to retrieve user", err,
+			zap.String("journey", sessionServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve user")
+	} else if userRecord == nil {
+		logging.Info("user not found",
+			zap.String("journey", sessionServiceJourney),
+			zap.String("userID", claims.UserID))
+		return nil, fault.NewUnauthorized("unauthorized user")
+	}
+
+	newAccessToken, _, err := token.Gen(s.secretKey, claims.UserID, userRecord.Role, session.ID(), time.Minute*15)
 	if err != nil {
 		logging.Error("failed to generate access token", err,
 			zap.String("journey", sessionServiceJourney))
@@ -89,67 +133,82 @@ func (s service) RenewAccessToken(ctx context.Context, refreshToken string) (*dt
 	}, nil
 }
 
-func (s service) GetSessionByUserID(ctx context.Context, userID string) (*dto.SessionResponse, error) {
-	var cachedSession *model.Session
-	err := s.cache.GetStruct(ctx, fmt.Sprintf("sess:%s", userID), &cachedSession)
-	if err != nil {
-		switch {
-		case fault.GetTag(err) == fault.CACHE_MISS:
-			logging.Info("cache: miss session not found",
-				zap.String("journey", sessionServiceJourney))
-		default:
-			logging.Error("failed to query session from cache", err,
-				zap.String("journey", sessionServiceJourney))
-		}
+func (s service) GetSessionByID(ctx context.Context, sessionID string) (*dto.SessionResponse, error) {
+	sessionRecord, err := s.getSession(ctx, sessionID)
+	if err != nil {
+		return nil, err // The error is already being handled in getSession
+	} else if sessionRecord == nil || !sessionRecord.Active {
+		logging.Info("active session not found",
+			zap.String("journey", sessionServiceJourney),
+			zap.String("sessionID", sessionID))
+		return nil, fault.NewNotFound("session not found")
 	}
 
-	if cachedSession != nil {
-		if time.Now().After(cachedSession.Expires) {
-			logging.Info("session has expired",
-				zap.String("journey", sessionServiceJourney),
-				zap.String("userID", userID))
-			return nil, fault.NewBadRequest("session has expired")
-		}
+	if time.Now().After(sessionRecord.Expires) {
+		logging.Info("session has expired",
+			zap.String("journey", sessionServiceJourney),
+			zap.String("sessionID", sessionID))
+		return nil, fault.NewBadRequest("session has expired")
+	}
 
-		return &dto.SessionResponse{
-			ID:        cachedSession.ID,
-			Agent:     cachedSession.Agent,
-			IP:        cachedSession.IP,
-			Active:    cachedSession.Active,
-			CreatedAt: cachedSession.CreatedAt,
-			UpdatedAt: cachedSession.UpdatedAt,
-		}, nil
+	return newSessionResponse(*sessionRecord, sessionID), nil
+}
+
+func (s service) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	sessionRecord, err := s.getSession(ctx, sessionID)
+	if err != nil {
+		return false, err // The error is already being handled in getSession
 	}
 
-	sessionRecord, err := s.sessionRepo.GetActiveByUserID(ctx, userID)
+	return sessionRecord != nil && sessionRecord.Active && time.Now().Before(sessionRecord.Expires), nil
+}
+
+func (s service) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	sessionRecord, err := s.sessionRepo.GetByID(ctx, sessionID)
 	if err != nil {
-		logging.Error("failed to retrieve active session", err,
+		logging.Error("failed to retrieve session", err,
 			zap.String("journey", sessionServiceJourney))
-		return nil, fault.NewBadRequest("failed to retrieve session")
-	} else if sessionRecord == nil {
+		return fault.NewBadRequest("failed to retrieve session")
+	} else if sessionRecord == nil || sessionRecord.UserID != userID || !sessionRecord.Active {
+		// Sessions of other users are reported as missing, not as forbidden
 		logging.Info("active session not found",
 			zap.String("journey", sessionServiceJourney),
-			zap.String("userID", userID))
-		return nil, fault.NewNotFound("session not found")
+			zap.String("sessionID", sessionID))
+		return fault.NewNotFound("active session not found")
 	}
 
-	res := &dto.SessionResponse{
-		ID:        sessionRecord.ID,
-		Agent:     sessionRecord.Agent,
-		IP:        sessionRecord.IP,
-		Active:    sessionRecord.Active,
-		CreatedAt: sessionRecord.CreatedAt,
-		UpdatedAt: sessionRecord.UpdatedAt,
+	sess := NewFromModel(*sessionRecord)
+	sess.Deactivate()
+
+	err = s.sessionRepo.Update(ctx, sess.Model())
+	if err != nil {
+		logging.Error("failed to deactivate session", err,
+			zap.String("journey", sessionServiceJourney))
+		return fault.NewBadRequest("failed to deactivate session")
 	}
 
-	cacheKey := fmt.Sprintf("sess:%s", userID)
-	err = s.cache.SetStruct(ctx, cacheKey, sessionRecord, time.Minute*30)
+	s.uncacheSessions(ctx, sessionID)
+
+	return nil
+}
+
+func (s service) RevokeSessions(ctx context.Context, userID, except string) error {
+	var revoked []string
+	var err error
+	if except == "" {
+		revoked, err = s.sessionRepo.DeactivateAll(ctx, userID)
+	} else {
+		revoked, err = s.sessionRepo.DeactivateOthers(ctx, userID, except)
+	}
 	if err != nil {
-		logging.Error("failed to cache session", err,
+		logging.Error("failed to deactivate user sessions", err,
 			zap.String("journey", sessionServiceJourney))
+		return fault.NewBadRequest("failed to deactivate user sessions")
 	}
 
-	return res, nil
+	s.uncacheSessions(ctx, revoked...)
+
+	return nil
 }
 
 func (s service) GetAllSessions(ctx context.Context) ([]dto.SessionResponse, error) {
@@ -176,14 +235,7 @@ func (s service) GetAllSessions(ctx context.Context) ([]dto.SessionResponse, err
 	// This is more efficient than appending to the slice
 	sessions := make([]dto.SessionResponse, len(records))
 	for i, s := range records {
-		sessions[i] = dto.SessionResponse{
-			ID:        s.ID,
-			Agent:     s.Agent,
-			IP:        s.IP,
-			Active:    s.Active,
-			CreatedAt: s.CreatedAt,
-			UpdatedAt: s.UpdatedAt,
-		}
+		sessions[i] = *newSessionResponse(s, c.SessionID)
 	}
 
 	return sessions, nil
@@ -212,13 +264,22 @@ func (s service) CreateSession(ctx context.Context, input dto.CreateSession) (*d
 		agent = "unknown agent"
 	}
 
-	sess, err := New(userID, input.IP, agent, input.RefreshToken)
+	sess, err := New(input.ID, userID, input.IP, agent, input.RefreshToken)
 	if err != nil {
 		logging.Error("failed to create session entity", err,
 			zap.String("journey", sessionServiceJourney))
 		return nil, fault.NewUnprocessableEntity("failed to create session entity")
 	}
 
+	// Past the limit, the sessions created the longest ago are logged out to make room
+	revoked, err := s.sessionRepo.DeactivateOldest(ctx, userID, s.maxSessions-1)
+	if err != nil {
+		logging.Error("failed to deactivate oldest sessions", err,
+			zap.String("journey", sessionServiceJourney))
+		return nil, fault.NewBadRequest("failed to deactivate oldest sessions")
+	}
+	s.uncacheSessions(ctx, revoked...)
+
 	err = s.sessionRepo.Insert(ctx, sess.Model())
 	if err != nil {
 		logging.Error("failed to insert session entity", err,
@@ -231,9 +292,104 @@ func (s service) CreateSession(ctx context.Context, input dto.CreateSession) (*d
 		Agent:     sess.Agent(),
 		IP:        sess.IP(),
 		Active:    sess.Active(),
+		Current:   true,
 		CreatedAt: sess.CreatedAt(),
 		UpdatedAt: sess.UpdatedAt(),
 	}
 
 	return &res, nil
 }
+
+// getSession returns the session, from the cache when possible. Only active
+// sessions are cached, so ending a session must drop it with uncacheSessions.
+func (s service) getSession(ctx context.Context, sessionID string) (*model.Session, error) {
+	// A request that read the session before it was revoked may cache it again
+	// afterwards, so revoked sessions are always read from the database
+	revoked, err := s.cache.Has(ctx, revokedKey(sessionID))
+	if err != nil {
+		logging.Error("failed to check revoked session in cache", err,
+			zap.String("journey", sessionServiceJourney))
+	}
+	useCache := err == nil && !revoked
+
+	if useCache {
+		var cachedSession *model.Session
+		err := s.cache.GetStruct(ctx, cacheKey(sessionID), &cachedSession)
+		if err != nil {
+			switch {
+			case fault.GetTag(err) == fault.CACHE_MISS:
+				logging.Info("cache: miss session not found",
+					zap.String("journey", sessionServiceJourney))
+			default:
+				logging.Error("failed to query session from cache", err,
+					zap.String("journey", sessionServiceJourney))
+			}
+		}
+		if cachedSession != nil {
+			return cachedSession, nil
+		}
+	}
+
+	sessionRecord, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		logging.Error("failed to retrieve session", err,
+			zap.String("journey", sessionServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve session")
+	} else if sessionRecord == nil || !sessionRecord.Active || !useCache {
+		return sessionRecord, nil
+	}
+
+	err = s.cache.SetStruct(ctx, cacheKey(sessionID), sessionRecord, sessionCacheDuration)
+	if err != nil {
+		logging.Error("failed to cache session", err,
+			zap.String("journey", sessionServiceJourney))
+	}
+
+	return sessionRecord, nil
+}
+
+// uncacheSessions drops the cached sessions and marks them as revoked, so their
+// end applies right away and they are not cached again
+func (s service) uncacheSessions(ctx context.Context, sessionIDs ...string) {
+	if len(sessionIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, len(sessionIDs))
+	for i, id := range sessionIDs {
+		err := s.cache.SetString(ctx, revokedKey(id), "1", revokedSessionDuration)
+		if err != nil {
+			logging.Error("failed to mark session as revoked in cache", err,
+				zap.String("journey", sessionServiceJourney),
+				zap.String("sessionID", id))
+		}
+		keys[i] = cacheKey(id)
+	}
+
+	err := s.cache.Delete(ctx, keys...)
+	if err != nil {
+		logging.Error("failed to delete sessions from cache", err,
+			zap.String("journey", sessionServiceJourney))
+	}
+}
+
+func cacheKey(sessionID string) string {
+	return fmt.Sprintf("sess:%s", sessionID)
+}
+
+func revokedKey(sessionID string) string {
+	return fmt.Sprintf("sess:revoked:%s", sessionID)
+}
+
+// newSessionResponse builds the response, current being the session of the caller
+func newSessionResponse(m model.Session, current string) *dto.SessionResponse {
+	return &dto.SessionResponse{
+		ID:        m.ID,
+		Agent:     m.Agent,
+		IP:        m.IP,
+		Active:    m.Active,
+		Current:   m.ID == current,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
@@ -26,9 +26,15 @@ type session struct {
 	updatedAt    time.Time
 }
 
-func New(userId, ip, agent, refresh string) (*session, error) {
+// NewID returns the ID for a new session. It is generated apart from the session
+// so the tokens carrying it can be signed before the session is created.
+func NewID() string {
+	return uid.New("sess")
+}
+
+func New(id, userId, ip, agent, refresh string) (*session, error) {
 	s := session{
-		id:           uid.New("sess"),
+		id:           id,
 		userId:       userId,
 		ip:           ip,
 		agent:        agent,
@@ -65,6 +71,9 @@ func NewFromModel(m model.Session) *session {
 }
 
 func (s *session) validate() error {
+	if s.id == "" {
+		return fault.New("id is required")
+	}
 	if s.userId == "" {
 		return fault.New("user id is required")
 	}
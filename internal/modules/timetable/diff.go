@@ -0,0 +1,167 @@
+package timetable
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+)
+
+// Diff lists how importing a timetable changes the stored routes
+type Diff struct {
+	Added   []string      `json:"added"`
+	Changed []RouteChange `json:"changed"`
+	// Removed is only filled when the import replaces the whole network
+	Removed   []string `json:"removed"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// RouteChange names the fields of a route that an import changes
+type RouteChange struct {
+	ID     string   `json:"id"`
+	Fields []string `json:"fields"`
+}
+
+// diffRoutes compares the timetable routes with the ones stored in the database
+func diffRoutes(ctx context.Context, repo route.Repository, tt *Timetable, replace bool) (*Diff, error) {
+	records, err := repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(records))
+	for i, r := range records {
+		ids[i] = r.ID
+	}
+
+	stops, err := repo.GetStopsByRouteIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	departures, err := repo.GetDeparturesByRouteIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	locationIds := make([]string, 0, len(stops))
+	for _, r := range records {
+		if r.DepartureLocationID != nil {
+			locationIds = append(locationIds, *r.DepartureLocationID)
+		}
+		if r.ArrivalLocationID != nil {
+			locationIds = append(locationIds, *r.ArrivalLocationID)
+		}
+	}
+	for _, st := range stops {
+		locationIds = append(locationIds, st.LocationID)
+	}
+	slices.Sort(locationIds)
+
+	locations, err := repo.GetLocationsByIDs(ctx, slices.Compact(locationIds))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(locations))
+	for _, l := range locations {
+		names[l.ID] = l.Name
+	}
+	nameOf := func(id *string) string {
+		if id == nil {
+			return ""
+		}
+		return names[*id]
+	}
+
+	stored := make(map[string]model.Route, len(records))
+	for _, r := range records {
+		stored[r.ID] = r
+	}
+	storedStops := make(map[string][]string)
+	for _, st := range stops {
+		storedStops[st.RouteID] = append(storedStops[st.RouteID], stopKey(names[st.LocationID], st.IsDeparture, st.IsArrival))
+	}
+	storedDepartures := make(map[string][]string)
+	for _, d := range departures {
+		storedDepartures[d.RouteID] = append(storedDepartures[d.RouteID], departureKey(d.CalendarID, d.DepartsAt.String()))
+	}
+
+	diff := &Diff{
+		Added:   make([]string, 0),
+		Changed: make([]RouteChange, 0),
+		Removed: make([]string, 0),
+	}
+
+	incoming := make(map[string]bool, len(tt.Routes))
+	for _, r := range tt.Routes {
+		incoming[r.ID] = true
+
+		current, ok := stored[r.ID]
+		if !ok {
+			diff.Added = append(diff.Added, r.ID)
+			continue
+		}
+
+		newStops := make([]string, len(r.Stops))
+		for i, st := range r.Stops {
+			newStops[i] = stopKey(st.Name, st.Departure, st.Arrival)
+		}
+		newDepartures := make([]string, len(r.DepartureTimes()))
+		for i, d := range r.DepartureTimes() {
+			newDepartures[i] = departureKey(d.CalendarID, d.Time.String())
+		}
+		oldDepartures := storedDepartures[r.ID]
+		slices.Sort(oldDepartures)
+		slices.Sort(newDepartures)
+
+		fields := make([]string, 0)
+		if current.Name != r.Name {
+			fields = append(fields, "name")
+		}
+		if current.TripLength != r.TripLength {
+			fields = append(fields, "trip_length")
+		}
+		if nameOf(current.DepartureLocationID) != r.Departure {
+			fields = append(fields, "departure")
+		}
+		if nameOf(current.ArrivalLocationID) != r.Arrival {
+			fields = append(fields, "arrival")
+		}
+		if !slices.Equal(current.Notes, r.Notes) && len(current.Notes)+len(r.Notes) > 0 {
+			fields = append(fields, "notes")
+		}
+		if !slices.Equal(storedStops[r.ID], newStops) {
+			fields = append(fields, "stops")
+		}
+		if !slices.Equal(oldDepartures, newDepartures) {
+			fields = append(fields, "departures")
+		}
+
+		if len(fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changed = append(diff.Changed, RouteChange{ID: r.ID, Fields: fields})
+	}
+
+	if replace {
+		for _, r := range records {
+			if !incoming[r.ID] {
+				diff.Removed = append(diff.Removed, r.ID)
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+func stopKey(name string, departure, arrival bool) string {
+	return fmt.Sprintf("%s|%t|%t", name, departure, arrival)
+}
+
+func departureKey(calendarId, t string) string {
+	return calendarId + "|" + t
+}
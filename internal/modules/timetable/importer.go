@@ -0,0 +1,228 @@
+package timetable
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/dbutil"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/uid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+const importerJourney = "timetable importer"
+
+// errDryRun rolls back the import transaction of a dry run
+var errDryRun = errors.New("dry run")
+
+// Options tune how a timetable is imported
+type Options struct {
+	// DryRun runs the whole import but rolls it back, only reporting the diff
+	DryRun bool
+	// Replace removes the stored routes that are missing from the timetable
+	Replace bool
+}
+
+// Result summarises what an import wrote, or would write on a dry run
+type Result struct {
+	DryRun     bool  `json:"dry_run"`
+	Calendars  int   `json:"calendars"`
+	Locations  int   `json:"locations"`
+	Routes     int   `json:"routes"`
+	Stops      int   `json:"stops"`
+	Departures int   `json:"departures"`
+	Removed    int64 `json:"removed"`
+	Diff       *Diff `json:"diff"`
+}
+
+type Importer struct {
+	db *sqlx.DB
+}
+
+func NewImporter(db *sqlx.DB) *Importer {
+	return &Importer{db: db}
+}
+
+// Import upserts the timetable inside a single transaction.
+// Rows are matched by their natural keys (location name, route id, stop order
+// and departure time), so importing the same file again never duplicates rows.
+func (i *Importer) Import(ctx context.Context, tt *Timetable, opts Options) (*Result, error) {
+	diff, err := diffRoutes(ctx, route.NewRepo(i.db), tt, opts.Replace)
+	if err != nil {
+		logging.Error("failed to compare timetable with stored routes", err,
+			zap.String("journey", importerJourney))
+		return nil, fault.New("failed to compare timetable with stored routes", fault.WithError(err))
+	}
+
+	res := Result{DryRun: opts.DryRun, Diff: diff}
+
+	err = dbutil.ExecTx(ctx, i.db, func(tx *sqlx.Tx) error {
+		w := route.NewWriter(tx)
+		cw := calendar.NewWriter(tx)
+		now := time.Now()
+
+		for _, c := range tt.Calendars {
+			err := cw.UpsertCalendar(ctx, model.ServiceCalendar{
+				ID:        c.ID,
+				Name:      c.Name,
+				Monday:    c.Runs("monday"),
+				Tuesday:   c.Runs("tuesday"),
+				Wednesday: c.Runs("wednesday"),
+				Thursday:  c.Runs("thursday"),
+				Friday:    c.Runs("friday"),
+				Saturday:  c.Runs("saturday"),
+				Sunday:    c.Runs("sunday"),
+				TermKind:  c.TermKind,
+				StartDate: optionalDate(c.StartDate),
+				EndDate:   optionalDate(c.EndDate),
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		res.Calendars = len(tt.Calendars)
+
+		coordinates := make(map[string]Stop)
+		for _, r := range tt.Routes {
+			for _, st := range r.Stops {
+				if _, ok := coordinates[st.Name]; !ok && st.Latitude != nil && st.Longitude != nil {
+					coordinates[st.Name] = st
+				}
+			}
+		}
+
+		locationIds := make(map[string]string)
+		for _, name := range tt.LocationNames() {
+			id, err := w.UpsertLocation(ctx, model.Location{
+				ID:        uid.New("loc"),
+				Name:      name,
+				Latitude:  coordinates[name].Latitude,
+				Longitude: coordinates[name].Longitude,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+			if err != nil {
+				return err
+			}
+			locationIds[name] = id
+		}
+		res.Locations = len(locationIds)
+
+		for _, r := range tt.Routes {
+			err := w.UpsertRoute(ctx, model.Route{
+				ID:                  r.ID,
+				Name:                r.Name,
+				TripLength:          r.TripLength,
+				DepartureLocationID: optionalID(locationIds, r.Departure),
+				ArrivalLocationID:   optionalID(locationIds, r.Arrival),
+				Notes:               append(make([]string, 0, len(r.Notes)), r.Notes...),
+				CreatedAt:           now,
+				UpdatedAt:           now,
+			})
+			if err != nil {
+				return err
+			}
+
+			stops := make([]model.RouteStop, len(r.Stops))
+			for order, st := range r.Stops {
+				stops[order] = model.RouteStop{
+					ID:          uid.New("stop"),
+					RouteID:     r.ID,
+					LocationID:  locationIds[st.Name],
+					StopOrder:   order,
+					IsDeparture: st.Departure,
+					IsArrival:   st.Arrival,
+					CreatedAt:   now,
+					UpdatedAt:   now,
+				}
+			}
+			if err := w.ReplaceStops(ctx, r.ID, stops); err != nil {
+				return err
+			}
+
+			departures := make([]model.DepartureTime, len(r.DepartureTimes()))
+			for j, d := range r.DepartureTimes() {
+				departures[j] = model.DepartureTime{
+					ID:         uid.New("dep"),
+					RouteID:    r.ID,
+					CalendarID: d.CalendarID,
+					DepartsAt:  d.Time,
+					CreatedAt:  now,
+					UpdatedAt:  now,
+				}
+			}
+			if err := w.ReplaceDepartures(ctx, r.ID, departures); err != nil {
+				return err
+			}
+
+			res.Routes++
+			res.Stops += len(stops)
+			res.Departures += len(departures)
+		}
+
+		if opts.Replace {
+			routeIds := make([]string, len(tt.Routes))
+			for j, r := range tt.Routes {
+				routeIds[j] = r.ID
+			}
+
+			removed, err := w.DeleteRoutesExcept(ctx, routeIds)
+			if err != nil {
+				return err
+			}
+			res.Removed = removed
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		logging.Error("failed to import timetable", err,
+			zap.String("journey", importerJourney))
+		return nil, fault.New(
+			"failed to import timetable",
+			fault.WithTag(fault.DB_TRANSACTION),
+			fault.WithError(err),
+		)
+	}
+
+	logging.Info("timetable imported",
+		zap.String("journey", importerJourney),
+		zap.Bool("dryRun", res.DryRun),
+		zap.Int("calendars", res.Calendars),
+		zap.Int("locations", res.Locations),
+		zap.Int("routes", res.Routes),
+		zap.Int("stops", res.Stops),
+		zap.Int("departures", res.Departures),
+		zap.Int64("removed", res.Removed))
+
+	return &res, nil
+}
+
+func optionalID(ids map[string]string, name string) *string {
+	id, ok := ids[name]
+	if !ok {
+		return nil
+	}
+	return &id
+}
+
+func optionalDate(v string) *time.Time {
+	if v == "" {
+		return nil
+	}
+
+	// The date was already validated when the timetable was parsed
+	date, _ := time.Parse(calendar.DateLayout, v)
+	return &date
+}
@@ -0,0 +1,266 @@
+package timetable
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/calendar"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/timeofday"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SchemaVersion is the timetable file version supported by this build
+	SchemaVersion = 1
+	// DefaultCalendar is the calendar of departures listed without one
+	DefaultCalendar = "weekdays"
+)
+
+var weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// Timetable is the versioned description of the whole BUZUFBA network,
+// as read from a YAML or JSON file
+type Timetable struct {
+	Version   int        `yaml:"version" json:"version"`
+	Calendars []Calendar `yaml:"calendars" json:"calendars"`
+	Locations []string   `yaml:"locations" json:"locations"`
+	Routes    []Route    `yaml:"routes" json:"routes"`
+}
+
+// Calendar declares the days a set of departures runs on, see the calendar module
+type Calendar struct {
+	ID        string   `yaml:"id" json:"id"`
+	Name      string   `yaml:"name" json:"name"`
+	Days      []string `yaml:"days" json:"days"`
+	TermKind  string   `yaml:"term_kind" json:"term_kind"`
+	StartDate string   `yaml:"start_date" json:"start_date"`
+	EndDate   string   `yaml:"end_date" json:"end_date"`
+}
+
+type Route struct {
+	ID         string   `yaml:"id" json:"id"`
+	Name       string   `yaml:"name" json:"name"`
+	TripLength float64  `yaml:"trip_length" json:"trip_length"`
+	Departure  string   `yaml:"departure" json:"departure"`
+	Arrival    string   `yaml:"arrival" json:"arrival"`
+	Notes      []string `yaml:"notes" json:"notes"`
+	Stops      []Stop   `yaml:"stops" json:"stops"`
+	// Departures run on the DefaultCalendar
+	Departures []string `yaml:"departures" json:"departures"`
+	// Schedules list departures running on other calendars
+	Schedules []Schedule `yaml:"schedules" json:"schedules"`
+
+	// departureTimes holds the normalised, sorted and unique departures
+	departureTimes []Departure
+}
+
+type Schedule struct {
+	Calendar   string   `yaml:"calendar" json:"calendar"`
+	Departures []string `yaml:"departures" json:"departures"`
+}
+
+// Departure is a normalised departure time bound to its calendar
+type Departure struct {
+	CalendarID string
+	Time       timeofday.TimeOfDay
+}
+
+type Stop struct {
+	Name      string `yaml:"name" json:"name"`
+	Departure bool   `yaml:"departure" json:"departure"`
+	Arrival   bool   `yaml:"arrival" json:"arrival"`
+	// Latitude and Longitude are optional, known coordinates are kept when missing
+	Latitude  *float64 `yaml:"latitude,omitempty" json:"latitude,omitempty"`
+	Longitude *float64 `yaml:"longitude,omitempty" json:"longitude,omitempty"`
+}
+
+// Load reads and validates a timetable file. The format is picked
+// from the file extension: .yaml, .yml or .json
+func Load(path string) (*Timetable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fault.New("failed to open timetable file", fault.WithError(err))
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return Parse(f, json.Unmarshal)
+	case ".yaml", ".yml":
+		return Parse(f, yaml.Unmarshal)
+	default:
+		return nil, fault.New(fmt.Sprintf("unsupported timetable file extension %q", filepath.Ext(path)))
+	}
+}
+
+// Parse decodes a timetable with the given unmarshal function and validates it
+func Parse(r io.Reader, unmarshal func([]byte, any) error) (*Timetable, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fault.New("failed to read timetable", fault.WithError(err))
+	}
+
+	var tt Timetable
+	if err := unmarshal(data, &tt); err != nil {
+		return nil, fault.New("failed to decode timetable", fault.WithError(err))
+	}
+
+	if err := tt.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &tt, nil
+}
+
+// Validate checks and normalises a timetable built in code, e.g. from a GTFS feed.
+// Timetables returned by Load and Parse are already validated.
+func (t *Timetable) Validate() error {
+	if err := t.validate(); err != nil {
+		return fault.New(
+			"invalid timetable",
+			fault.WithTag(fault.INVALID_ENTITY),
+			fault.WithError(err),
+		)
+	}
+
+	return nil
+}
+
+// LocationNames returns every location referenced by the timetable, without duplicates
+func (t *Timetable) LocationNames() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0, len(t.Locations))
+
+	add := func(name string) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	for _, name := range t.Locations {
+		add(name)
+	}
+	for _, r := range t.Routes {
+		add(r.Departure)
+		add(r.Arrival)
+		for _, st := range r.Stops {
+			add(st.Name)
+		}
+	}
+
+	return names
+}
+
+// DepartureTimes returns the normalised departures of the route, sorted by calendar and time.
+// It is only populated for timetables returned by Load or Parse.
+func (r Route) DepartureTimes() []Departure {
+	return r.departureTimes
+}
+
+// Runs reports whether the calendar runs on the given weekday name
+func (c Calendar) Runs(weekday string) bool {
+	return slices.Contains(c.Days, weekday)
+}
+
+func (t *Timetable) validate() error {
+	if t.Version != SchemaVersion {
+		return fmt.Errorf("unsupported timetable version %d, expected %d", t.Version, SchemaVersion)
+	}
+
+	calendars := map[string]bool{DefaultCalendar: true}
+	for i := range t.Calendars {
+		c := &t.Calendars[i]
+		c.ID = strings.TrimSpace(c.ID)
+
+		if c.ID == "" {
+			return fmt.Errorf("calendar #%d: id is required", i+1)
+		}
+		if c.Name == "" {
+			return fmt.Errorf("calendar %s: name is required", c.ID)
+		}
+		for _, d := range c.Days {
+			if !slices.Contains(weekdays, d) {
+				return fmt.Errorf("calendar %s: invalid day %q", c.ID, d)
+			}
+		}
+		if c.TermKind == "" {
+			c.TermKind = calendar.TermKindRegular
+		}
+		if c.TermKind != calendar.TermKindRegular && c.TermKind != calendar.TermKindExam {
+			return fmt.Errorf("calendar %s: invalid term kind %q", c.ID, c.TermKind)
+		}
+		for _, d := range []string{c.StartDate, c.EndDate} {
+			if d == "" {
+				continue
+			}
+			if _, err := time.Parse(calendar.DateLayout, d); err != nil {
+				return fmt.Errorf("calendar %s: invalid date %q", c.ID, d)
+			}
+		}
+		calendars[c.ID] = true
+	}
+
+	ids := make(map[string]bool, len(t.Routes))
+	for i := range t.Routes {
+		r := &t.Routes[i]
+		r.ID = strings.TrimSpace(r.ID)
+
+		if r.ID == "" {
+			return fmt.Errorf("route #%d: id is required", i+1)
+		}
+		if ids[r.ID] {
+			return fmt.Errorf("route %s: duplicated id", r.ID)
+		}
+		ids[r.ID] = true
+
+		if r.Name == "" {
+			return fmt.Errorf("route %s: name is required", r.ID)
+		}
+		if r.TripLength < 0 {
+			return fmt.Errorf("route %s: trip length must not be negative", r.ID)
+		}
+		if len(r.Stops) == 0 {
+			return fmt.Errorf("route %s: at least one stop is required", r.ID)
+		}
+		for j, st := range r.Stops {
+			if strings.TrimSpace(st.Name) == "" {
+				return fmt.Errorf("route %s: stop #%d has no name", r.ID, j+1)
+			}
+		}
+
+		schedules := append([]Schedule{{Calendar: DefaultCalendar, Departures: r.Departures}}, r.Schedules...)
+		departures := make([]Departure, 0, len(r.Departures))
+		for _, sch := range schedules {
+			if !calendars[sch.Calendar] {
+				return fmt.Errorf("route %s: unknown calendar %q", r.ID, sch.Calendar)
+			}
+
+			for _, raw := range sch.Departures {
+				v, err := timeofday.Parse(raw)
+				if err != nil {
+					return fmt.Errorf("route %s: %w", r.ID, err)
+				}
+				departures = append(departures, Departure{CalendarID: sch.Calendar, Time: v})
+			}
+		}
+
+		slices.SortFunc(departures, func(a, b Departure) int {
+			if c := strings.Compare(a.CalendarID, b.CalendarID); c != 0 {
+				return c
+			}
+			return a.Time.Minutes() - b.Time.Minutes()
+		})
+		r.departureTimes = slices.Compact(departures)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package tracking
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/role"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/http/middleware"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	httputil "github.com/brnocorreia/api-meu-buzufba/pkg/http_util"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	trackingHandlerJourney = "tracking handler"
+)
+
+var (
+	instance *handler
+	once     sync.Once
+)
+
+type handler struct {
+	trackingService Service
+	hub             *Hub
+	secretKey       string
+	sessions        middleware.SessionValidator
+}
+
+func NewHandler(trackingService Service, hub *Hub, secretKey string, sessions middleware.SessionValidator) *handler {
+	once.Do(func() {
+		instance = &handler{
+			trackingService: trackingService,
+			hub:             hub,
+			secretKey:       secretKey,
+			sessions:        sessions,
+		}
+	})
+	return instance
+}
+
+func (h handler) Register(r *chi.Mux) {
+	m := middleware.NewWithAuth(h.secretKey, h.sessions)
+
+	r.Route("/api/v1/vehicles", func(r chi.Router) {
+		// Staff
+		r.With(m.WithAuth, m.WithRole(role.Admin, role.Driver)).Post("/positions", h.handleReportPosition)
+		// Public
+		r.Get("/", h.handleGetVehiclePositions)
+		r.Get("/{vehicleId}", h.handleGetVehiclePosition)
+	})
+
+	// Public
+	r.Get("/api/v1/routes/{routeId}/live", h.handleLiveSSE)
+	r.Get("/api/v1/routes/{routeId}/live/ws", h.handleLiveWebSocket)
+}
+
+func (h handler) handleReportPosition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var body dto.ReportPosition
+	err := httputil.ReadRequestBody(w, r, &body)
+	if err != nil {
+		logErrorInReadRequestBody(err, r)
+		fault.NewHTTPError(w, fault.NewBadRequest(err.Error()))
+		return
+	}
+
+	res, err := h.trackingService.ReportPosition(ctx, body)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusAccepted, res)
+}
+
+// handleGetVehiclePositions lists the live vehicles, of a single route with ?route_id=
+func (h handler) handleGetVehiclePositions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res, err := h.trackingService.GetVehiclePositions(ctx, r.URL.Query().Get("route_id"))
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func (h handler) handleGetVehiclePosition(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vehicleId := chi.URLParam(r, "vehicleId")
+
+	res, err := h.trackingService.GetVehiclePosition(ctx, vehicleId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, res)
+}
+
+func logErrorInReadRequestBody(err error, r *http.Request) {
+	logging.Error("failed to read request body", err,
+		zap.String("journey", trackingHandlerJourney),
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path))
+}
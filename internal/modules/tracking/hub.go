@@ -0,0 +1,173 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/brnocorreia/api-meu-buzufba/pkg/cache"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	hubJourney = "live hub"
+	// liveChannelPrefix starts the pub/sub channels where each route's positions are published
+	liveChannelPrefix = "live:route:"
+	// subscriberBuffer is how many positions may wait for a slow client
+	subscriberBuffer = 32
+	// maxDropped disconnects clients that missed this many positions in a row,
+	// they reconnect and start over from the current positions
+	maxDropped = 64
+)
+
+type HubConfig struct {
+	Cache *cache.Cache
+}
+
+// Hub fans out the positions published by any instance of the API to the
+// clients of this instance following a route. A single pub/sub subscription
+// serves every client.
+type Hub struct {
+	cache       *cache.Cache
+	mu          sync.Mutex
+	subscribers map[string]map[*Subscriber]struct{}
+	closed      bool
+}
+
+// Subscriber receives the positions of the vehicles of a route, as JSON
+type Subscriber struct {
+	routeId  string
+	messages chan []byte
+	done     chan struct{}
+	dropped  int
+}
+
+func NewHub(c HubConfig) *Hub {
+	return &Hub{
+		cache:       c.Cache,
+		subscribers: make(map[string]map[*Subscriber]struct{}),
+	}
+}
+
+// Messages delivers the positions published for the route
+func (s *Subscriber) Messages() <-chan []byte {
+	return s.messages
+}
+
+// Done is closed when the subscriber is dropped for being too slow or the hub shuts down
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+// Run relays the published positions until ctx is done, then disconnects every subscriber
+func (h *Hub) Run(ctx context.Context) {
+	sub := h.cache.PSubscribe(ctx, liveChannelPrefix+"*")
+	defer sub.Close()
+
+	logging.Info("live hub started", zap.String("journey", hubJourney))
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			h.close()
+			logging.Info("live hub stopped", zap.String("journey", hubJourney))
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				h.close()
+				return
+			}
+			h.broadcast(strings.TrimPrefix(msg.Channel, liveChannelPrefix), []byte(msg.Payload))
+		}
+	}
+}
+
+// Subscribe starts following the route, the subscriber must be given back with Unsubscribe
+func (h *Hub) Subscribe(routeId string) (*Subscriber, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, fault.New("live hub is shutting down", fault.WithHTTPCode(http.StatusServiceUnavailable))
+	}
+
+	s := &Subscriber{
+		routeId:  routeId,
+		messages: make(chan []byte, subscriberBuffer),
+		done:     make(chan struct{}),
+	}
+	if h.subscribers[routeId] == nil {
+		h.subscribers[routeId] = make(map[*Subscriber]struct{})
+	}
+	h.subscribers[routeId][s] = struct{}{}
+
+	return s, nil
+}
+
+func (h *Hub) Unsubscribe(s *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(s)
+}
+
+// broadcast hands the message to the subscribers of the route without ever
+// blocking, a full buffer drops the message for that subscriber only
+func (h *Hub) broadcast(routeId string, data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subscribers[routeId] {
+		select {
+		case s.messages <- data:
+			s.dropped = 0
+		default:
+			s.dropped++
+			if s.dropped >= maxDropped {
+				logging.Info("slow live client disconnected",
+					zap.String("journey", hubJourney),
+					zap.String("routeID", routeId))
+				h.remove(s)
+			}
+		}
+	}
+}
+
+func (h *Hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, subscribers := range h.subscribers {
+		for s := range subscribers {
+			h.remove(s)
+		}
+	}
+}
+
+// remove must be called with the lock held
+func (h *Hub) remove(s *Subscriber) {
+	subscribers, ok := h.subscribers[s.routeId]
+	if !ok {
+		return
+	}
+	if _, ok := subscribers[s]; !ok {
+		return
+	}
+
+	close(s.done)
+	delete(subscribers, s)
+	if len(subscribers) == 0 {
+		delete(h.subscribers, s.routeId)
+	}
+}
+
+// liveChannel is the pub/sub channel of the route's positions
+func liveChannel(routeId string) string {
+	return fmt.Sprintf("%s%s", liveChannelPrefix, routeId)
+}
@@ -0,0 +1,22 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+)
+
+type Repository interface {
+	Insert(ctx context.Context, position model.VehiclePosition) error
+	// DeleteBefore removes the positions recorded before the given time, returning how many were removed
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type Service interface {
+	ReportPosition(ctx context.Context, input dto.ReportPosition) (*dto.VehiclePositionResponse, error)
+	// GetVehiclePositions returns the latest position of the live vehicles, of every route when routeId is empty
+	GetVehiclePositions(ctx context.Context, routeId string) ([]dto.VehiclePositionResponse, error)
+	GetVehiclePosition(ctx context.Context, vehicleId string) (*dto.VehiclePositionResponse, error)
+}
@@ -0,0 +1,167 @@
+package tracking
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	listenerJourney = "position listener"
+	// maxLineBytes caps the size of a single position line, and of an UDP datagram
+	maxLineBytes = 4096
+	// connIdleTimeout closes TCP connections of devices that stopped reporting
+	connIdleTimeout = time.Minute * 5
+)
+
+type ListenerConfig struct {
+	TrackingService Service
+	// DeviceKey is the shared secret devices put in every line they send
+	DeviceKey string
+}
+
+// Listener takes positions from devices that cannot keep an HTTP session, as
+// GPS trackers do. Each line, or UDP datagram, is a JSON dto.ReportPosition
+// with the device key in its device_key field.
+type Listener struct {
+	trackingService Service
+	deviceKey       []byte
+}
+
+// deviceLine is a position sent to the listener
+type deviceLine struct {
+	dto.ReportPosition
+	DeviceKey string `json:"device_key"`
+}
+
+func NewListener(c ListenerConfig) *Listener {
+	return &Listener{
+		trackingService: c.TrackingService,
+		deviceKey:       []byte(c.DeviceKey),
+	}
+}
+
+// ListenUDP reads positions from UDP datagrams sent to addr until ctx is done
+func (l *Listener) ListenUDP(ctx context.Context, addr string) error {
+	conn, err := new(net.ListenConfig).ListenPacket(ctx, "udp", addr)
+	if err != nil {
+		return err
+	}
+	context.AfterFunc(ctx, func() { conn.Close() })
+
+	logging.Info("udp position listener started",
+		zap.String("journey", listenerJourney),
+		zap.String("addr", addr))
+
+	buf := make([]byte, maxLineBytes)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				logging.Info("udp position listener stopped", zap.String("journey", listenerJourney))
+				return nil
+			}
+			return err
+		}
+
+		// A datagram may carry a few lines buffered by the device
+		for line := range bytes.Lines(buf[:n]) {
+			l.handleLine(ctx, line)
+		}
+	}
+}
+
+// ListenTCP reads line-delimited positions from TCP connections to addr until ctx is done
+func (l *Listener) ListenTCP(ctx context.Context, addr string) error {
+	ln, err := new(net.ListenConfig).Listen(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	context.AfterFunc(ctx, func() { ln.Close() })
+
+	logging.Info("tcp position listener started",
+		zap.String("journey", listenerJourney),
+		zap.String("addr", addr))
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				logging.Info("tcp position listener stopped", zap.String("journey", listenerJourney))
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		go l.serveConn(ctx, conn)
+	}
+}
+
+func (l *Listener) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, maxLineBytes), maxLineBytes)
+	for {
+		conn.SetReadDeadline(time.Now().Add(connIdleTimeout))
+		if !scanner.Scan() {
+			break
+		}
+		l.handleLine(ctx, scanner.Bytes())
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		logging.Info("tcp position connection closed",
+			zap.String("journey", listenerJourney),
+			zap.String("remote", conn.RemoteAddr().String()),
+			zap.String("reason", err.Error()))
+	}
+}
+
+// handleLine reports the position in the line. Devices get no answer, so
+// rejected lines are only logged.
+func (l *Listener) handleLine(ctx context.Context, line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+
+	var msg deviceLine
+	err := json.Unmarshal(line, &msg)
+	if err != nil {
+		logging.Info("malformed position line",
+			zap.String("journey", listenerJourney),
+			zap.String("reason", err.Error()))
+		return
+	}
+
+	if subtle.ConstantTimeCompare([]byte(msg.DeviceKey), l.deviceKey) != 1 {
+		logging.Info("position line with invalid device key",
+			zap.String("journey", listenerJourney),
+			zap.String("vehicleID", msg.VehicleID))
+		return
+	}
+
+	_, err = l.trackingService.ReportPosition(ctx, msg.ReportPosition)
+	if err != nil {
+		logging.Info("position line rejected",
+			zap.String("journey", listenerJourney),
+			zap.String("vehicleID", msg.VehicleID),
+			zap.String("reason", err.Error()))
+	}
+}
@@ -0,0 +1,68 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	prunerJourney = "position pruner"
+	// pruneInterval is how often the expired positions are removed
+	pruneInterval = time.Hour
+	// PositionRetention is how long the position history is kept, enough to learn
+	// the travel times of every hour of the week a few times over
+	PositionRetention = time.Hour * 24 * 30
+)
+
+type PrunerConfig struct {
+	PositionRepo Repository
+}
+
+// Pruner removes the positions older than PositionRetention from the history.
+// Deleting is idempotent, so every instance of the API may run one.
+type Pruner struct {
+	positionRepo Repository
+}
+
+func NewPruner(c PrunerConfig) *Pruner {
+	return &Pruner{
+		positionRepo: c.PositionRepo,
+	}
+}
+
+// Run prunes the history every hour until ctx is done
+func (p *Pruner) Run(ctx context.Context) {
+	logging.Info("position pruner started", zap.String("journey", prunerJourney))
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		p.prune(ctx)
+
+		select {
+		case <-ctx.Done():
+			logging.Info("position pruner stopped", zap.String("journey", prunerJourney))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Pruner) prune(ctx context.Context) {
+	deleted, err := p.positionRepo.DeleteBefore(ctx, time.Now().Add(-PositionRetention))
+	if err != nil {
+		logging.Error("failed to prune vehicle positions", err,
+			zap.String("journey", prunerJourney))
+		return
+	}
+
+	if deleted > 0 {
+		logging.Info("vehicle positions pruned",
+			zap.String("journey", prunerJourney),
+			zap.Int64("deleted", deleted))
+	}
+}
@@ -0,0 +1,67 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/jmoiron/sqlx"
+)
+
+type repo struct {
+	db *sqlx.DB
+}
+
+func NewRepo(db *sqlx.DB) Repository {
+	return &repo{db: db}
+}
+
+func (r repo) Insert(ctx context.Context, position model.VehiclePosition) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	var query = `
+		INSERT INTO vehicle_positions (
+			vehicle_id,
+			route_id,
+			latitude,
+			longitude,
+			heading,
+			from_stop_order,
+			to_stop_order,
+			progress,
+			recorded_at
+		) VALUES (
+			:vehicle_id,
+			:route_id,
+			:latitude,
+			:longitude,
+			:heading,
+			:from_stop_order,
+			:to_stop_order,
+			:progress,
+			:recorded_at
+		)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, position)
+	if err != nil {
+		return fault.New("failed to insert vehicle position", fault.WithError(err))
+	}
+
+	return nil
+}
+
+func (r repo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	// Pruning a long backlog may take a while, so it gets more time than the other queries
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM vehicle_positions WHERE recorded_at < $1", before)
+	if err != nil {
+		return 0, fault.New("failed to delete vehicle positions", fault.WithError(err))
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,220 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/cache"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geo"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	trackingServiceJourney = "tracking service"
+	// vehicleStateTTL is how long a vehicle stays live after its last position
+	vehicleStateTTL = time.Minute * 2
+	// maxPositionAge rejects positions recorded too long ago to be of any use, as
+	// the ones a device buffers while offline
+	maxPositionAge = time.Minute * 10
+	// maxClockSkew tolerates devices whose clock is slightly ahead of the server
+	maxClockSkew = time.Second * 30
+
+	maxVehicleIDLength = 50
+)
+
+type ServiceConfig struct {
+	PositionRepo Repository
+	RouteService route.Service
+	Cache        *cache.Cache
+}
+
+type service struct {
+	positionRepo Repository
+	routeService route.Service
+	cache        *cache.Cache
+	routeStops   *routeStops
+}
+
+func NewService(c ServiceConfig) Service {
+	return &service{
+		positionRepo: c.PositionRepo,
+		routeService: c.RouteService,
+		cache:        c.Cache,
+		routeStops:   &routeStops{},
+	}
+}
+
+func (s service) ReportPosition(ctx context.Context, input dto.ReportPosition) (*dto.VehiclePositionResponse, error) {
+	if input.VehicleID == "" || input.RouteID == "" {
+		return nil, fault.NewBadRequest("vehicle_id and route_id are required")
+	}
+	if len(input.VehicleID) > maxVehicleIDLength || strings.ContainsAny(input.VehicleID, ":*") {
+		return nil, fault.NewBadRequest("invalid vehicle_id")
+	}
+	if input.Latitude == nil || input.Longitude == nil || input.Timestamp == nil {
+		return nil, fault.NewBadRequest("latitude, longitude and timestamp are required")
+	}
+
+	point := geo.Point{Lat: *input.Latitude, Lon: *input.Longitude}
+	if !point.Valid() {
+		return nil, fault.NewUnprocessableEntity("latitude must be between -90 and 90 and longitude between -180 and 180")
+	}
+	if input.Heading != nil && (*input.Heading < 0 || *input.Heading >= 360) {
+		return nil, fault.NewUnprocessableEntity("heading must be between 0 and 360")
+	}
+
+	now := time.Now()
+	recordedAt := *input.Timestamp
+	if recordedAt.After(now.Add(maxClockSkew)) {
+		return nil, fault.NewUnprocessableEntity("timestamp must not be in the future")
+	}
+	if recordedAt.Before(now.Add(-maxPositionAge)) {
+		return nil, fault.NewUnprocessableEntity(fmt.Sprintf("timestamp must be within the last %d minutes", int(maxPositionAge.Minutes())))
+	}
+
+	stops, err := s.routeStops.get(ctx, s.routeService, input.RouteID)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	previous, err := s.GetVehiclePosition(ctx, input.VehicleID)
+	if err != nil && fault.GetTag(err) != fault.NOT_FOUND {
+		return nil, err // The error is already being handled in GetVehiclePosition
+	}
+
+	// A vehicle moves forward along its route, so the segment it was last seen on
+	// helps placing it where the route passes twice near the same point
+	minOrder := 0
+	if previous != nil && previous.RouteID == input.RouteID && previous.Snap != nil {
+		minOrder = previous.Snap.FromStopOrder
+	}
+
+	res := &dto.VehiclePositionResponse{
+		VehicleID:  input.VehicleID,
+		RouteID:    input.RouteID,
+		Latitude:   point.Lat,
+		Longitude:  point.Lon,
+		Heading:    input.Heading,
+		Snap:       snapToRoute(point, stops, minOrder),
+		RecordedAt: recordedAt,
+	}
+
+	err = s.positionRepo.Insert(ctx, newPositionModel(*res))
+	if err != nil {
+		logging.Error("failed to insert vehicle position", err,
+			zap.String("journey", trackingServiceJourney))
+		return nil, fault.NewBadRequest("failed to report position")
+	}
+
+	// Positions may arrive out of order, an older one only goes to the history
+	if previous != nil && !recordedAt.After(previous.RecordedAt) {
+		return res, nil
+	}
+
+	err = s.cache.SetStruct(ctx, stateKey(input.VehicleID), res, vehicleStateTTL)
+	if err != nil {
+		logging.Error("failed to cache vehicle position", err,
+			zap.String("journey", trackingServiceJourney))
+		return nil, fault.NewBadRequest("failed to report position")
+	}
+	s.publish(ctx, *res)
+
+	return res, nil
+}
+
+func (s service) GetVehiclePositions(ctx context.Context, routeId string) ([]dto.VehiclePositionResponse, error) {
+	if routeId != "" {
+		// Tells apart unknown routes from routes without live vehicles
+		_, err := s.routeStops.get(ctx, s.routeService, routeId)
+		if err != nil {
+			return nil, err // The error is already being handled in the route service
+		}
+	}
+
+	keys, err := s.cache.GetKeys(ctx, stateKey("*"))
+	if err != nil {
+		logging.Error("failed to list vehicles from cache", err,
+			zap.String("journey", trackingServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve vehicle positions")
+	}
+
+	res := make([]dto.VehiclePositionResponse, 0, len(keys))
+	for _, key := range keys {
+		var position dto.VehiclePositionResponse
+		err := s.cache.GetStruct(ctx, key, &position)
+		if err != nil {
+			// The vehicle may have expired since the keys were listed
+			if fault.GetTag(err) == fault.CACHE_MISS {
+				continue
+			}
+			logging.Error("failed to query vehicle position from cache", err,
+				zap.String("journey", trackingServiceJourney))
+			return nil, fault.NewBadRequest("failed to retrieve vehicle positions")
+		}
+		if routeId != "" && position.RouteID != routeId {
+			continue
+		}
+		res = append(res, position)
+	}
+
+	slices.SortFunc(res, func(a, b dto.VehiclePositionResponse) int {
+		return strings.Compare(a.VehicleID, b.VehicleID)
+	})
+
+	return res, nil
+}
+
+func (s service) GetVehiclePosition(ctx context.Context, vehicleId string) (*dto.VehiclePositionResponse, error) {
+	var position dto.VehiclePositionResponse
+	err := s.cache.GetStruct(ctx, stateKey(vehicleId), &position)
+	if err != nil {
+		if fault.GetTag(err) == fault.CACHE_MISS {
+			return nil, fault.NewNotFound("vehicle not live")
+		}
+		logging.Error("failed to query vehicle position from cache", err,
+			zap.String("journey", trackingServiceJourney))
+		return nil, fault.NewBadRequest("failed to retrieve vehicle position")
+	}
+
+	return &position, nil
+}
+
+// publish sends the position to the followers of its route on every instance
+func (s service) publish(ctx context.Context, position dto.VehiclePositionResponse) {
+	err := s.cache.Publish(ctx, liveChannel(position.RouteID), position)
+	if err != nil {
+		logging.Error("failed to publish vehicle position", err,
+			zap.String("journey", trackingServiceJourney))
+	}
+}
+
+func newPositionModel(p dto.VehiclePositionResponse) model.VehiclePosition {
+	m := model.VehiclePosition{
+		VehicleID:  p.VehicleID,
+		RouteID:    p.RouteID,
+		Latitude:   p.Latitude,
+		Longitude:  p.Longitude,
+		Heading:    p.Heading,
+		RecordedAt: p.RecordedAt,
+	}
+	if p.Snap != nil {
+		m.FromStopOrder = &p.Snap.FromStopOrder
+		m.ToStopOrder = &p.Snap.ToStopOrder
+		m.Progress = &p.Snap.Progress
+	}
+
+	return m
+}
+
+// stateKey is the cache key holding the latest position of the vehicle
+func stateKey(vehicleId string) string {
+	return fmt.Sprintf("vehicle:%s", vehicleId)
+}
@@ -0,0 +1,114 @@
+package tracking
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/dto"
+	"github.com/brnocorreia/api-meu-buzufba/internal/modules/route"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geo"
+)
+
+const (
+	// maxOffRouteMeters is how far from its route a vehicle may be and still be placed on it
+	maxOffRouteMeters = 300
+	// snapTolerance lets a vehicle stay on a segment ahead of its last one when another
+	// segment is only slightly closer, as where a route passes twice on the same street
+	snapTolerance = 25
+	// routeStopsMaxAge bounds how long stop changes take to reach the snapping
+	routeStopsMaxAge = time.Minute * 5
+)
+
+// snapStop is a located stop of a route, in stop order
+type snapStop struct {
+	locationId string
+	order      int
+	point      geo.Point
+}
+
+// snapToRoute places the point on the closest segment between two consecutive
+// stops, preferring segments from minOrder on. It returns nil when the route
+// has less than two located stops or the point is too far from all segments.
+func snapToRoute(p geo.Point, stops []snapStop, minOrder int) *dto.VehicleSnapResponse {
+	type candidate struct {
+		from, to snapStop
+		progress float64
+		distance float64
+	}
+
+	var best, ahead *candidate
+	for i := 1; i < len(stops); i++ {
+		progress, distance := geo.Project(p, stops[i-1].point, stops[i].point)
+		c := &candidate{from: stops[i-1], to: stops[i], progress: progress, distance: distance}
+		if best == nil || c.distance < best.distance {
+			best = c
+		}
+		if c.from.order >= minOrder && (ahead == nil || c.distance < ahead.distance) {
+			ahead = c
+		}
+	}
+	if best == nil || best.distance > maxOffRouteMeters {
+		return nil
+	}
+	if ahead != nil && ahead.distance <= best.distance+snapTolerance {
+		best = ahead
+	}
+
+	return &dto.VehicleSnapResponse{
+		FromStopID:    best.from.locationId,
+		FromStopOrder: best.from.order,
+		ToStopID:      best.to.locationId,
+		ToStopOrder:   best.to.order,
+		Progress:      math.Round(best.progress*1000) / 1000,
+		OffsetMeters:  int(math.Round(best.distance)),
+	}
+}
+
+// routeStops keeps the located stops of the routes in memory, since every
+// reported position needs them
+type routeStops struct {
+	mu      sync.Mutex
+	entries map[string]routeStopsEntry
+}
+
+type routeStopsEntry struct {
+	stops    []snapStop
+	loadedAt time.Time
+}
+
+func (rs *routeStops) get(ctx context.Context, routeService route.Service, routeId string) ([]snapStop, error) {
+	rs.mu.Lock()
+	entry, ok := rs.entries[routeId]
+	rs.mu.Unlock()
+	if ok && time.Since(entry.loadedAt) < routeStopsMaxAge {
+		return entry.stops, nil
+	}
+
+	r, err := routeService.GetRouteByID(ctx, routeId)
+	if err != nil {
+		return nil, err // The error is already being handled in the route service
+	}
+
+	stops := make([]snapStop, 0, len(r.Stops))
+	for _, st := range r.Stops {
+		if st.Location.Latitude == nil || st.Location.Longitude == nil {
+			continue
+		}
+		stops = append(stops, snapStop{
+			locationId: st.Location.ID,
+			order:      st.StopOrder,
+			point:      geo.Point{Lat: *st.Location.Latitude, Lon: *st.Location.Longitude},
+		})
+	}
+
+	rs.mu.Lock()
+	if rs.entries == nil {
+		rs.entries = make(map[string]routeStopsEntry)
+	}
+	rs.entries[routeId] = routeStopsEntry{stops: stops, loadedAt: time.Now()}
+	rs.mu.Unlock()
+
+	return stops, nil
+}
@@ -0,0 +1,196 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
+	"github.com/brnocorreia/api-meu-buzufba/pkg/logging"
+	"github.com/coder/websocket"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	// heartbeatInterval keeps idle streams alive through proxies and detects gone clients
+	heartbeatInterval = time.Second * 15
+	// liveWriteTimeout is how long a single write to a client may take
+	liveWriteTimeout = time.Second * 10
+	// sseRetry is how long, in milliseconds, browsers wait before reconnecting a dropped stream
+	sseRetry = 3000
+
+	eventPosition = "position"
+)
+
+// liveMessage is the envelope of the WebSocket messages, matching the SSE events
+type liveMessage struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// handleLiveSSE streams the positions of the route's vehicles as Server-Sent Events.
+// The current positions are sent first, then every new one as it is reported.
+func (h handler) handleLiveSSE(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	routeId := chi.URLParam(r, "routeId")
+
+	snapshot, sub, err := h.subscribe(ctx, routeId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Keeps reverse proxies from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	// write pushes the chunk to the client, extending the server write timeout
+	// which would otherwise end the stream
+	write := func(chunk string) bool {
+		rc.SetWriteDeadline(time.Now().Add(liveWriteTimeout))
+		if _, err := fmt.Fprint(w, chunk); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+
+	initial := fmt.Sprintf("retry: %d\n\n", sseRetry)
+	for _, data := range snapshot {
+		initial += sseEvent(eventPosition, data)
+	}
+	if !write(initial) {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.Done():
+			return
+		case data := <-sub.Messages():
+			if !write(sseEvent(eventPosition, data)) {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(": ping\n\n") {
+				return
+			}
+		}
+	}
+}
+
+// handleLiveWebSocket is the WebSocket equivalent of handleLiveSSE, each message
+// is a JSON object with the event type and its data
+func (h handler) handleLiveWebSocket(w http.ResponseWriter, r *http.Request) {
+	routeId := chi.URLParam(r, "routeId")
+
+	snapshot, sub, err := h.subscribe(r.Context(), routeId)
+	if err != nil {
+		fault.NewHTTPError(w, err)
+		return
+	}
+	defer h.hub.Unsubscribe(sub)
+
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		// Any origin may follow the vehicles, as the CORS policy allows
+		OriginPatterns: []string{"*"},
+	})
+	if err != nil {
+		// Accept already wrote the error response
+		logging.Info("failed to accept websocket",
+			zap.String("journey", trackingHandlerJourney),
+			zap.String("path", r.URL.Path),
+			zap.String("reason", err.Error()))
+		return
+	}
+	defer conn.CloseNow()
+
+	// Clients only listen, CloseRead handles their control frames and
+	// cancels ctx once they go away
+	ctx := conn.CloseRead(context.WithoutCancel(r.Context()))
+
+	write := func(data []byte) bool {
+		msg, err := json.Marshal(liveMessage{Type: eventPosition, Data: data})
+		if err != nil {
+			return false
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, liveWriteTimeout)
+		defer cancel()
+		return conn.Write(ctx, websocket.MessageText, msg) == nil
+	}
+
+	for _, data := range snapshot {
+		if !write(data) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.Done():
+			// The client reconnects, reaching a running instance
+			conn.Close(websocket.StatusTryAgainLater, "reconnect")
+			return
+		case data := <-sub.Messages():
+			if !write(data) {
+				return
+			}
+		case <-heartbeat.C:
+			pingCtx, cancel := context.WithTimeout(ctx, liveWriteTimeout)
+			err := conn.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscribe follows the route before reading its current positions, so no
+// position reported in between is missed
+func (h handler) subscribe(ctx context.Context, routeId string) ([][]byte, *Subscriber, error) {
+	sub, err := h.hub.Subscribe(routeId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	positions, err := h.trackingService.GetVehiclePositions(ctx, routeId)
+	if err != nil {
+		h.hub.Unsubscribe(sub)
+		return nil, nil, err // The error is already being handled in the tracking service
+	}
+
+	snapshot := make([][]byte, 0, len(positions))
+	for _, p := range positions {
+		data, err := json.Marshal(p)
+		if err != nil {
+			h.hub.Unsubscribe(sub)
+			return nil, nil, fault.New("failed to encode vehicle position", fault.WithError(err))
+		}
+		snapshot = append(snapshot, data)
+	}
+
+	return snapshot, sub, nil
+}
+
+func sseEvent(event string, data []byte) string {
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", event, data)
+}
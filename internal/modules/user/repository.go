@@ -31,6 +31,7 @@ func (r repo) Update(ctx context.Context, user model.User) error {
 			email = :email,
 			password = :password,
 			is_ufba = :is_ufba,
+			role = :role,
 			activated = :activated,
 			activated_at = :activated_at,
 			updated_at = :updated_at
@@ -101,6 +102,7 @@ func (r repo) Insert(ctx context.Context, user model.User) error {
 			email,
 			password,
 			is_ufba,
+			role,
 			activated,
 			activated_at,
 			created_at,
@@ -112,6 +114,7 @@ func (r repo) Insert(ctx context.Context, user model.User) error {
 			:email,
 			:password,
 			:is_ufba,
+			:role,
 			:activated,
 			:activated_at,
 			:created_at,
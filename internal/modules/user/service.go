@@ -45,6 +45,7 @@ func (s service) GetUserByEmail(ctx context.Context, email string) (*dto.UserRes
 		Username:    userRecord.Username,
 		Email:       userRecord.Email,
 		IsUfba:      userRecord.IsUfba,
+		Role:        userRecord.Role,
 		Activated:   userRecord.Activated,
 		ActivatedAt: userRecord.ActivatedAt,
 		CreatedAt:   userRecord.CreatedAt,
@@ -73,6 +74,7 @@ func (s service) GetUserByID(ctx context.Context, userId string) (*dto.UserRespo
 		Username:    userRecord.Username,
 		Email:       userRecord.Email,
 		IsUfba:      userRecord.IsUfba,
+		Role:        userRecord.Role,
 		Activated:   userRecord.Activated,
 		ActivatedAt: userRecord.ActivatedAt,
 		CreatedAt:   userRecord.CreatedAt,
@@ -3,6 +3,7 @@ package user
 import (
 	"time"
 
+	"github.com/brnocorreia/api-meu-buzufba/internal/common/role"
 	"github.com/brnocorreia/api-meu-buzufba/internal/infra/database/model"
 	"github.com/brnocorreia/api-meu-buzufba/pkg/crypto"
 	"github.com/brnocorreia/api-meu-buzufba/pkg/fault"
@@ -16,6 +17,7 @@ type user struct {
 	email        string
 	password     string
 	is_ufba      bool
+	role         string
 	activated    bool
 	activated_at *time.Time
 	created_at   time.Time
@@ -30,6 +32,7 @@ func NewFromModel(m model.User) *user {
 		email:        m.Email,
 		password:     m.Password,
 		is_ufba:      m.IsUfba,
+		role:         m.Role,
 		activated:    m.Activated,
 		activated_at: m.ActivatedAt,
 		created_at:   m.CreatedAt,
@@ -50,6 +53,7 @@ func New(name, username, email, pass string, isUfba bool) (*user, error) {
 		email:        email,
 		password:     hashedPass,
 		is_ufba:      isUfba,
+		role:         role.User,
 		activated:    false,
 		activated_at: nil,
 		created_at:   time.Now(),
@@ -73,6 +77,41 @@ func (u *user) Activate() {
 	u.activated_at = &now
 }
 
+// ChangePassword replaces the password with the hash of the given one
+func (u *user) ChangePassword(pass string) error {
+	if pass == "" {
+		return fault.New("password is required")
+	}
+
+	hashedPass, err := crypto.HashPassword(pass)
+	if err != nil {
+		return fault.New("failed to hash password", fault.WithError(err))
+	}
+	if hashedPass == "" {
+		return fault.New("failed to hash password")
+	}
+
+	u.password = hashedPass
+	u.updated_at = time.Now()
+	return nil
+}
+
+// ChangeEmail moves the user to a confirmed new email. Confirming it proves the
+// user owns the address, so an inactive account is activated too.
+func (u *user) ChangeEmail(email string, isUfba bool) error {
+	if email == "" {
+		return fault.New("email is required")
+	}
+
+	u.email = email
+	u.is_ufba = isUfba
+	if !u.activated {
+		u.Activate()
+	}
+	u.updated_at = time.Now()
+	return nil
+}
+
 func (u *user) Model() model.User {
 	return model.User{
 		ID:          u.id,
@@ -81,6 +120,7 @@ func (u *user) Model() model.User {
 		Email:       u.email,
 		Password:    u.password,
 		IsUfba:      u.is_ufba,
+		Role:        u.role,
 		Activated:   u.activated,
 		ActivatedAt: u.activated_at,
 		CreatedAt:   u.created_at,
@@ -101,6 +141,9 @@ func (u *user) validate() error {
 	if u.username == "" {
 		return fault.New("username is required")
 	}
+	if u.role == "" {
+		return fault.New("role is required")
+	}
 
 	return nil
 }
@@ -90,6 +90,23 @@ func (c *Cache) SetStruct(ctx context.Context, key string, data any, ttl time.Du
 	return c.set(ctx, key, s, ttl)
 }
 
+// SetNX sets the key only when it does not exist yet, reporting whether it was set.
+// It is meant for claims such as idempotency keys, where only the first caller may proceed.
+//
+// Example:
+//
+//	ok, err := cache.SetNX(ctx, "job:1", "done", time.Hour)
+//	if err != nil {...}
+//	if !ok {...} // Someone else already claimed the key
+func (c *Cache) SetNX(ctx context.Context, key string, data string, ttl time.Duration) (bool, error) {
+	ok, err := c.redis.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		return false, fault.New("failed to set value in cache", fault.WithError(err))
+	}
+
+	return ok, nil
+}
+
 // Has checks if a key exists in the cache
 func (c *Cache) Has(ctx context.Context, key string) (bool, error) {
 	exists, err := c.redis.Exists(ctx, key).Result()
@@ -100,6 +117,33 @@ func (c *Cache) Has(ctx context.Context, key string) (bool, error) {
 	return exists > 0, nil
 }
 
+// Publish sends the data to every subscriber of the channel, on any instance
+func (c *Cache) Publish(ctx context.Context, channel string, data any) error {
+	val, err := json.Marshal(data)
+	if err != nil {
+		return fault.New("failed to marshal data", fault.WithError(err))
+	}
+
+	err = c.redis.Publish(ctx, channel, val).Err()
+	if err != nil {
+		return fault.New("failed to publish message", fault.WithError(err))
+	}
+
+	return nil
+}
+
+// PSubscribe subscribes to the channels matching the pattern. The subscription
+// reconnects on its own and must be closed once no longer needed.
+//
+// Example:
+//
+//	sub := cache.PSubscribe(ctx, "live:*")
+//	defer sub.Close()
+//	for msg := range sub.Channel() {...}
+func (c *Cache) PSubscribe(ctx context.Context, pattern string) *redis.PubSub {
+	return c.redis.PSubscribe(ctx, pattern)
+}
+
 // get is a helper function that gets a value from the cache
 func (c *Cache) get(ctx context.Context, key string) ([]byte, error) {
 	val, err := c.redis.Get(ctx, key).Bytes()
@@ -1,14 +1,22 @@
 package crypto
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+// MaxPasswordLength is the longest password bcrypt accepts, in bytes
+const MaxPasswordLength = 72
+
 // HashPassword encrypts a password using bcrypt
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return "", nil
+		return "", err
 	}
 	return string(hashedPassword), nil
 }
@@ -19,3 +27,19 @@ func PasswordMatches(password, encrypted string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(encrypted), []byte(password))
 	return err == nil
 }
+
+// GenerateToken returns a random URL-safe token with 256 bits of entropy
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex SHA-256 of a token. Tokens are random enough that a
+// fast hash is safe, and it allows looking the token up by its hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
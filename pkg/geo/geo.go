@@ -0,0 +1,37 @@
+package geo
+
+import (
+	"math"
+)
+
+// earthRadius is the mean radius of the Earth in meters
+const earthRadius = 6_371_000
+
+// Point is a WGS84 coordinate in decimal degrees
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Valid reports whether the point is a real coordinate
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180 &&
+		!math.IsNaN(p.Lat) && !math.IsNaN(p.Lon)
+}
+
+// Distance returns the great-circle distance between two points in meters,
+// using the haversine formula
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLon := radians(b.Lon - a.Lon)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
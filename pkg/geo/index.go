@@ -0,0 +1,94 @@
+package geo
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// cellSize is the side of a grid cell in degrees, roughly 550m at the equator
+const cellSize = 0.005
+
+// metersPerDegree is the length of a degree of latitude
+const metersPerDegree = 111_320
+
+// Entry is an identified point stored in an Index
+type Entry struct {
+	ID    string
+	Point Point
+}
+
+// Match is an entry found by a search, with its distance in meters to the searched point
+type Match struct {
+	Entry
+	Distance float64
+}
+
+type cell struct {
+	lat, lon int
+}
+
+// Index is an immutable grid of points answering radius searches
+// without scanning every entry. Build a new one whenever the points change.
+type Index struct {
+	cells map[cell][]Entry
+	size  int
+}
+
+// NewIndex builds an index of the entries, ignoring the ones with invalid points
+func NewIndex(entries []Entry) *Index {
+	idx := &Index{cells: make(map[cell][]Entry)}
+	for _, e := range entries {
+		if !e.Point.Valid() {
+			continue
+		}
+		c := cellOf(e.Point)
+		idx.cells[c] = append(idx.cells[c], e)
+		idx.size++
+	}
+	return idx
+}
+
+// Len returns how many entries the index holds
+func (idx *Index) Len() int {
+	return idx.size
+}
+
+// Nearby returns the entries within radius meters of p, closest first
+func (idx *Index) Nearby(p Point, radius float64) []Match {
+	res := make([]Match, 0)
+	if !p.Valid() || radius <= 0 {
+		return res
+	}
+
+	latSpan := radius / metersPerDegree
+	// Longitude degrees shrink towards the poles, clamp to avoid dividing by zero
+	lonSpan := radius / (metersPerDegree * math.Max(math.Cos(radians(p.Lat)), 0.01))
+
+	minCell := cellOf(Point{Lat: p.Lat - latSpan, Lon: p.Lon - lonSpan})
+	maxCell := cellOf(Point{Lat: p.Lat + latSpan, Lon: p.Lon + lonSpan})
+
+	for lat := minCell.lat; lat <= maxCell.lat; lat++ {
+		for lon := minCell.lon; lon <= maxCell.lon; lon++ {
+			for _, e := range idx.cells[cell{lat: lat, lon: lon}] {
+				d := Distance(p, e.Point)
+				if d <= radius {
+					res = append(res, Match{Entry: e, Distance: d})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(res, func(a, b Match) int {
+		return cmp.Compare(a.Distance, b.Distance)
+	})
+
+	return res
+}
+
+func cellOf(p Point) cell {
+	return cell{
+		lat: int(math.Floor(p.Lat / cellSize)),
+		lon: int(math.Floor(p.Lon / cellSize)),
+	}
+}
@@ -0,0 +1,27 @@
+package geo
+
+import (
+	"math"
+)
+
+// Project finds the point of the segment from a to b closest to p. It returns
+// how far along the segment that point is, from 0 at a to 1 at b, and its
+// distance in meters to p.
+//
+// The segment is flattened around p, which is accurate for the few hundred
+// meters between bus stops.
+func Project(p, a, b Point) (float64, float64) {
+	// Local planar coordinates in meters, with p at the origin
+	scale := math.Cos(radians(p.Lat))
+	ax, ay := (a.Lon-p.Lon)*metersPerDegree*scale, (a.Lat-p.Lat)*metersPerDegree
+	bx, by := (b.Lon-p.Lon)*metersPerDegree*scale, (b.Lat-p.Lat)*metersPerDegree
+
+	dx, dy := bx-ax, by-ay
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return 0, math.Hypot(ax, ay)
+	}
+
+	t := math.Max(0, math.Min(1, -(ax*dx+ay*dy)/length))
+	return t, math.Hypot(ax+t*dx, ay+t*dy)
+}
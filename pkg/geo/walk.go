@@ -0,0 +1,26 @@
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// WalkingSpeed is an average walking pace in meters per second, about 4.7 km/h
+	WalkingSpeed = 1.3
+	// detourFactor accounts for streets rarely following the straight line between two points
+	detourFactor = 1.3
+)
+
+// WalkingDistance estimates the distance walked on the streets, in meters,
+// between two points a straight distance apart
+func WalkingDistance(straight float64) float64 {
+	return straight * detourFactor
+}
+
+// WalkingDuration estimates how long it takes to walk between two points
+// a straight distance apart, rounded up to the minute
+func WalkingDuration(straight float64) time.Duration {
+	seconds := WalkingDistance(straight) / WalkingSpeed
+	return time.Duration(math.Ceil(seconds/60)) * time.Minute
+}
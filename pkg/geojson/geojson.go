@@ -0,0 +1,124 @@
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geo"
+)
+
+// ContentType is the media type of GeoJSON documents, see RFC 7946
+const ContentType = "application/geo+json"
+
+const (
+	TypePoint             = "Point"
+	TypeLineString        = "LineString"
+	TypeFeature           = "Feature"
+	TypeFeatureCollection = "FeatureCollection"
+)
+
+// Position is a coordinate as GeoJSON lays it out, longitude first
+type Position [2]float64
+
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+type Feature struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id,omitempty"`
+	Geometry   *Geometry      `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+func NewPoint(p geo.Point) *Geometry {
+	coordinates, _ := json.Marshal(Position{p.Lon, p.Lat})
+	return &Geometry{Type: TypePoint, Coordinates: coordinates}
+}
+
+func NewLineString(points []geo.Point) *Geometry {
+	positions := make([]Position, len(points))
+	for i, p := range points {
+		positions[i] = Position{p.Lon, p.Lat}
+	}
+	coordinates, _ := json.Marshal(positions)
+	return &Geometry{Type: TypeLineString, Coordinates: coordinates}
+}
+
+func NewFeature(id string, geometry *Geometry, properties map[string]any) Feature {
+	if properties == nil {
+		properties = make(map[string]any)
+	}
+	return Feature{Type: TypeFeature, ID: id, Geometry: geometry, Properties: properties}
+}
+
+func NewFeatureCollection(features []Feature) *FeatureCollection {
+	if features == nil {
+		features = make([]Feature, 0)
+	}
+	return &FeatureCollection{Type: TypeFeatureCollection, Features: features}
+}
+
+// ParseLineString reads the points of a LineString given on its own, as a
+// Feature or as a FeatureCollection holding a single LineString feature
+func ParseLineString(data []byte) ([]geo.Point, error) {
+	var object struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+		Geometry    *Geometry       `json:"geometry"`
+		Features    []Feature       `json:"features"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("invalid geojson: %w", err)
+	}
+
+	var geometry *Geometry
+	switch object.Type {
+	case TypeLineString:
+		geometry = &Geometry{Type: object.Type, Coordinates: object.Coordinates}
+	case TypeFeature:
+		geometry = object.Geometry
+	case TypeFeatureCollection:
+		for _, f := range object.Features {
+			if f.Geometry == nil || f.Geometry.Type != TypeLineString {
+				continue
+			}
+			if geometry != nil {
+				return nil, errors.New("feature collection must hold a single linestring")
+			}
+			geometry = f.Geometry
+		}
+	default:
+		return nil, fmt.Errorf("unsupported geojson type %q", object.Type)
+	}
+
+	if geometry == nil || geometry.Type != TypeLineString {
+		return nil, errors.New("geojson must hold a linestring")
+	}
+
+	var positions [][]float64
+	if err := json.Unmarshal(geometry.Coordinates, &positions); err != nil {
+		return nil, fmt.Errorf("invalid linestring coordinates: %w", err)
+	}
+
+	points := make([]geo.Point, len(positions))
+	for i, pos := range positions {
+		// Altitude, when given, is left out
+		if len(pos) < 2 {
+			return nil, fmt.Errorf("position %d must have longitude and latitude", i)
+		}
+		points[i] = geo.Point{Lat: pos[1], Lon: pos[0]}
+		if !points[i].Valid() {
+			return nil, fmt.Errorf("position %d is not a valid coordinate", i)
+		}
+	}
+
+	return points, nil
+}
@@ -0,0 +1,84 @@
+package polyline
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/brnocorreia/api-meu-buzufba/pkg/geo"
+)
+
+// precision is the number of decimal places kept, about one meter at precision 5
+const precision = 1e5
+
+var ErrInvalid = errors.New("invalid encoded polyline")
+
+// Encode encodes the points with the Google encoded polyline algorithm
+func Encode(points []geo.Point) string {
+	var sb strings.Builder
+	var prevLat, prevLon int64
+	for _, p := range points {
+		lat := int64(math.Round(p.Lat * precision))
+		lon := int64(math.Round(p.Lon * precision))
+		encodeValue(&sb, lat-prevLat)
+		encodeValue(&sb, lon-prevLon)
+		prevLat, prevLon = lat, lon
+	}
+	return sb.String()
+}
+
+// Decode decodes a polyline built by Encode
+func Decode(s string) ([]geo.Point, error) {
+	points := make([]geo.Point, 0, len(s)/4)
+	var lat, lon int64
+	for i := 0; i < len(s); {
+		dLat, n, err := decodeValue(s[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += n
+
+		dLon, n, err := decodeValue(s[i:])
+		if err != nil {
+			return nil, err
+		}
+		i += n
+
+		lat += dLat
+		lon += dLon
+		points = append(points, geo.Point{Lat: float64(lat) / precision, Lon: float64(lon) / precision})
+	}
+	return points, nil
+}
+
+func encodeValue(sb *strings.Builder, v int64) {
+	u := uint64(v) << 1
+	if v < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		sb.WriteByte(byte(0x20|u&0x1f) + 63)
+		u >>= 5
+	}
+	sb.WriteByte(byte(u) + 63)
+}
+
+// decodeValue reads a single value, returning it and how many bytes it takes
+func decodeValue(s string) (int64, int, error) {
+	var u uint64
+	for i := 0; i < len(s); i++ {
+		c := int(s[i]) - 63
+		if c < 0 || c > 0x3f || i >= 12 {
+			return 0, 0, ErrInvalid
+		}
+		u |= uint64(c&0x1f) << (5 * i)
+		if c < 0x20 {
+			v := int64(u >> 1)
+			if u&1 != 0 {
+				v = ^v
+			}
+			return v, i + 1, nil
+		}
+	}
+	return 0, 0, ErrInvalid
+}
@@ -0,0 +1,144 @@
+package timeofday
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const minutesPerDay = 24 * 60
+
+// TimeOfDay is a wall clock time without a date, stored as minutes since midnight
+//
+// Example:
+//
+//	t, _ := timeofday.New(6, 30)
+//	fmt.Println(t) // Output: 06:30
+type TimeOfDay int
+
+// New returns the TimeOfDay for the given hour and minute
+func New(hour, minute int) (TimeOfDay, error) {
+	if hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("invalid hour %d", hour)
+	}
+	if minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid minute %d", minute)
+	}
+
+	return TimeOfDay(hour*60 + minute), nil
+}
+
+// Parse parses a time of day written as "HH:MM", "HH:MM:SS" or in the
+// brazilian "6h30" / "6h" notation used by the official timetables.
+// Seconds are truncated.
+//
+// Example:
+//
+//	t, _ := timeofday.Parse("6h30")  // 06:30
+//	t, _ = timeofday.Parse("13:30")  // 13:30
+func Parse(s string) (TimeOfDay, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+
+	var parts []string
+	switch {
+	case strings.Contains(v, "h"):
+		parts = strings.SplitN(v, "h", 2)
+		if parts[1] == "" {
+			parts[1] = "0"
+		}
+	case strings.Contains(v, ":"):
+		parts = strings.Split(v, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("invalid time of day %q", s)
+		}
+	default:
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+
+	return New(hour, minute)
+}
+
+// FromTime returns the time of day of t in its own location
+func FromTime(t time.Time) TimeOfDay {
+	return TimeOfDay(t.Hour()*60 + t.Minute())
+}
+
+func (t TimeOfDay) Hour() int   { return int(t) / 60 }
+func (t TimeOfDay) Minute() int { return int(t) % 60 }
+
+// Minutes returns the number of minutes since midnight
+func (t TimeOfDay) Minutes() int { return int(t) }
+
+// On returns the instant of t at the given date, in the date's location
+func (t TimeOfDay) On(date time.Time) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, date.Location())
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour(), t.Minute())
+}
+
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*t = v
+
+	return nil
+}
+
+// Scan implements sql.Scanner so TIME columns can be read directly
+func (t *TimeOfDay) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return t.scanString(string(v))
+	case string:
+		return t.scanString(v)
+	case time.Time:
+		*t = FromTime(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into TimeOfDay", src)
+	}
+}
+
+// Value implements driver.Valuer so TimeOfDay can be written to TIME columns
+func (t TimeOfDay) Value() (driver.Value, error) {
+	if t < 0 || t >= minutesPerDay {
+		return nil, fmt.Errorf("invalid time of day %d", int(t))
+	}
+	return fmt.Sprintf("%02d:%02d:00", t.Hour(), t.Minute()), nil
+}
+
+func (t *TimeOfDay) scanString(s string) error {
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*t = v
+
+	return nil
+}